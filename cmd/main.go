@@ -11,8 +11,8 @@ import (
 	"time"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app"
-	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/firebase"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/migrations"
 )
 
 func main() {
@@ -30,13 +30,33 @@ func main() {
 	}
 	defer db.Close()
 
-	firebaseBucket, err := firebase.InitFirebaseStorage(ctx, cfg)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = migrations.RunCommand(ctx, db, os.Args[2:])
+		if err != nil {
+			slog.Error("failed to run migrations", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	dependencies, err := app.InitDependencies(ctx, cfg, db)
 	if err != nil {
-		slog.Error("failed to connect to firebase storage", "error", err)
+		slog.Error("failed to initialize dependencies", "error", err)
 		return
 	}
 
-	dependencies := app.InitDependencies(db, firebaseBucket)
+	if len(os.Args) > 1 {
+		err = app.RunCommand(ctx, dependencies, os.Args[1:])
+		if err != nil {
+			slog.Error("failed to run command", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	waitForWorkers := app.StartWorkers(workerCtx, dependencies)
 
 	router := app.NewRouter(dependencies)
 
@@ -76,5 +96,8 @@ func main() {
 		slog.Error("cannot shut HTTP server down gracefully", "error", err)
 	}
 
+	stopWorkers()
+	waitForWorkers()
+
 	slog.Info("server shutdown successfully")
 }
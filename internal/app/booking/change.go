@@ -0,0 +1,365 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+// ExtendBooking asks the host to push the dropoff time back. The slot is checked now and again
+// when the host approves, as it is not held while the request is pending.
+func (s *service) ExtendBooking(ctx context.Context, bookingId int, extendData ExtendBookingRequestBody) (changeRequest BookingChangeRequest, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	if booking.SeekerId != userId {
+		slog.Error("invalid booking extension attempt")
+		return BookingChangeRequest{}, apperrors.ErrActionForbidden
+	}
+
+	if booking.Status != Scheduled && booking.Status != CheckedOut {
+		slog.Error("booking cannot be extended in its current status", "status", booking.Status)
+		return BookingChangeRequest{}, apperrors.ErrBookingNotChangeable
+	}
+
+	err = extendData.validate(booking)
+	if err != nil {
+		slog.Error("booking extension validation failed", "error", err)
+		return BookingChangeRequest{}, apperrors.ErrInvalidRequestBody
+	}
+
+	newAmount, err := s.scheduleAmount(ctx, booking, booking.ScheduledPickupTime, extendData.ScheduledDropoffTime)
+	if err != nil {
+		return BookingChangeRequest{}, err
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start booking extension", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	err = s.bookingRepository.VehicleBookingConflictCheck(ctx, tx, booking.VehicleId, booking.ScheduledPickupTime, extendData.ScheduledDropoffTime, s.availabilityCfg.BookingBuffer, booking.Id)
+	if err != nil {
+		slog.Error("failed to check booking extension availability", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	newChangeRequest, err := s.bookingRepository.CreateBookingChangeRequest(ctx, tx, repository.CreateBookingChangeRequestData{
+		BookingId:           booking.Id,
+		Type:                ChangeExtension,
+		Status:              ChangePending,
+		PreviousPickupTime:  booking.ScheduledPickupTime,
+		PreviousDropoffTime: booking.ScheduledDropoffTime,
+		PreviousAmount:      booking.BookingAmount,
+		NewPickupTime:       booking.ScheduledPickupTime,
+		NewDropoffTime:      extendData.ScheduledDropoffTime,
+		NewAmount:           newAmount,
+		RequestedBy:         userId,
+		Reason:              strings.TrimSpace(extendData.Reason),
+	})
+	if err != nil {
+		slog.Error("failed to create booking extension request", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	return BookingChangeRequest(newChangeRequest), nil
+}
+
+// RescheduleBooking moves a booking that has not been picked up yet. It does not need the
+// host's approval and is applied right away.
+func (s *service) RescheduleBooking(ctx context.Context, bookingId int, rescheduleData RescheduleBookingRequestBody) (changeRequest BookingChangeRequest, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	if booking.SeekerId != userId {
+		slog.Error("invalid booking reschedule attempt")
+		return BookingChangeRequest{}, apperrors.ErrActionForbidden
+	}
+
+	if booking.Status != Scheduled {
+		slog.Error("booking cannot be rescheduled in its current status", "status", booking.Status)
+		return BookingChangeRequest{}, apperrors.ErrBookingNotChangeable
+	}
+
+	err = rescheduleData.validate()
+	if err != nil {
+		slog.Error("booking reschedule validation failed", "error", err)
+		return BookingChangeRequest{}, apperrors.ErrInvalidRequestBody
+	}
+
+	newAmount, err := s.scheduleAmount(ctx, booking, rescheduleData.ScheduledPickupTime, rescheduleData.ScheduledDropoffTime)
+	if err != nil {
+		return BookingChangeRequest{}, err
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start booking reschedule", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	changeData := repository.CreateBookingChangeRequestData{
+		BookingId:           booking.Id,
+		Type:                ChangeReschedule,
+		Status:              ChangeApproved,
+		PreviousPickupTime:  booking.ScheduledPickupTime,
+		PreviousDropoffTime: booking.ScheduledDropoffTime,
+		PreviousAmount:      booking.BookingAmount,
+		NewPickupTime:       rescheduleData.ScheduledPickupTime,
+		NewDropoffTime:      rescheduleData.ScheduledDropoffTime,
+		NewAmount:           newAmount,
+		RequestedBy:         userId,
+		DecidedBy:           &userId,
+		Reason:              strings.TrimSpace(rescheduleData.Reason),
+	}
+	// A pending extension was made against the old schedule and could no longer be approved.
+	err = s.declinePendingChanges(ctx, tx, booking.Id, "superseded by a reschedule")
+	if err != nil {
+		return BookingChangeRequest{}, err
+	}
+
+	err = s.applyScheduleChange(ctx, tx, booking, changeData)
+	if err != nil {
+		return BookingChangeRequest{}, err
+	}
+
+	newChangeRequest, err := s.bookingRepository.CreateBookingChangeRequest(ctx, tx, changeData)
+	if err != nil {
+		slog.Error("failed to record booking reschedule", "error", err)
+		return BookingChangeRequest{}, err
+	}
+
+	return BookingChangeRequest(newChangeRequest), nil
+}
+
+// ApproveBookingChange applies a pending extension. The new schedule only applies if the
+// booking still has the schedule the request was made against.
+func (s *service) ApproveBookingChange(ctx context.Context, bookingId, requestId int, decisionData DecideBookingChangeRequestBody) (err error) {
+	booking, changeRequest, err := s.pendingBookingChange(ctx, bookingId, requestId)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start booking change approval", "error", err)
+		return err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	err = s.bookingRepository.DecideBookingChangeRequest(ctx, tx, changeRequest.Id, ChangeApproved, booking.HostId, strings.TrimSpace(decisionData.Reason))
+	if err != nil {
+		slog.Error("failed to approve booking change request", "error", err)
+		return err
+	}
+
+	err = s.applyScheduleChange(ctx, tx, booking, repository.CreateBookingChangeRequestData{
+		PreviousPickupTime:  changeRequest.PreviousPickupTime,
+		PreviousDropoffTime: changeRequest.PreviousDropoffTime,
+		NewPickupTime:       changeRequest.NewPickupTime,
+		NewDropoffTime:      changeRequest.NewDropoffTime,
+		NewAmount:           changeRequest.NewAmount,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeclineBookingChange(ctx context.Context, bookingId, requestId int, decisionData DecideBookingChangeRequestBody) error {
+	booking, changeRequest, err := s.pendingBookingChange(ctx, bookingId, requestId)
+	if err != nil {
+		return err
+	}
+
+	err = s.bookingRepository.DecideBookingChangeRequest(ctx, nil, changeRequest.Id, ChangeDeclined, booking.HostId, strings.TrimSpace(decisionData.Reason))
+	if err != nil {
+		slog.Error("failed to decline booking change request", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetBookingChangeRequests(ctx context.Context, bookingId int) (changeRequests []BookingChangeRequest, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return []BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking", "error", err)
+		return []BookingChangeRequest{}, err
+	}
+
+	_, err = bookingActor(booking, userId)
+	if err != nil {
+		slog.Error("unauthorized booking change requests access attempt")
+		return []BookingChangeRequest{}, err
+	}
+
+	changeRequestList, err := s.bookingRepository.GetBookingChangeRequests(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking change requests", "error", err)
+		return []BookingChangeRequest{}, err
+	}
+
+	changeRequests = make([]BookingChangeRequest, len(changeRequestList))
+	for i, changeRequest := range changeRequestList {
+		changeRequests[i] = BookingChangeRequest(changeRequest)
+	}
+
+	return changeRequests, nil
+}
+
+// pendingBookingChange loads a change request of a booking hosted by the current user that is
+// still waiting for a decision.
+func (s *service) pendingBookingChange(ctx context.Context, bookingId, requestId int) (repository.Booking, repository.BookingChangeRequest, error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return repository.Booking{}, repository.BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking", "error", err)
+		return repository.Booking{}, repository.BookingChangeRequest{}, err
+	}
+
+	if booking.HostId != userId {
+		slog.Error("invalid booking change decision attempt")
+		return repository.Booking{}, repository.BookingChangeRequest{}, apperrors.ErrActionForbidden
+	}
+
+	changeRequest, err := s.bookingRepository.GetBookingChangeRequestById(ctx, nil, requestId)
+	if err != nil {
+		slog.Error("failed to get booking change request", "error", err)
+		return repository.Booking{}, repository.BookingChangeRequest{}, err
+	}
+
+	if changeRequest.BookingId != booking.Id {
+		slog.Error("booking change request belongs to another booking", "bookingId", booking.Id, "requestId", requestId)
+		return repository.Booking{}, repository.BookingChangeRequest{}, apperrors.ErrBookingChangeNotFound
+	}
+
+	if changeRequest.Status != ChangePending {
+		slog.Error("booking change request is already decided", "requestId", requestId, "status", changeRequest.Status)
+		return repository.Booking{}, repository.BookingChangeRequest{}, apperrors.ErrBookingChangeDecided
+	}
+
+	return booking, changeRequest, nil
+}
+
+// applyScheduleChange re-checks the new slot, excluding the booking itself, and moves the
+// booking to it. The checkout otp expires at the scheduled dropoff, so it follows along.
+func (s *service) applyScheduleChange(ctx context.Context, tx *sql.Tx, booking repository.Booking, changeData repository.CreateBookingChangeRequestData) error {
+	err := s.bookingRepository.VehicleBookingConflictCheck(ctx, tx, booking.VehicleId, changeData.NewPickupTime, changeData.NewDropoffTime, s.availabilityCfg.BookingBuffer, booking.Id)
+	if err != nil {
+		slog.Error("failed to check booking change availability", "error", err)
+		return err
+	}
+
+	err = s.bookingRepository.UpdateBookingSchedule(ctx, tx, repository.BookingScheduleUpdate{
+		BookingId:           booking.Id,
+		PreviousPickupTime:  changeData.PreviousPickupTime,
+		PreviousDropoffTime: changeData.PreviousDropoffTime,
+		PickupTime:          changeData.NewPickupTime,
+		DropoffTime:         changeData.NewDropoffTime,
+		BookingAmount:       changeData.NewAmount,
+	})
+	if err != nil {
+		slog.Error("failed to update booking schedule", "error", err)
+		return err
+	}
+
+	err = s.bookingRepository.UpdateOtpTokenExpiry(ctx, tx, booking.Id, changeData.PreviousDropoffTime, changeData.NewDropoffTime)
+	if err != nil {
+		slog.Error("failed to move checkout otp expiry", "error", err)
+		return err
+	}
+
+	err = s.bookingRepository.CancelBookingReminders(ctx, tx, booking.Id, []string{ReminderPickup, ReminderDropoff})
+	if err != nil {
+		slog.Error("failed to cancel booking reminders", "error", err)
+		return err
+	}
+
+	booking.ScheduledPickupTime = changeData.NewPickupTime
+	booking.ScheduledDropoffTime = changeData.NewDropoffTime
+	return s.scheduleReminders(ctx, tx, booking)
+}
+
+func (s *service) declinePendingChanges(ctx context.Context, tx *sql.Tx, bookingId int, reason string) error {
+	err := s.bookingRepository.DeclinePendingBookingChangeRequests(ctx, tx, bookingId, reason)
+	if err != nil {
+		slog.Error("failed to decline pending booking changes", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// closeBookingChanges declines a pending extension once the booking has ended, so that it
+// does not linger as pending forever.
+func (s *service) closeBookingChanges(ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error {
+	return s.declinePendingChanges(ctx, tx, booking.Id, "booking was "+strings.ToLower(booking.Status))
+}
+
+func (s *service) scheduleAmount(ctx context.Context, booking repository.Booking, pickupTime, dropoffTime time.Time) (float64, error) {
+	ratePerHour, err := s.vehicleService.GetVehicleRatePerHour(ctx, booking.VehicleId)
+	if err != nil {
+		slog.Error("failed to retrieve vehicle rate", "error", err)
+		return 0, err
+	}
+
+	return bookingAmount(pickupTime, dropoffTime, ratePerHour), nil
+}
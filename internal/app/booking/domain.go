@@ -3,10 +3,10 @@ package booking
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
 )
 
@@ -17,15 +17,58 @@ const (
 	Returned   = "RETURNED"
 	Cancelled  = "CANCELLED"
 
+	// Booking status transition actors
+	ActorHost   = "HOST"
+	ActorSeeker = "SEEKER"
+	ActorSystem = "SYSTEM"
+
+	// Booking change request types
+	ChangeExtension  = "EXTENSION"
+	ChangeReschedule = "RESCHEDULE"
+
+	// Booking change request statuses
+	ChangePending  = "PENDING"
+	ChangeApproved = "APPROVED"
+	ChangeDeclined = "DECLINED"
+
+	// Booking reminder kinds
+	ReminderPickup  = "PICKUP"
+	ReminderDropoff = "DROPOFF"
+
+	// Booking reminder statuses
+	ReminderSent      = "SENT"
+	ReminderCancelled = "CANCELLED"
+
+	// OTP purposes
+	OtpPickup = "PICKUP"
+	OtpReturn = "RETURN"
+
 	// Tax rate
 	taxRate = 0.18
-)
 
-const (
-	checkoutOtpEmailContent       = "Hello %s,\n\nThank you for choosing Wheelio! To proceed with your vehicle checkout, please provide the following OTP to the vehicle owner:\n\nOTP: %s\n\nEnsure you share this OTP with the owner before the expiration time to complete the rental process.\n\nBest regards,\nThe Wheelio Team"
-	initiateReturnOtpEmailContent = "Hello %s,\n\nThank you for choosing Wheelio! To proceed with your vehicle return, please provide the following OTP to the vehicle seeker:\n\nOTP: %s\n\nThis OTP will expire in 20 minutes.\n\nEnsure you share this OTP with the seeker before the expiration time to complete the vehicle return process.\n\nBest regards,\nThe Wheelio Team"
+	// Time to live constants
+	returnOtpTTL = time.Minute * 20
 )
 
+var AvailableBookingStatuses = map[string]struct{}{
+	Scheduled:  {},
+	CheckedOut: {},
+	Returned:   {},
+	Cancelled:  {},
+}
+
+var AvailableBookingSorts = map[string]struct{}{
+	repository.BookingSortNewest:     {},
+	repository.BookingSortOldest:     {},
+	repository.BookingSortPickupAsc:  {},
+	repository.BookingSortPickupDesc: {},
+}
+
+var AvailableBookingTimeFields = map[string]struct{}{
+	repository.BookingTimePickup:  {},
+	repository.BookingTimeDropoff: {},
+}
+
 type Booking struct {
 	Id                    int        `json:"id"`
 	VehicleId             int        `json:"vehicleId"`
@@ -37,12 +80,15 @@ type Booking struct {
 	BookingAmount         float64    `json:"bookingAmount"`
 	OverdueFeeRatePerHour float64    `json:"overdueFeeRatePerHour"`
 	CancellationAllowed   bool       `json:"cancellationAllowed"`
+	CancellationPolicy    string     `json:"cancellationPolicy"`
 	ActualPickupTime      *time.Time `json:"actualPickupTime,omitempty"`
 	ActualDropoffTime     *time.Time `json:"actualDropoffTime,omitempty"`
 	ScheduledPickupTime   time.Time  `json:"scheduledPickupTime"`
 	ScheduledDropoffTime  time.Time  `json:"scheduledDropoffTime"`
 	CreatedAt             time.Time  `json:"createdAt"`
 	UpdatedAt             time.Time  `json:"updatedAt"`
+	NoShowAt              *time.Time `json:"noShowAt,omitempty"`
+	OverdueAt             *time.Time `json:"overdueAt,omitempty"`
 }
 
 type CreateBookingRequestBody struct {
@@ -55,6 +101,7 @@ type CreateBookingRequestBody struct {
 	BookingAmount         float64   `json:"bookingAmount"`
 	OverdueFeeRatePerHour float64   `json:"overdueFeeRatePerHour"`
 	CancellationAllowed   bool      `json:"cancellationAllowed"`
+	CancellationPolicy    string    `json:"cancellationPolicy"`
 	ScheduledPickupTime   time.Time `json:"scheduledPickupTime"`
 	ScheduledDropoffTime  time.Time `json:"scheduledDropoffTime"`
 }
@@ -62,40 +109,123 @@ type CreateBookingRequestBody struct {
 type OtpToken struct {
 	Id        int
 	BookingId int
-	Otp       string
+	Purpose   string
+	OtpHash   string
+	Attempts  int
 	ExpiresAt time.Time
+	CreatedAt time.Time
 }
 
 type OtpRequestBody struct {
 	Otp string `json:"otp"`
 }
 
+type CancelBookingRequestBody struct {
+	Reason string `json:"reason"`
+}
+
+type BookingStatusHistory struct {
+	Id         int       `json:"id"`
+	BookingId  int       `json:"bookingId"`
+	FromStatus *string   `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	ActorId    *int      `json:"actorId"`
+	ActorRole  string    `json:"actorRole"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ExtendBookingRequestBody struct {
+	ScheduledDropoffTime time.Time `json:"scheduledDropoffTime"`
+	Reason               string    `json:"reason"`
+}
+
+type RescheduleBookingRequestBody struct {
+	ScheduledPickupTime  time.Time `json:"scheduledPickupTime"`
+	ScheduledDropoffTime time.Time `json:"scheduledDropoffTime"`
+	Reason               string    `json:"reason"`
+}
+
+type DecideBookingChangeRequestBody struct {
+	Reason string `json:"reason"`
+}
+
+type BookingChangeRequest struct {
+	Id                  int        `json:"id"`
+	BookingId           int        `json:"bookingId"`
+	Type                string     `json:"type"`
+	Status              string     `json:"status"`
+	PreviousPickupTime  time.Time  `json:"previousPickupTime"`
+	PreviousDropoffTime time.Time  `json:"previousDropoffTime"`
+	PreviousAmount      float64    `json:"previousAmount"`
+	NewPickupTime       time.Time  `json:"newPickupTime"`
+	NewDropoffTime      time.Time  `json:"newDropoffTime"`
+	NewAmount           float64    `json:"newAmount"`
+	RequestedBy         int        `json:"requestedBy"`
+	DecidedBy           *int       `json:"decidedBy"`
+	Reason              string     `json:"reason"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	DecidedAt           *time.Time `json:"decidedAt"`
+}
+
+type BookingCancellation struct {
+	Id                int       `json:"id"`
+	BookingId         int       `json:"bookingId"`
+	HostId            int       `json:"hostId"`
+	CancelledBy       *int      `json:"cancelledBy"`
+	InitiatedBy       string    `json:"initiatedBy"`
+	Policy            string    `json:"policy"`
+	HoursBeforePickup float64   `json:"hoursBeforePickup"`
+	BookingAmount     float64   `json:"bookingAmount"`
+	RefundPercent     int       `json:"refundPercent"`
+	RefundAmount      float64   `json:"refundAmount"`
+	CancellationFee   float64   `json:"cancellationFee"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type PaginatedBookingCancellations struct {
+	Data       []BookingCancellation `json:"data"`
+	Pagination pagination.Page       `json:"pagination"`
+}
+
 type BookingData struct {
-	Id                      int       `json:"id"`
-	Status                  string    `json:"status"`
-	PickupLocation          string    `json:"pickupLocation"`
-	DropoffLocation         string    `json:"dropoffLocation"`
-	BookingAmount           float64   `json:"bookingAmount"`
-	OverdueFeeRatePerHour   float64   `json:"overdueFeeRatePerHour"`
-	CancellationAllowed     bool      `json:"cancellationAllowed"`
-	ScheduledPickupTime     time.Time `json:"scheduledPickupTime"`
-	ScheduledDropoffTime    time.Time `json:"scheduledDropoffTime"`
-	VehicleName             string    `json:"vehicleName"`
-	VehicleSeatCount        int       `json:"vehicleSeatCount"`
-	VehicleFuelType         string    `json:"vehicleFuelType"`
-	VehicleTransmissionType string    `json:"vehicleTransmissionType"`
-	VehicleImage            string    `json:"vehicleImage"`
-}
-
-type PaginationParams struct {
-	Page       int `json:"page"`
-	PageSize   int `json:"pageSize"`
-	TotalCount int `json:"totalCount"`
+	Id                      int        `json:"id"`
+	Status                  string     `json:"status"`
+	PickupLocation          string     `json:"pickupLocation"`
+	DropoffLocation         string     `json:"dropoffLocation"`
+	BookingAmount           float64    `json:"bookingAmount"`
+	OverdueFeeRatePerHour   float64    `json:"overdueFeeRatePerHour"`
+	CancellationAllowed     bool       `json:"cancellationAllowed"`
+	ScheduledPickupTime     time.Time  `json:"scheduledPickupTime"`
+	ScheduledDropoffTime    time.Time  `json:"scheduledDropoffTime"`
+	NoShowAt                *time.Time `json:"noShowAt,omitempty"`
+	OverdueAt               *time.Time `json:"overdueAt,omitempty"`
+	VehicleName             string     `json:"vehicleName"`
+	VehicleSeatCount        int        `json:"vehicleSeatCount"`
+	VehicleFuelType         string     `json:"vehicleFuelType"`
+	VehicleTransmissionType string     `json:"vehicleTransmissionType"`
+	VehicleImage            string     `json:"vehicleImage"`
+}
+
+// BookingFilters narrows booking lists. From and To bound the scheduled pickup or dropoff
+// time, as selected by TimeField, and are both inclusive.
+type BookingFilters struct {
+	Statuses  []string
+	TimeField string
+	From      *time.Time
+	To        *time.Time
+}
+
+type GetBookingsParams struct {
+	Filters    BookingFilters
+	VehicleId  int
+	Sort       string
+	Pagination pagination.Request
 }
 
 type PaginatedBookingData struct {
-	Data       []BookingData    `json:"data"`
-	Pagination PaginationParams `json:"pagination"`
+	Data       []BookingData   `json:"data"`
+	Pagination pagination.Page `json:"pagination"`
 }
 
 type BookingDetails struct {
@@ -106,10 +236,13 @@ type BookingDetails struct {
 	BookingAmount         float64               `json:"bookingAmount"`
 	OverdueFeeRatePerHour float64               `json:"overdueFeeRatePerHour"`
 	CancellationAllowed   bool                  `json:"cancellationAllowed"`
+	CancellationPolicy    string                `json:"cancellationPolicy"`
 	ActualPickupTime      *time.Time            `json:"actualPickupTime,omitempty"`
 	ActualDropoffTime     *time.Time            `json:"actualDropoffTime,omitempty"`
 	ScheduledPickupTime   time.Time             `json:"scheduledPickupTime"`
 	ScheduledDropoffTime  time.Time             `json:"scheduledDropoffTime"`
+	NoShowAt              *time.Time            `json:"noShowAt,omitempty"`
+	OverdueAt             *time.Time            `json:"overdueAt,omitempty"`
 	Host                  BookingDetailsUser    `json:"host"`
 	Seeker                BookingDetailsUser    `json:"seeker"`
 	Vehicle               BookingDetailsVehicle `json:"vehicle"`
@@ -181,17 +314,138 @@ func (c CreateBookingRequestBody) validate() error {
 	return nil
 }
 
-func parseQueryParamToInt(r *http.Request, param string, defaultValue int) (int, error) {
+func (e ExtendBookingRequestBody) validate(booking repository.Booking) error {
+	var validationErrors []string
+
+	if e.ScheduledDropoffTime.IsZero() {
+		validationErrors = append(validationErrors, "scheduledDropoffTime is required")
+	} else if !e.ScheduledDropoffTime.After(booking.ScheduledDropoffTime) {
+		validationErrors = append(validationErrors, "scheduledDropoffTime must be after the current scheduledDropoffTime")
+	} else if !e.ScheduledDropoffTime.After(time.Now()) {
+		validationErrors = append(validationErrors, "scheduledDropoffTime must not be in past")
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
+	}
+
+	return nil
+}
+
+func (r RescheduleBookingRequestBody) validate() error {
+	var validationErrors []string
+
+	if r.ScheduledPickupTime.IsZero() {
+		validationErrors = append(validationErrors, "scheduledPickupTime is required")
+	} else if r.ScheduledPickupTime.Before(time.Now()) {
+		validationErrors = append(validationErrors, "scheduledPickupTime must not be in past")
+	}
+
+	if r.ScheduledDropoffTime.IsZero() {
+		validationErrors = append(validationErrors, "scheduledDropoffTime is required")
+	}
+
+	if !r.ScheduledPickupTime.IsZero() && !r.ScheduledDropoffTime.IsZero() && !r.ScheduledPickupTime.Before(r.ScheduledDropoffTime) {
+		validationErrors = append(validationErrors, "scheduledPickupTime must be before scheduledDropoffTime")
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
+	}
+
+	return nil
+}
+
+func (p *GetBookingsParams) setDefaults() {
+	if p.Sort == "" {
+		p.Sort = repository.BookingSortNewest
+	}
+
+	if p.Filters.TimeField == "" {
+		p.Filters.TimeField = repository.BookingTimePickup
+	}
+}
+
+func (p GetBookingsParams) validate() error {
+	var validationErrors []string
+
+	for _, status := range p.Filters.Statuses {
+		if _, ok := AvailableBookingStatuses[status]; !ok {
+			validationErrors = append(validationErrors, fmt.Sprintf("status %q is invalid", status))
+		}
+	}
+
+	if _, ok := AvailableBookingTimeFields[p.Filters.TimeField]; !ok {
+		validationErrors = append(validationErrors, fmt.Sprintf("timeField %q is invalid", p.Filters.TimeField))
+	}
+
+	if p.Filters.From != nil && p.Filters.To != nil && p.Filters.From.After(*p.Filters.To) {
+		validationErrors = append(validationErrors, "from cannot be after to")
+	}
+
+	if p.VehicleId < 0 {
+		validationErrors = append(validationErrors, "vehicleId must be greater than 0")
+	}
+
+	if _, ok := AvailableBookingSorts[p.Sort]; !ok {
+		validationErrors = append(validationErrors, fmt.Sprintf("sort %q is invalid", p.Sort))
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
+	}
+
+	return nil
+}
+
+// parseGetBookingsParams reads the query parameters shared by the seeker and host booking
+// lists. Statuses are comma-separated and matched case-insensitively.
+func parseGetBookingsParams(r *http.Request) (GetBookingsParams, error) {
+	query := r.URL.Query()
+
+	pageRequest, err := pagination.ParseRequest(r)
+	if err != nil {
+		return GetBookingsParams{}, err
+	}
+
+	params := GetBookingsParams{
+		Filters: BookingFilters{
+			TimeField: query.Get("timeField"),
+		},
+		Sort:       query.Get("sort"),
+		Pagination: pageRequest,
+	}
+
+	for _, status := range strings.Split(query.Get("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			params.Filters.Statuses = append(params.Filters.Statuses, strings.ToUpper(status))
+		}
+	}
+
+	params.Filters.From, err = parseQueryParamToTime(r, "from")
+	if err != nil {
+		return GetBookingsParams{}, err
+	}
+
+	params.Filters.To, err = parseQueryParamToTime(r, "to")
+	if err != nil {
+		return GetBookingsParams{}, err
+	}
+
+	return params, nil
+}
+
+func parseQueryParamToTime(r *http.Request, param string) (*time.Time, error) {
 	query := r.URL.Query().Get(param)
 	if query == "" {
-		return defaultValue, nil
+		return nil, nil
 	}
 
-	value, err := strconv.Atoi(query)
+	value, err := time.Parse(time.RFC3339Nano, query)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return value, nil
+	return &value, nil
 }
 
 func mapBookingDetailsRepoToBookingDetails(bookingDetails repository.BookingDetails) BookingDetails {
@@ -203,10 +457,13 @@ func mapBookingDetailsRepoToBookingDetails(bookingDetails repository.BookingDeta
 		BookingAmount:         bookingDetails.BookingAmount,
 		OverdueFeeRatePerHour: bookingDetails.OverdueFeeRatePerHour,
 		CancellationAllowed:   bookingDetails.CancellationAllowed,
+		CancellationPolicy:    bookingDetails.CancellationPolicy,
 		ActualPickupTime:      bookingDetails.ActualPickupTime,
 		ActualDropoffTime:     bookingDetails.ActualDropoffTime,
 		ScheduledPickupTime:   bookingDetails.ScheduledPickupTime,
 		ScheduledDropoffTime:  bookingDetails.ScheduledDropoffTime,
+		NoShowAt:              bookingDetails.NoShowAt,
+		OverdueAt:             bookingDetails.OverdueAt,
 		Host:                  BookingDetailsUser(bookingDetails.Host),
 		Seeker:                BookingDetailsUser(bookingDetails.Seeker),
 		Vehicle:               BookingDetailsVehicle(bookingDetails.Vehicle),
@@ -1,12 +1,16 @@
 package booking
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/response"
 )
 
@@ -45,7 +49,15 @@ func CancelBooking(bookingService Service) http.HandlerFunc {
 			return
 		}
 
-		err = bookingService.CancelBooking(ctx, parsedBookingId)
+		var requestBody CancelBookingRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil && !errors.Is(err, io.EOF) {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		cancellation, err := bookingService.CancelBooking(ctx, parsedBookingId, requestBody)
 		if err != nil {
 			slog.Error("failed to cancel booking", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -53,7 +65,7 @@ func CancelBooking(bookingService Service) http.HandlerFunc {
 			return
 		}
 
-		response.WriteJson(w, http.StatusOK, "booking cancelled successfully", nil)
+		response.WriteJson(w, http.StatusOK, "booking cancelled successfully", cancellation)
 	}
 }
 
@@ -111,6 +123,29 @@ func InitiateReturn(bookingService Service) http.HandlerFunc {
 	}
 }
 
+func ResendOtp(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		bookingId := r.PathValue("id")
+		parsedBookingId, err := strconv.Atoi(bookingId)
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		err = bookingService.ResendOtp(ctx, parsedBookingId)
+		if err != nil {
+			slog.Error("failed to resend booking otp", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "otp sent successfully", nil)
+	}
+}
+
 func ConfirmReturn(bookingService Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -146,21 +181,14 @@ func GetSeekerBookings(bookingService Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		page, err := parseQueryParamToInt(r, "page", 1)
-		if err != nil {
-			slog.Error("failed to parse page number to int", "error", err)
-			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
-			return
-		}
-
-		limit, err := parseQueryParamToInt(r, "limit", 10)
+		params, err := parseGetBookingsParams(r)
 		if err != nil {
-			slog.Error("failed to parse page limit to int", "error", err)
+			slog.Error("failed to parse booking list parameters", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
 
-		bookings, err := bookingService.GetSeekerBookings(ctx, int(page), int(limit))
+		bookings, err := bookingService.GetSeekerBookings(ctx, params)
 		if err != nil {
 			slog.Error("failed to fetch bookings", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -176,21 +204,23 @@ func GetHostBookings(bookingService Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		page, err := parseQueryParamToInt(r, "page", 1)
+		params, err := parseGetBookingsParams(r)
 		if err != nil {
-			slog.Error("failed to parse page number to int", "error", err)
+			slog.Error("failed to parse booking list parameters", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
 
-		limit, err := parseQueryParamToInt(r, "limit", 10)
-		if err != nil {
-			slog.Error("failed to parse page limit to int", "error", err)
-			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
-			return
+		if vehicleId := r.URL.Query().Get("vehicleId"); vehicleId != "" {
+			params.VehicleId, err = strconv.Atoi(vehicleId)
+			if err != nil || params.VehicleId <= 0 {
+				slog.Error("invalid vehicle id", "vehicleId", vehicleId)
+				response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+				return
+			}
 		}
 
-		bookings, err := bookingService.GetHostBookings(ctx, int(page), int(limit))
+		bookings, err := bookingService.GetHostBookings(ctx, params)
 		if err != nil {
 			slog.Error("failed to fetch bookings", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -224,3 +254,204 @@ func GetBookingDetailsById(bookingService Service) http.HandlerFunc {
 		response.WriteJson(w, http.StatusOK, "booking details fetched successfully", bookingDetails)
 	}
 }
+
+func GetBookingStatusHistory(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		bookingId := r.PathValue("id")
+		parsedBookingId, err := strconv.Atoi(bookingId)
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		history, err := bookingService.GetBookingStatusHistory(ctx, parsedBookingId)
+		if err != nil {
+			slog.Error("failed to fetch booking status history", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "booking status history fetched successfully", history)
+	}
+}
+
+func ExtendBooking(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedBookingId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		var requestBody ExtendBookingRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		changeRequest, err := bookingService.ExtendBooking(ctx, parsedBookingId, requestBody)
+		if err != nil {
+			slog.Error("failed to request booking extension", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "booking extension requested successfully", changeRequest)
+	}
+}
+
+func RescheduleBooking(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedBookingId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		var requestBody RescheduleBookingRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		changeRequest, err := bookingService.RescheduleBooking(ctx, parsedBookingId, requestBody)
+		if err != nil {
+			slog.Error("failed to reschedule booking", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "booking rescheduled successfully", changeRequest)
+	}
+}
+
+func ApproveBookingChange(bookingService Service) http.HandlerFunc {
+	return decideBookingChange(bookingService.ApproveBookingChange, "booking change request approved successfully")
+}
+
+func DeclineBookingChange(bookingService Service) http.HandlerFunc {
+	return decideBookingChange(bookingService.DeclineBookingChange, "booking change request declined successfully")
+}
+
+func decideBookingChange(decide func(ctx context.Context, bookingId, requestId int, decisionData DecideBookingChangeRequestBody) error, successMessage string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedBookingId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		parsedRequestId, err := strconv.Atoi(r.PathValue("requestId"))
+		if err != nil {
+			slog.Error("invalid booking change request id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking change request id", nil)
+			return
+		}
+
+		var requestBody DecideBookingChangeRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil && !errors.Is(err, io.EOF) {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		err = decide(ctx, parsedBookingId, parsedRequestId, requestBody)
+		if err != nil {
+			slog.Error("failed to decide booking change request", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, successMessage, nil)
+	}
+}
+
+func GetBookingChangeRequests(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedBookingId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		changeRequests, err := bookingService.GetBookingChangeRequests(ctx, parsedBookingId)
+		if err != nil {
+			slog.Error("failed to fetch booking change requests", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "booking change requests fetched successfully", changeRequests)
+	}
+}
+
+func GetBookingCancellation(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedBookingId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid booking id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid booking id", nil)
+			return
+		}
+
+		cancellation, err := bookingService.GetBookingCancellation(ctx, parsedBookingId)
+		if err != nil {
+			slog.Error("failed to fetch booking cancellation", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "booking cancellation fetched successfully", cancellation)
+	}
+}
+
+func GetHostCancellations(bookingService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedHostId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid host id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid host id", nil)
+			return
+		}
+
+		pageRequest, err := pagination.ParseRequest(r)
+		if err != nil {
+			slog.Error("failed to parse pagination parameters", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		cancellations, err := bookingService.GetHostCancellations(ctx, parsedHostId, pageRequest)
+		if err != nil {
+			slog.Error("failed to fetch host cancellations", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "host cancellations fetched successfully", cancellations)
+	}
+}
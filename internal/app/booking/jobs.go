@@ -0,0 +1,206 @@
+package booking
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+const noShowReason = "pickup was not confirmed within the no-show grace period"
+
+// HandleNoShows flags bookings whose pickup was never confirmed once the grace period has
+// passed and, if configured, cancels them. Each booking is handled in its own transaction,
+// so one failure does not hold up the rest of the batch.
+func (s *service) HandleNoShows(ctx context.Context) error {
+	bookings, err := s.bookingRepository.GetNoShowBookings(ctx, nil, time.Now().Add(-s.schedulerCfg.NoShowGracePeriod), s.schedulerCfg.BatchSize)
+	if err != nil {
+		slog.Error("failed to get no-show bookings", "error", err)
+		return err
+	}
+
+	for _, booking := range bookings {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = s.handleNoShow(ctx, booking)
+		if err != nil {
+			slog.Error("failed to handle no-show booking", "bookingId", booking.Id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// HandleOverdueBookings flags rentals that have not been returned once the grace period after
+// the scheduled dropoff has passed, and tells both parties that the overdue fee now applies.
+func (s *service) HandleOverdueBookings(ctx context.Context) error {
+	bookings, err := s.bookingRepository.GetOverdueBookings(ctx, nil, time.Now().Add(-s.schedulerCfg.OverdueGracePeriod), s.schedulerCfg.BatchSize)
+	if err != nil {
+		slog.Error("failed to get overdue bookings", "error", err)
+		return err
+	}
+
+	for _, booking := range bookings {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = s.handleOverdueBooking(ctx, booking)
+		if err != nil {
+			slog.Error("failed to handle overdue booking", "bookingId", booking.Id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) handleNoShow(ctx context.Context, booking repository.Booking) (err error) {
+	participants, err := s.bookingParticipants(ctx, booking)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start no-show handling", "error", err)
+		return err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	flagged, err := s.bookingRepository.FlagBookingNoShow(ctx, tx, booking.Id)
+	if err != nil {
+		slog.Error("failed to flag booking as no-show", "error", err)
+		return err
+	}
+
+	// Another run or a status change got there first.
+	if !flagged {
+		return nil
+	}
+
+	if s.schedulerCfg.CancelNoShows {
+		err = s.transitionBooking(ctx, tx, booking, Cancelled, Actor{Role: ActorSystem}, noShowReason)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, participant := range participants {
+		err = s.outboxService.EnqueueEmail(ctx, tx, outbox.Email{
+			ToName:   participant.name,
+			ToEmail:  participant.email,
+			Template: email.BookingNoShowTemplate,
+			Locale:   participant.locale,
+			Data: email.BookingNoShowData{
+				Name:        participant.name,
+				BookingId:   booking.Id,
+				VehicleName: participant.vehicleName,
+				PickupTime:  booking.ScheduledPickupTime,
+				Cancelled:   s.schedulerCfg.CancelNoShows,
+			},
+		})
+		if err != nil {
+			slog.Error("failed to enqueue no-show email", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) handleOverdueBooking(ctx context.Context, booking repository.Booking) (err error) {
+	participants, err := s.bookingParticipants(ctx, booking)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start overdue handling", "error", err)
+		return err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	flagged, err := s.bookingRepository.FlagBookingOverdue(ctx, tx, booking.Id)
+	if err != nil {
+		slog.Error("failed to flag booking as overdue", "error", err)
+		return err
+	}
+
+	if !flagged {
+		return nil
+	}
+
+	for _, participant := range participants {
+		err = s.outboxService.EnqueueEmail(ctx, tx, outbox.Email{
+			ToName:   participant.name,
+			ToEmail:  participant.email,
+			Template: email.BookingOverdueTemplate,
+			Locale:   participant.locale,
+			Data: email.BookingOverdueData{
+				Name:                  participant.name,
+				BookingId:             booking.Id,
+				VehicleName:           participant.vehicleName,
+				DropoffTime:           booking.ScheduledDropoffTime,
+				OverdueFeeRatePerHour: booking.OverdueFeeRatePerHour,
+			},
+		})
+		if err != nil {
+			slog.Error("failed to enqueue overdue email", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+type bookingParticipant struct {
+	name        string
+	email       string
+	locale      string
+	vehicleName string
+}
+
+// bookingParticipants looks up the host and seeker of a booking for the scheduler's emails.
+func (s *service) bookingParticipants(ctx context.Context, booking repository.Booking) ([]bookingParticipant, error) {
+	vehicleName, err := s.bookingRepository.GetBookingVehicleName(ctx, nil, booking.Id)
+	if err != nil {
+		slog.Error("failed to get booking vehicle name", "error", err)
+		return nil, err
+	}
+
+	participants := make([]bookingParticipant, 0, 2)
+	for _, userId := range []int{booking.HostId, booking.SeekerId} {
+		user, err := s.userService.GetUserById(ctx, userId)
+		if err != nil {
+			slog.Error("failed to get booking participant", "userId", userId, "error", err)
+			return nil, err
+		}
+
+		participants = append(participants, bookingParticipant{
+			name:        user.Name,
+			email:       user.Email,
+			locale:      user.PreferredLanguage,
+			vehicleName: vehicleName,
+		})
+	}
+
+	return participants, nil
+}
@@ -0,0 +1,179 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/user"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/cryptokit"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+// ResendOtp sends a new OTP for the booking's next handover: the pickup OTP to the seeker
+// before pickup, and the return OTP to the host while the vehicle is checked out. Either
+// participant can ask, but the OTP always goes to the same recipient.
+func (s *service) ResendOtp(ctx context.Context, bookingId int) (err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to fetch booking by id", "error", err)
+		return err
+	}
+
+	_, err = bookingActor(booking, userId)
+	if err != nil {
+		slog.Error("invalid otp resend attempt")
+		return err
+	}
+
+	purpose, recipientId := OtpPickup, booking.SeekerId
+	switch booking.Status {
+	case Scheduled:
+	case CheckedOut:
+		purpose, recipientId = OtpReturn, booking.HostId
+	default:
+		slog.Error("booking has no pending handover", "bookingId", booking.Id, "status", booking.Status)
+		return apperrors.ErrOptTokenNotFound
+	}
+
+	// Bookings can be left without an OTP, e.g. by the migration that started hashing them, so
+	// a missing OTP is issued rather than refused.
+	otpToken, err := s.bookingRepository.GetOtpToken(ctx, nil, booking.Id, purpose)
+	switch {
+	case errors.Is(err, apperrors.ErrOptTokenNotFound):
+	case err != nil:
+		slog.Error("failed to get otp to resend", "error", err)
+		return err
+	case time.Since(otpToken.CreatedAt) < s.otpCfg.ResendCooldown:
+		slog.Error("otp resend requested within the cooldown", "bookingId", booking.Id)
+		return apperrors.ErrOtpResendCooldown
+	}
+
+	recipient, err := s.userService.GetUserById(ctx, recipientId)
+	if err != nil {
+		slog.Error("failed to get the otp recipient", "error", err)
+		return err
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start otp resend", "error", err)
+		return err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	return s.issueOtp(ctx, tx, booking, purpose, recipient)
+}
+
+// issueOtp replaces the booking's OTP for purpose with a new one and emails it to recipient.
+// Only its hash is stored.
+func (s *service) issueOtp(ctx context.Context, tx *sql.Tx, booking repository.Booking, purpose string, recipient user.User) error {
+	otp, err := cryptokit.GenerateOTP()
+	if err != nil {
+		slog.Error("failed to generate secure otp", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	otpTokenData := OtpToken{
+		BookingId: booking.Id,
+		Purpose:   purpose,
+		OtpHash:   cryptokit.HashOTP(s.otpCfg.Secret, otpScope(booking.Id, purpose), otp),
+		ExpiresAt: booking.ScheduledDropoffTime,
+	}
+	mail := outbox.Email{
+		ToName:    recipient.Name,
+		ToEmail:   recipient.Email,
+		Template:  email.CheckoutOtpTemplate,
+		Locale:    recipient.PreferredLanguage,
+		Data:      email.CheckoutOtpData{Name: recipient.Name, Otp: otp},
+		Sensitive: true,
+	}
+	if purpose == OtpReturn {
+		otpTokenData.ExpiresAt = time.Now().Add(returnOtpTTL)
+		mail.Template = email.ReturnOtpTemplate
+		mail.Data = email.ReturnOtpData{Name: recipient.Name, Otp: otp, ExpiresInMinutes: int(returnOtpTTL.Minutes())}
+	}
+
+	err = s.bookingRepository.CreateOtpToken(ctx, tx, repository.OtpToken(otpTokenData), s.otpCfg.MaxResends)
+	if err != nil {
+		slog.Error("failed to create otp token", "purpose", purpose, "error", err)
+		return err
+	}
+
+	err = s.outboxService.EnqueueEmail(ctx, tx, mail)
+	if err != nil {
+		slog.Error("failed to enqueue otp email", "purpose", purpose, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// verifyOtp checks otp against the booking's OTP for purpose and returns the token, which the
+// caller deletes in the transaction that acts on it. Attempts are recorded outside of that
+// transaction so that failed ones are not rolled back.
+func (s *service) verifyOtp(ctx context.Context, bookingId int, purpose, otp string) (repository.OtpToken, error) {
+	otpToken, err := s.bookingRepository.GetOtpToken(ctx, nil, bookingId, purpose)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrOptTokenNotFound) {
+			slog.Error("no otp issued for booking", "bookingId", bookingId, "purpose", purpose)
+			return repository.OtpToken{}, apperrors.ErrInvalidOtp
+		}
+		slog.Error("failed to get otp", "error", err)
+		return repository.OtpToken{}, err
+	}
+
+	allowed, err := s.bookingRepository.RecordOtpAttempt(ctx, nil, otpToken.Id, s.otpCfg.MaxAttempts)
+	if err != nil {
+		return repository.OtpToken{}, err
+	}
+
+	if !allowed {
+		slog.Error("otp is locked after too many failed attempts", "bookingId", bookingId, "purpose", purpose)
+		return repository.OtpToken{}, apperrors.ErrOtpLocked
+	}
+
+	if time.Now().After(otpToken.ExpiresAt) || !cryptokit.CheckOTPHash(s.otpCfg.Secret, otpScope(bookingId, purpose), otp, otpToken.OtpHash) {
+		slog.Error("invalid otp provided", "bookingId", bookingId, "purpose", purpose)
+		return repository.OtpToken{}, apperrors.ErrInvalidOtp
+	}
+
+	return otpToken, nil
+}
+
+// useOtp deletes a verified OTP, failing if a concurrent request has already used it.
+func (s *service) useOtp(ctx context.Context, tx *sql.Tx, otpTokenId int) error {
+	err := s.bookingRepository.DeleteOtpTokenById(ctx, tx, otpTokenId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrOptTokenNotFound) {
+			slog.Error("otp has already been used", "otpTokenId", otpTokenId)
+			return apperrors.ErrInvalidOtp
+		}
+		return err
+	}
+
+	return nil
+}
+
+func otpScope(bookingId int, purpose string) string {
+	return strconv.Itoa(bookingId) + ":" + purpose
+}
@@ -0,0 +1,193 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+// SendBookingReminders emails seekers the reminders that have come due. Each reminder is
+// marked as sent in the same transaction that queues its email, so it is sent only once.
+func (s *service) SendBookingReminders(ctx context.Context) error {
+	reminders, err := s.bookingRepository.GetDueBookingReminders(ctx, nil, time.Now(), s.schedulerCfg.BatchSize)
+	if err != nil {
+		slog.Error("failed to get due booking reminders", "error", err)
+		return err
+	}
+
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = s.sendReminder(ctx, reminder)
+		if err != nil {
+			slog.Error("failed to send booking reminder", "reminderId", reminder.Id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) sendReminder(ctx context.Context, reminder repository.BookingReminder) (err error) {
+	// Reminders whose booking or seeker cannot be found anymore would fail on every run, and
+	// hold up the reminders queued behind them, so they are cancelled instead.
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, reminder.BookingId)
+	if err != nil {
+		slog.Error("failed to fetch booking by id", "error", err)
+		return s.cancelUnresolvableReminder(ctx, reminder, err)
+	}
+
+	seeker, err := s.userService.GetUserById(ctx, booking.SeekerId)
+	if err != nil {
+		slog.Error("failed to get the seeker to remind", "error", err)
+		return s.cancelUnresolvableReminder(ctx, reminder, err)
+	}
+
+	vehicleName, err := s.bookingRepository.GetBookingVehicleName(ctx, nil, booking.Id)
+	if err != nil {
+		slog.Error("failed to get booking vehicle name", "error", err)
+		return s.cancelUnresolvableReminder(ctx, reminder, err)
+	}
+
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start booking reminder", "error", err)
+		return err
+	}
+
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	// A reminder can fall due after the moment it points at, e.g. while no replica was running
+	// the job. It is dropped then rather than sent late.
+	status := ReminderSent
+	if !reminderApplies(booking, reminder.Kind, time.Now()) {
+		status = ReminderCancelled
+	}
+
+	updated, err := s.bookingRepository.UpdateBookingReminderStatus(ctx, tx, reminder.Id, status)
+	if err != nil {
+		slog.Error("failed to update booking reminder status", "error", err)
+		return err
+	}
+
+	if !updated || status == ReminderCancelled {
+		return nil
+	}
+
+	mail := outbox.Email{
+		ToName:  seeker.Name,
+		ToEmail: seeker.Email,
+		Locale:  seeker.PreferredLanguage,
+	}
+	switch reminder.Kind {
+	case ReminderPickup:
+		mail.Template = email.PickupReminderTemplate
+		mail.Data = email.PickupReminderData{
+			Name:           seeker.Name,
+			BookingId:      booking.Id,
+			VehicleName:    vehicleName,
+			PickupTime:     booking.ScheduledPickupTime,
+			PickupLocation: booking.PickupLocation,
+		}
+	default:
+		mail.Template = email.DropoffReminderTemplate
+		mail.Data = email.DropoffReminderData{
+			Name:                  seeker.Name,
+			BookingId:             booking.Id,
+			VehicleName:           vehicleName,
+			DropoffTime:           booking.ScheduledDropoffTime,
+			DropoffLocation:       booking.DropoffLocation,
+			OverdueFeeRatePerHour: booking.OverdueFeeRatePerHour,
+		}
+	}
+
+	err = s.outboxService.EnqueueEmail(ctx, tx, mail)
+	if err != nil {
+		slog.Error("failed to enqueue booking reminder email", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// cancelUnresolvableReminder cancels the reminder if err means its booking or seeker is gone,
+// and otherwise returns err so that the reminder is retried on the next run.
+func (s *service) cancelUnresolvableReminder(ctx context.Context, reminder repository.BookingReminder, err error) error {
+	if !errors.Is(err, apperrors.ErrBookingNotFound) && !errors.Is(err, apperrors.ErrUserNotFound) {
+		return err
+	}
+
+	_, err = s.bookingRepository.UpdateBookingReminderStatus(ctx, nil, reminder.Id, ReminderCancelled)
+	if err != nil {
+		slog.Error("failed to cancel unresolvable booking reminder", "reminderId", reminder.Id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func reminderApplies(booking repository.Booking, kind string, now time.Time) bool {
+	if kind == ReminderPickup {
+		return booking.Status == Scheduled && booking.ScheduledPickupTime.After(now)
+	}
+
+	return booking.Status == CheckedOut && booking.ScheduledDropoffTime.After(now)
+}
+
+// scheduleReminders creates the booking's reminders that are still ahead of it. Pickup
+// reminders are left out once the vehicle has been picked up.
+func (s *service) scheduleReminders(ctx context.Context, tx *sql.Tx, booking repository.Booking) error {
+	reminders := []repository.CreateBookingReminderData{}
+	if booking.Status == Scheduled {
+		for _, lead := range s.schedulerCfg.PickupReminders {
+			reminders = append(reminders, repository.CreateBookingReminderData{BookingId: booking.Id, Kind: ReminderPickup, RemindAt: booking.ScheduledPickupTime.Add(-lead)})
+		}
+	}
+	for _, lead := range s.schedulerCfg.DropoffReminders {
+		reminders = append(reminders, repository.CreateBookingReminderData{BookingId: booking.Id, Kind: ReminderDropoff, RemindAt: booking.ScheduledDropoffTime.Add(-lead)})
+	}
+
+	now := time.Now()
+	for _, reminder := range reminders {
+		if !reminder.RemindAt.After(now) {
+			continue
+		}
+
+		err := s.bookingRepository.CreateBookingReminder(ctx, tx, reminder)
+		if err != nil {
+			slog.Error("failed to schedule booking reminder", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cancelReminders drops the pending reminders that no longer apply to the booking's new status.
+func (s *service) cancelReminders(ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error {
+	kinds := []string{ReminderPickup, ReminderDropoff}
+	if booking.Status == CheckedOut {
+		kinds = []string{ReminderPickup}
+	}
+
+	err := s.bookingRepository.CancelBookingReminders(ctx, tx, booking.Id, kinds)
+	if err != nil {
+		slog.Error("failed to cancel booking reminders", "error", err)
+		return err
+	}
+
+	return nil
+}
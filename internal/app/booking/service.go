@@ -2,17 +2,19 @@ package booking
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log/slog"
 	"math"
+	"strings"
 	"time"
 
-	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/user"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/vehicle"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
-	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/cryptokit"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
 )
 
@@ -20,27 +22,49 @@ type service struct {
 	bookingRepository repository.BookingRepository
 	userService       user.Service
 	vehicleService    vehicle.Service
-	emailService      email.Service
+	outboxService     outbox.Service
+	availabilityCfg   config.Availability
+	schedulerCfg      config.Scheduler
+	otpCfg            config.Otp
 }
 
 type Service interface {
 	CreateBooking(ctx context.Context, bookingData CreateBookingRequestBody) (newBooking Booking, err error)
-	CancelBooking(ctx context.Context, bookingId int) (err error)
+	CancelBooking(ctx context.Context, bookingId int, cancelData CancelBookingRequestBody) (cancellation BookingCancellation, err error)
 	ConfirmPickup(ctx context.Context, bookingId int, otpData OtpRequestBody) (err error)
 	InitiateReturn(ctx context.Context, bookingId int) (err error)
 	ConfirmReturn(ctx context.Context, bookingId int, otpData OtpRequestBody) (err error)
-	GetSeekerBookings(ctx context.Context, page, limit int) (bookings PaginatedBookingData, err error)
-	GetHostBookings(ctx context.Context, page, limit int) (bookings PaginatedBookingData, err error)
+	GetSeekerBookings(ctx context.Context, params GetBookingsParams) (bookings PaginatedBookingData, err error)
+	GetHostBookings(ctx context.Context, params GetBookingsParams) (bookings PaginatedBookingData, err error)
 	GetBookingDetailsById(ctx context.Context, bookingId int) (booking BookingDetails, err error)
+	GetBookingStatusHistory(ctx context.Context, bookingId int) (history []BookingStatusHistory, err error)
+	ExtendBooking(ctx context.Context, bookingId int, extendData ExtendBookingRequestBody) (changeRequest BookingChangeRequest, err error)
+	RescheduleBooking(ctx context.Context, bookingId int, rescheduleData RescheduleBookingRequestBody) (changeRequest BookingChangeRequest, err error)
+	ApproveBookingChange(ctx context.Context, bookingId, requestId int, decisionData DecideBookingChangeRequestBody) (err error)
+	DeclineBookingChange(ctx context.Context, bookingId, requestId int, decisionData DecideBookingChangeRequestBody) error
+	GetBookingChangeRequests(ctx context.Context, bookingId int) (changeRequests []BookingChangeRequest, err error)
+	GetBookingCancellation(ctx context.Context, bookingId int) (cancellation BookingCancellation, err error)
+	GetHostCancellations(ctx context.Context, hostId int, pageRequest pagination.Request) (cancellations PaginatedBookingCancellations, err error)
+	HandleNoShows(ctx context.Context) error
+	HandleOverdueBookings(ctx context.Context) error
+	SendBookingReminders(ctx context.Context) error
+	ResendOtp(ctx context.Context, bookingId int) (err error)
 }
 
-func NewService(bookingRepository repository.BookingRepository, userService user.Service, vehicleService vehicle.Service, emailService email.Service) Service {
+func NewService(bookingRepository repository.BookingRepository, userService user.Service, vehicleService vehicle.Service, outboxService outbox.Service, availabilityCfg config.Availability, schedulerCfg config.Scheduler, otpCfg config.Otp) (Service, error) {
+	if otpCfg.Secret == "" {
+		return nil, errors.New("otp secret is required")
+	}
+
 	return &service{
 		bookingRepository: bookingRepository,
 		userService:       userService,
 		vehicleService:    vehicleService,
-		emailService:      emailService,
-	}
+		outboxService:     outboxService,
+		availabilityCfg:   availabilityCfg,
+		schedulerCfg:      schedulerCfg,
+		otpCfg:            otpCfg,
+	}, nil
 }
 
 func (s *service) CreateBooking(ctx context.Context, bookingData CreateBookingRequestBody) (newBooking Booking, err error) {
@@ -67,12 +91,6 @@ func (s *service) CreateBooking(ctx context.Context, bookingData CreateBookingRe
 		return Booking{}, apperrors.ErrVehicleNotFound
 	}
 
-	err = s.bookingRepository.VehicleBookingConflictCheck(ctx, nil, vehicle.Id, bookingData.ScheduledPickupTime, bookingData.ScheduledDropoffTime)
-	if err != nil {
-		slog.Error("failed to check booking slot availability", "error", err)
-		return Booking{}, err
-	}
-
 	user, err := s.userService.GetUserById(ctx, userId)
 	if err != nil {
 		slog.Error("failed to get the user to create booking", "error", err)
@@ -92,14 +110,19 @@ func (s *service) CreateBooking(ctx context.Context, bookingData CreateBookingRe
 		}
 	}()
 
+	err = s.bookingRepository.VehicleBookingConflictCheck(ctx, tx, vehicle.Id, bookingData.ScheduledPickupTime, bookingData.ScheduledDropoffTime, s.availabilityCfg.BookingBuffer, 0)
+	if err != nil {
+		slog.Error("failed to check booking slot availability", "error", err)
+		return Booking{}, err
+	}
+
 	bookingData.HostId = vehicle.HostId
 	bookingData.SeekerId = user.Id
 	bookingData.Status = Scheduled
-	duration := bookingData.ScheduledDropoffTime.Sub(bookingData.ScheduledPickupTime)
-	numberOfHours := math.Ceil(duration.Hours())
-	bookingData.BookingAmount = numberOfHours * vehicle.RatePerHour
+	bookingData.BookingAmount = bookingAmount(bookingData.ScheduledPickupTime, bookingData.ScheduledDropoffTime, vehicle.RatePerHour)
 	bookingData.OverdueFeeRatePerHour = vehicle.OverdueFeeRatePerHour
 	bookingData.CancellationAllowed = vehicle.CancellationAllowed
+	bookingData.CancellationPolicy = vehicle.CancellationPolicy
 
 	booking, err := s.bookingRepository.CreateBooking(ctx, tx, repository.CreateBookingRequestBody(bookingData))
 	if err != nil {
@@ -107,64 +130,69 @@ func (s *service) CreateBooking(ctx context.Context, bookingData CreateBookingRe
 		return Booking{}, err
 	}
 
-	otp, err := cryptokit.GenerateOTP()
+	err = s.recordStatusHistory(ctx, tx, booking.Id, nil, booking.Status, Actor{Id: &user.Id, Role: ActorSeeker}, "booking created")
 	if err != nil {
-		slog.Error("failed to generate secure otp", "error", err)
-		return Booking{}, apperrors.ErrInternalServer
+		return Booking{}, err
 	}
 
-	optTokenData := OtpToken{
-		BookingId: booking.Id,
-		Otp:       otp,
-		ExpiresAt: booking.ScheduledDropoffTime,
-	}
-	err = s.bookingRepository.CreateOtpToken(ctx, tx, repository.OtpToken(optTokenData))
+	err = s.issueOtp(ctx, tx, booking, OtpPickup, user)
 	if err != nil {
-		slog.Error("failed to create checkout otp token", "error", err)
 		return Booking{}, err
 	}
 
-	emailBody := fmt.Sprintf(checkoutOtpEmailContent, user.Name, otp)
-
-	err = s.emailService.SendEmail(user.Name, user.Email, "Vehicle Checkout OTP – Wheelio", emailBody)
+	err = s.scheduleReminders(ctx, tx, booking)
 	if err != nil {
-		slog.Error("failed to send checkout otp email", "error", err)
 		return Booking{}, err
 	}
 
 	return Booking(booking), nil
 }
 
-func (s *service) CancelBooking(ctx context.Context, bookingId int) (err error) {
+func (s *service) CancelBooking(ctx context.Context, bookingId int, cancelData CancelBookingRequestBody) (cancellation BookingCancellation, err error) {
 	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
 	if !ok {
 		slog.Error("failed to retrieve user id from context")
-		return apperrors.ErrInternalServer
+		return BookingCancellation{}, apperrors.ErrInternalServer
 	}
 
 	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
 	if err != nil {
 		slog.Error("failed to get booking", "error", err)
-		return err
+		return BookingCancellation{}, err
 	}
 
-	if booking.HostId != userId && booking.SeekerId != userId {
+	actor, err := bookingActor(booking, userId)
+	if err != nil {
 		slog.Error("unauthorized booking cancellation attempt")
-		return apperrors.ErrActionForbidden
+		return BookingCancellation{}, err
 	}
 
-	if booking.SeekerId == userId && !booking.CancellationAllowed {
-		slog.Error("cancellation not allowed for this booking")
-		return apperrors.ErrBookingCancellationNotAllowed
+	tx, err := s.bookingRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start booking cancellation", "error", err)
+		return BookingCancellation{}, err
 	}
 
-	err = s.bookingRepository.UpdateBookingStatus(ctx, nil, bookingId, Cancelled)
+	defer func() {
+		if txErr := s.bookingRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	err = s.transitionBooking(ctx, tx, booking, Cancelled, actor, strings.TrimSpace(cancelData.Reason))
 	if err != nil {
 		slog.Error("failed to cancel the booking", "error", err)
-		return err
+		return BookingCancellation{}, err
+	}
+
+	newCancellation, err := s.bookingRepository.GetBookingCancellation(ctx, tx, booking.Id)
+	if err != nil {
+		slog.Error("failed to get booking cancellation", "error", err)
+		return BookingCancellation{}, err
 	}
 
-	return nil
+	return BookingCancellation(newCancellation), nil
 }
 
 func (s *service) ConfirmPickup(ctx context.Context, bookingId int, otpData OtpRequestBody) (err error) {
@@ -180,20 +208,21 @@ func (s *service) ConfirmPickup(ctx context.Context, bookingId int, otpData OtpR
 		return err
 	}
 
-	if booking.HostId != userId || booking.Status != Scheduled {
+	actor, err := bookingActor(booking, userId)
+	if err != nil {
 		slog.Error("invalid booking pickup attempt")
-		return apperrors.ErrActionForbidden
+		return err
 	}
 
-	otpToken, err := s.bookingRepository.GetOtpToken(ctx, nil, otpData.Otp)
+	_, err = checkTransition(booking, CheckedOut, actor)
 	if err != nil {
-		slog.Error("failed to get otp", "error", err)
-		return apperrors.ErrInvalidOtp
+		slog.Error("invalid booking pickup attempt", "error", err)
+		return err
 	}
 
-	if time.Now().After(otpToken.ExpiresAt) || bookingId != otpToken.BookingId {
-		slog.Error("invalid otp provided")
-		return apperrors.ErrInvalidOtp
+	otpToken, err := s.verifyOtp(ctx, booking.Id, OtpPickup, otpData.Otp)
+	if err != nil {
+		return err
 	}
 
 	tx, err := s.bookingRepository.BeginTx(ctx)
@@ -209,24 +238,13 @@ func (s *service) ConfirmPickup(ctx context.Context, bookingId int, otpData OtpR
 		}
 	}()
 
-	err = s.bookingRepository.UpdateBookingStatus(ctx, tx, bookingId, CheckedOut)
-	if err != nil {
-		slog.Error("failed to update booking status", "error", err)
-		return err
-	}
-
-	err = s.bookingRepository.UpdateActualPickupTime(ctx, tx, bookingId)
+	err = s.transitionBooking(ctx, tx, booking, CheckedOut, actor, "pickup confirmed with otp")
 	if err != nil {
-		slog.Error("failed to update actual pickup time for booking", "error", err)
+		slog.Error("failed to confirm booking pickup", "error", err)
 		return err
 	}
 
-	err = s.bookingRepository.DeleteOtpTokenById(ctx, nil, otpToken.Id)
-	if err != nil {
-		slog.Warn("failed to delete otp token", "error", err)
-	}
-
-	return nil
+	return s.useOtp(ctx, tx, otpToken.Id)
 }
 
 func (s *service) InitiateReturn(ctx context.Context, bookingId int) (err error) {
@@ -242,21 +260,22 @@ func (s *service) InitiateReturn(ctx context.Context, bookingId int) (err error)
 		return err
 	}
 
-	if booking.HostId != userId || booking.Status != CheckedOut {
+	actor, err := bookingActor(booking, userId)
+	if err != nil {
 		slog.Error("invalid booking initiate return attempt")
-		return apperrors.ErrActionForbidden
+		return err
 	}
 
-	host, err := s.userService.GetUserById(ctx, userId)
+	_, err = checkTransitionInitiation(booking, Returned, actor)
 	if err != nil {
-		slog.Error("failed to get the user to initiate return", "error", err)
+		slog.Error("invalid booking initiate return attempt", "error", err)
 		return err
 	}
 
-	otp, err := cryptokit.GenerateOTP()
+	host, err := s.userService.GetUserById(ctx, userId)
 	if err != nil {
-		slog.Error("failed to generate secure otp", "error", err)
-		return apperrors.ErrInternalServer
+		slog.Error("failed to get the user to initiate return", "error", err)
+		return err
 	}
 
 	tx, err := s.bookingRepository.BeginTx(ctx)
@@ -272,26 +291,7 @@ func (s *service) InitiateReturn(ctx context.Context, bookingId int) (err error)
 		}
 	}()
 
-	optTokenData := OtpToken{
-		BookingId: booking.Id,
-		Otp:       otp,
-		ExpiresAt: time.Now().Add(20 * time.Minute),
-	}
-	err = s.bookingRepository.CreateOtpToken(ctx, tx, repository.OtpToken(optTokenData))
-	if err != nil {
-		slog.Error("failed to create return otp token", "error", err)
-		return err
-	}
-
-	emailBody := fmt.Sprintf(initiateReturnOtpEmailContent, host.Name, otp)
-
-	err = s.emailService.SendEmail(host.Name, host.Email, "Vehicle Return OTP – Wheelio", emailBody)
-	if err != nil {
-		slog.Error("failed to send return otp email", "error", err)
-		return err
-	}
-
-	return nil
+	return s.issueOtp(ctx, tx, booking, OtpReturn, host)
 }
 
 func (s *service) ConfirmReturn(ctx context.Context, bookingId int, otpData OtpRequestBody) (err error) {
@@ -307,20 +307,21 @@ func (s *service) ConfirmReturn(ctx context.Context, bookingId int, otpData OtpR
 		return err
 	}
 
-	if booking.SeekerId != userId || booking.Status != CheckedOut {
+	actor, err := bookingActor(booking, userId)
+	if err != nil {
 		slog.Error("invalid booking return attempt")
-		return apperrors.ErrActionForbidden
+		return err
 	}
 
-	otpToken, err := s.bookingRepository.GetOtpToken(ctx, nil, otpData.Otp)
+	_, err = checkTransition(booking, Returned, actor)
 	if err != nil {
-		slog.Error("failed to get otp", "error", err)
-		return apperrors.ErrInvalidOtp
+		slog.Error("invalid booking return attempt", "error", err)
+		return err
 	}
 
-	if time.Now().After(otpToken.ExpiresAt) || bookingId != otpToken.BookingId {
-		slog.Error("invalid otp provided")
-		return apperrors.ErrInvalidOtp
+	otpToken, err := s.verifyOtp(ctx, booking.Id, OtpReturn, otpData.Otp)
+	if err != nil {
+		return err
 	}
 
 	tx, err := s.bookingRepository.BeginTx(ctx)
@@ -336,73 +337,41 @@ func (s *service) ConfirmReturn(ctx context.Context, bookingId int, otpData OtpR
 		}
 	}()
 
-	err = s.bookingRepository.UpdateBookingStatus(ctx, tx, bookingId, Returned)
-	if err != nil {
-		slog.Error("failed to update booking status", "error", err)
-		return err
-	}
-
-	err = s.bookingRepository.UpdateActualDropoffTime(ctx, tx, bookingId)
-	if err != nil {
-		slog.Error("failed to update actual dropoff time for booking", "error", err)
-		return err
-	}
-
-	overdueTime := time.Since(booking.ScheduledDropoffTime).Hours()
-	if overdueTime < 0 {
-		overdueTime = 0
-	}
-	additionalFees := overdueTime * booking.OverdueFeeRatePerHour
-	taxAmount := (booking.BookingAmount + additionalFees) * taxRate
-	totalAmount := booking.BookingAmount + additionalFees + taxAmount
-
-	invoiceData := repository.Invoice{
-		BookingId:      bookingId,
-		BookingAmount:  booking.BookingAmount,
-		AdditionalFees: additionalFees,
-		Tax:            taxAmount,
-		TaxRate:        taxRate,
-		TotalAmount:    totalAmount,
-	}
-	_, err = s.bookingRepository.CreateInvoice(ctx, tx, invoiceData)
+	err = s.transitionBooking(ctx, tx, booking, Returned, actor, "return confirmed with otp")
 	if err != nil {
-		slog.Error("failed to create invoice", "error", err)
+		slog.Error("failed to confirm booking return", "error", err)
 		return err
 	}
 
-	err = s.bookingRepository.DeleteOtpTokenById(ctx, nil, otpToken.Id)
-	if err != nil {
-		slog.Warn("failed to delete otp token", "error", err)
-	}
-
-	return nil
+	return s.useOtp(ctx, tx, otpToken.Id)
 }
 
-func (s *service) GetSeekerBookings(ctx context.Context, page, limit int) (bookings PaginatedBookingData, err error) {
+func (s *service) GetSeekerBookings(ctx context.Context, params GetBookingsParams) (bookings PaginatedBookingData, err error) {
 	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
 	if !ok {
 		slog.Error("failed to retrieve user id from context")
 		return PaginatedBookingData{}, apperrors.ErrInternalServer
 	}
 
-	if page <= 0 {
-		slog.Error("invalid page number provided", "page", page)
-		return PaginatedBookingData{}, apperrors.ErrInvalidPagination
+	params.setDefaults()
+	err = params.validate()
+	if err != nil {
+		slog.Error("booking list parameters validation failed", "error", err)
+		return PaginatedBookingData{}, apperrors.ErrInvalidQueryParams
 	}
 
-	if limit <= 0 {
-		slog.Error("invalid limit value provided", "limit", limit)
-		return PaginatedBookingData{}, apperrors.ErrInvalidPagination
+	pageParams, err := params.Pagination.Params(params.Sort)
+	if err != nil {
+		return PaginatedBookingData{}, err
 	}
 
-	offset := limit * (page - 1)
-
 	repoParams := repository.GetSeekerBookingsParams{
 		SeekerId: userId,
-		Offset:   offset,
-		Limit:    limit,
+		Filters:  repository.BookingFilters(params.Filters),
+		Sort:     params.Sort,
+		Page:     pageParams,
 	}
-	bookingList, totalBookings, err := s.bookingRepository.GetSeekerBookings(ctx, nil, repoParams)
+	bookingList, pageInfo, err := s.bookingRepository.GetSeekerBookings(ctx, nil, repoParams)
 	if err != nil {
 		slog.Error("failed to get vehicle list for host", "error", err)
 		return PaginatedBookingData{}, err
@@ -411,42 +380,45 @@ func (s *service) GetSeekerBookings(ctx context.Context, page, limit int) (booki
 	vehicleData := make([]BookingData, len(bookingList))
 	for i, v := range bookingList {
 		vehicleData[i] = BookingData(v)
+		vehicleData[i].VehicleImage, err = s.vehicleService.ImageAccessURL(ctx, v.VehicleImage)
+		if err != nil {
+			return PaginatedBookingData{}, err
+		}
 	}
 
 	return PaginatedBookingData{
-		Data: vehicleData,
-		Pagination: PaginationParams{
-			Page:       page,
-			PageSize:   limit,
-			TotalCount: totalBookings,
-		}}, nil
+		Data:       vehicleData,
+		Pagination: pagination.NewPage(params.Pagination, params.Sort, pageInfo),
+	}, nil
 }
 
-func (s *service) GetHostBookings(ctx context.Context, page, limit int) (bookings PaginatedBookingData, err error) {
+func (s *service) GetHostBookings(ctx context.Context, params GetBookingsParams) (bookings PaginatedBookingData, err error) {
 	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
 	if !ok {
 		slog.Error("failed to retrieve user id from context")
 		return PaginatedBookingData{}, apperrors.ErrInternalServer
 	}
 
-	if page <= 0 {
-		slog.Error("invalid page number provided", "page", page)
-		return PaginatedBookingData{}, apperrors.ErrInvalidPagination
+	params.setDefaults()
+	err = params.validate()
+	if err != nil {
+		slog.Error("booking list parameters validation failed", "error", err)
+		return PaginatedBookingData{}, apperrors.ErrInvalidQueryParams
 	}
 
-	if limit <= 0 {
-		slog.Error("invalid limit value provided", "limit", limit)
-		return PaginatedBookingData{}, apperrors.ErrInvalidPagination
+	pageParams, err := params.Pagination.Params(params.Sort)
+	if err != nil {
+		return PaginatedBookingData{}, err
 	}
 
-	offset := limit * (page - 1)
-
 	repoParams := repository.GetHostBookingsParams{
-		HostId: userId,
-		Offset: offset,
-		Limit:  limit,
+		HostId:    userId,
+		VehicleId: params.VehicleId,
+		Filters:   repository.BookingFilters(params.Filters),
+		Sort:      params.Sort,
+		Page:      pageParams,
 	}
-	bookingList, totalBookings, err := s.bookingRepository.GetHostBookings(ctx, nil, repoParams)
+	bookingList, pageInfo, err := s.bookingRepository.GetHostBookings(ctx, nil, repoParams)
 	if err != nil {
 		slog.Error("failed to get vehicle list for host", "error", err)
 		return PaginatedBookingData{}, err
@@ -455,15 +427,16 @@ func (s *service) GetHostBookings(ctx context.Context, page, limit int) (booking
 	vehicleData := make([]BookingData, len(bookingList))
 	for i, v := range bookingList {
 		vehicleData[i] = BookingData(v)
+		vehicleData[i].VehicleImage, err = s.vehicleService.ImageAccessURL(ctx, v.VehicleImage)
+		if err != nil {
+			return PaginatedBookingData{}, err
+		}
 	}
 
 	return PaginatedBookingData{
-		Data: vehicleData,
-		Pagination: PaginationParams{
-			Page:       page,
-			PageSize:   limit,
-			TotalCount: totalBookings,
-		}}, nil
+		Data:       vehicleData,
+		Pagination: pagination.NewPage(params.Pagination, params.Sort, pageInfo),
+	}, nil
 }
 
 func (s *service) GetBookingDetailsById(ctx context.Context, bookingId int) (booking BookingDetails, err error) {
@@ -473,5 +446,102 @@ func (s *service) GetBookingDetailsById(ctx context.Context, bookingId int) (boo
 		return BookingDetails{}, err
 	}
 
-	return mapBookingDetailsRepoToBookingDetails(bookingDetails), nil
+	booking = mapBookingDetailsRepoToBookingDetails(bookingDetails)
+	booking.Vehicle.Image, err = s.vehicleService.ImageAccessURL(ctx, booking.Vehicle.Image)
+	if err != nil {
+		return BookingDetails{}, err
+	}
+
+	return booking, nil
+}
+
+func (s *service) GetBookingStatusHistory(ctx context.Context, bookingId int) (history []BookingStatusHistory, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return []BookingStatusHistory{}, apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking", "error", err)
+		return []BookingStatusHistory{}, err
+	}
+
+	_, err = bookingActor(booking, userId)
+	if err != nil {
+		slog.Error("unauthorized booking history access attempt")
+		return []BookingStatusHistory{}, err
+	}
+
+	historyList, err := s.bookingRepository.GetBookingStatusHistory(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking status history", "error", err)
+		return []BookingStatusHistory{}, err
+	}
+
+	history = make([]BookingStatusHistory, len(historyList))
+	for i, h := range historyList {
+		history[i] = BookingStatusHistory(h)
+	}
+
+	return history, nil
+}
+
+// bookingAmount charges every started hour between pickup and dropoff.
+func bookingAmount(pickupTime, dropoffTime time.Time, ratePerHour float64) float64 {
+	numberOfHours := math.Ceil(dropoffTime.Sub(pickupTime).Hours())
+	return numberOfHours * ratePerHour
+}
+
+func (s *service) GetBookingCancellation(ctx context.Context, bookingId int) (cancellation BookingCancellation, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return BookingCancellation{}, apperrors.ErrInternalServer
+	}
+
+	booking, err := s.bookingRepository.GetBookingById(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking", "error", err)
+		return BookingCancellation{}, err
+	}
+
+	_, err = bookingActor(booking, userId)
+	if err != nil {
+		slog.Error("unauthorized booking cancellation access attempt")
+		return BookingCancellation{}, err
+	}
+
+	bookingCancellation, err := s.bookingRepository.GetBookingCancellation(ctx, nil, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking cancellation", "error", err)
+		return BookingCancellation{}, err
+	}
+
+	return BookingCancellation(bookingCancellation), nil
+}
+
+// GetHostCancellations lists the bookings a host cancelled, for admins deciding on penalties.
+func (s *service) GetHostCancellations(ctx context.Context, hostId int, pageRequest pagination.Request) (cancellations PaginatedBookingCancellations, err error) {
+	pageParams, err := pageRequest.Params(repository.BookingSortNewest)
+	if err != nil {
+		return PaginatedBookingCancellations{}, err
+	}
+
+	cancellationList, pageInfo, err := s.bookingRepository.GetHostCancellations(ctx, nil, repository.GetHostCancellationsParams{HostId: hostId, Page: pageParams})
+	if err != nil {
+		slog.Error("failed to get host cancellations", "error", err)
+		return PaginatedBookingCancellations{}, err
+	}
+
+	cancellationData := make([]BookingCancellation, len(cancellationList))
+	for i, c := range cancellationList {
+		cancellationData[i] = BookingCancellation(c)
+	}
+
+	return PaginatedBookingCancellations{
+		Data:       cancellationData,
+		Pagination: pagination.NewPage(pageRequest, repository.BookingSortNewest, pageInfo),
+	}, nil
 }
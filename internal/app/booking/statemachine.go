@@ -0,0 +1,240 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/vehicle"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+type Actor struct {
+	Id   *int
+	Role string
+}
+
+type transitionRule struct {
+	allowedActors []string
+	// initiators can start a transition that one of allowedActors completes, e.g. by having
+	// the OTP sent that the completing actor enters.
+	initiators []string
+	// guard runs before the transition and can veto it for reasons beyond the actor role.
+	guard func(booking repository.Booking, actor Actor) error
+	// sideEffects run inside the transaction after the status has been updated.
+	sideEffects []func(s *service, ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error
+}
+
+var bookingTransitions = map[string]map[string]transitionRule{
+	Scheduled: {
+		CheckedOut: {
+			allowedActors: []string{ActorHost},
+			sideEffects: []func(s *service, ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error{
+				(*service).recordActualPickupTime,
+				(*service).cancelReminders,
+			},
+		},
+		Cancelled: {
+			allowedActors: []string{ActorHost, ActorSeeker, ActorSystem},
+			guard:         cancellationGuard,
+			sideEffects: []func(s *service, ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error{
+				(*service).recordCancellation,
+				(*service).cancelReminders,
+				(*service).closeBookingChanges,
+			},
+		},
+	},
+	CheckedOut: {
+		Returned: {
+			allowedActors: []string{ActorSeeker},
+			initiators:    []string{ActorHost},
+			sideEffects: []func(s *service, ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error{
+				(*service).recordActualDropoffTime,
+				(*service).generateInvoice,
+				(*service).cancelReminders,
+				(*service).closeBookingChanges,
+			},
+		},
+	},
+}
+
+func bookingActor(booking repository.Booking, userId int) (Actor, error) {
+	switch userId {
+	case booking.HostId:
+		return Actor{Id: &userId, Role: ActorHost}, nil
+	case booking.SeekerId:
+		return Actor{Id: &userId, Role: ActorSeeker}, nil
+	default:
+		slog.Error("user is not a participant of the booking", "bookingId", booking.Id, "userId", userId)
+		return Actor{}, apperrors.ErrActionForbidden
+	}
+}
+
+func cancellationGuard(booking repository.Booking, actor Actor) error {
+	if actor.Role == ActorSeeker && !booking.CancellationAllowed {
+		slog.Error("cancellation not allowed for this booking")
+		return apperrors.ErrBookingCancellationNotAllowed
+	}
+
+	return nil
+}
+
+func checkTransition(booking repository.Booking, toStatus string, actor Actor) (transitionRule, error) {
+	return checkTransitionActor(booking, toStatus, actor, func(rule transitionRule) []string { return rule.allowedActors })
+}
+
+// checkTransitionInitiation checks that actor may start the transition to toStatus, which
+// another actor then completes through checkTransition.
+func checkTransitionInitiation(booking repository.Booking, toStatus string, actor Actor) (transitionRule, error) {
+	return checkTransitionActor(booking, toStatus, actor, func(rule transitionRule) []string { return rule.initiators })
+}
+
+func checkTransitionActor(booking repository.Booking, toStatus string, actor Actor, actors func(rule transitionRule) []string) (transitionRule, error) {
+	rule, ok := bookingTransitions[booking.Status][toStatus]
+	if !ok {
+		slog.Error("booking status transition not allowed", "bookingId", booking.Id, "fromStatus", booking.Status, "toStatus", toStatus)
+		return transitionRule{}, apperrors.ErrInvalidBookingTransition
+	}
+
+	if !slices.Contains(actors(rule), actor.Role) {
+		slog.Error("actor not allowed to perform booking status transition", "bookingId", booking.Id, "actorRole", actor.Role, "toStatus", toStatus)
+		return transitionRule{}, apperrors.ErrActionForbidden
+	}
+
+	if rule.guard != nil {
+		err := rule.guard(booking, actor)
+		if err != nil {
+			return transitionRule{}, err
+		}
+	}
+
+	return rule, nil
+}
+
+func (s *service) transitionBooking(ctx context.Context, tx *sql.Tx, booking repository.Booking, toStatus string, actor Actor, reason string) error {
+	rule, err := checkTransition(booking, toStatus, actor)
+	if err != nil {
+		return err
+	}
+
+	err = s.bookingRepository.UpdateBookingStatus(ctx, tx, booking.Id, booking.Status, toStatus)
+	if err != nil {
+		slog.Error("failed to update booking status", "error", err)
+		return err
+	}
+
+	fromStatus := booking.Status
+	booking.Status = toStatus
+	for _, sideEffect := range rule.sideEffects {
+		err = sideEffect(s, ctx, tx, booking, actor)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.recordStatusHistory(ctx, tx, booking.Id, &fromStatus, toStatus, actor, reason)
+}
+
+func (s *service) recordStatusHistory(ctx context.Context, tx *sql.Tx, bookingId int, fromStatus *string, toStatus string, actor Actor, reason string) error {
+	historyData := repository.BookingStatusHistory{
+		BookingId:  bookingId,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		ActorId:    actor.Id,
+		ActorRole:  actor.Role,
+		Reason:     reason,
+	}
+	err := s.bookingRepository.CreateBookingStatusHistory(ctx, tx, historyData)
+	if err != nil {
+		slog.Error("failed to record booking status history", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) recordActualPickupTime(ctx context.Context, tx *sql.Tx, booking repository.Booking, _ Actor) error {
+	err := s.bookingRepository.UpdateActualPickupTime(ctx, tx, booking.Id)
+	if err != nil {
+		slog.Error("failed to update actual pickup time for booking", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) recordActualDropoffTime(ctx context.Context, tx *sql.Tx, booking repository.Booking, _ Actor) error {
+	err := s.bookingRepository.UpdateActualDropoffTime(ctx, tx, booking.Id)
+	if err != nil {
+		slog.Error("failed to update actual dropoff time for booking", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) generateInvoice(ctx context.Context, tx *sql.Tx, booking repository.Booking, _ Actor) error {
+	overdueTime := time.Since(booking.ScheduledDropoffTime).Hours()
+	if overdueTime < 0 {
+		overdueTime = 0
+	}
+	additionalFees := overdueTime * booking.OverdueFeeRatePerHour
+	taxAmount := (booking.BookingAmount + additionalFees) * taxRate
+	totalAmount := booking.BookingAmount + additionalFees + taxAmount
+
+	invoiceData := repository.Invoice{
+		BookingId:      booking.Id,
+		BookingAmount:  booking.BookingAmount,
+		AdditionalFees: additionalFees,
+		Tax:            taxAmount,
+		TaxRate:        taxRate,
+		TotalAmount:    totalAmount,
+	}
+	_, err := s.bookingRepository.CreateInvoice(ctx, tx, invoiceData)
+	if err != nil {
+		slog.Error("failed to create invoice", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// recordCancellation works out the refund under the booking's cancellation policy. Hosts
+// cancelling refund the seeker in full; the cancellation is recorded as host-initiated instead.
+func (s *service) recordCancellation(ctx context.Context, tx *sql.Tx, booking repository.Booking, actor Actor) error {
+	notice := time.Until(booking.ScheduledPickupTime)
+
+	refundPercent := 100
+	if actor.Role != ActorHost {
+		refundPercent = vehicle.CancellationPolicies[booking.CancellationPolicy].RefundPercent(notice)
+	}
+	refundAmount := roundToCents(booking.BookingAmount * float64(refundPercent) / 100)
+
+	cancellationData := repository.CreateBookingCancellationData{
+		BookingId:         booking.Id,
+		HostId:            booking.HostId,
+		CancelledBy:       actor.Id,
+		InitiatedBy:       actor.Role,
+		Policy:            booking.CancellationPolicy,
+		HoursBeforePickup: roundToCents(notice.Hours()),
+		BookingAmount:     booking.BookingAmount,
+		RefundPercent:     refundPercent,
+		RefundAmount:      refundAmount,
+		CancellationFee:   roundToCents(booking.BookingAmount - refundAmount),
+	}
+	err := s.bookingRepository.CreateBookingCancellation(ctx, tx, cancellationData)
+	if err != nil {
+		slog.Error("failed to record booking cancellation", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
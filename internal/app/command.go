@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+)
+
+const (
+	rewriteImageURLsCommand = "rewrite-image-urls"
+	geocodeVehiclesCommand  = "geocode-vehicles"
+
+	commandUsage = "usage: rewrite-image-urls [--from <old base url>] [--dry-run] | geocode-vehicles"
+)
+
+// RunCommand runs one-off maintenance commands that need the application services.
+func RunCommand(ctx context.Context, deps Dependencies, args []string) error {
+	if len(args) == 0 {
+		return errors.New(commandUsage)
+	}
+
+	switch args[0] {
+	case rewriteImageURLsCommand:
+		flags := flag.NewFlagSet(rewriteImageURLsCommand, flag.ContinueOnError)
+		fromBaseURL := flags.String("from", "", "base url the stored image urls currently start with")
+		dryRun := flags.Bool("dry-run", false, "report the changes without writing them")
+		err := flags.Parse(args[1:])
+		if err != nil {
+			return errors.New(commandUsage)
+		}
+
+		result, err := deps.VehicleService.RewriteImageURLs(ctx, *fromBaseURL, *dryRun)
+		if err != nil {
+			return err
+		}
+		slog.Info("vehicle image urls rewritten", "updated", result.Updated, "skipped", result.Skipped, "dryRun", *dryRun)
+		return nil
+	case geocodeVehiclesCommand:
+		result, err := deps.VehicleService.GeocodeVehicles(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("vehicle coordinates geocoded", "updated", result.Updated, "failed", result.Failed)
+		return nil
+	default:
+		return errors.New(commandUsage)
+	}
+}
@@ -1,37 +1,90 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 
-	"cloud.google.com/go/storage"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/booking"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
-	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/firebase"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/geocoding"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/scheduler"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/storage"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/upload"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/user"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/vehicle"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
 )
 
 type Dependencies struct {
-	UserService    user.Service
-	VehicleService vehicle.Service
-	BookingService booking.Service
+	UserService      user.Service
+	VehicleService   vehicle.Service
+	BookingService   booking.Service
+	OutboxService    outbox.Service
+	EmailTemplates   email.TemplateRenderer
+	StorageService   storage.Service
+	UploadService    upload.Service
+	SchedulerService scheduler.Service
 }
 
-func InitDependencies(db *sql.DB, firebaseBucket *storage.BucketHandle) Dependencies {
+func InitDependencies(ctx context.Context, cfg config.Config, db *sql.DB) (Dependencies, error) {
 	userRepository := repository.NewUserRepository(db)
 	vehicleRepository := repository.NewVehicleRepository(db)
 	bookingRepository := repository.NewBookingRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+	uploadRepository := repository.NewUploadRepository(db)
+	jobLeaseRepository := repository.NewJobLeaseRepository(db)
 
-	emailService := email.NewService()
-	firebaseService := firebase.NewService(firebaseBucket)
-	userService := user.NewService(userRepository, emailService)
-	vehicleService := vehicle.NewService(vehicleRepository, firebaseService)
-	bookingService := booking.NewService(bookingRepository, userService, vehicleService, emailService)
+	if cfg.EmailService.SendTimeout >= cfg.Outbox.LeaseDuration {
+		return Dependencies{}, fmt.Errorf("email send timeout %s must be shorter than the outbox lease duration %s", cfg.EmailService.SendTimeout, cfg.Outbox.LeaseDuration)
+	}
 
-	return Dependencies{
-		UserService:    userService,
-		VehicleService: vehicleService,
-		BookingService: bookingService,
+	emailService, err := email.NewService(cfg.EmailService)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	emailTemplates, err := email.NewTemplateRenderer()
+	if err != nil {
+		return Dependencies{}, err
 	}
+
+	outboxService := outbox.NewService(outboxRepository, emailService, emailTemplates, cfg.Outbox)
+	storageService, err := storage.NewService(ctx, cfg.StorageService)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	geocodingService, err := geocoding.NewService(cfg.Geocoding)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	uploadService := upload.NewService(uploadRepository, storageService, cfg.Uploads)
+	userService := user.NewService(userRepository, outboxService)
+	vehicleService := vehicle.NewService(vehicleRepository, storageService, uploadService, geocodingService, cfg.ImageProcessing, cfg.Availability, cfg.Geocoding)
+	bookingService, err := booking.NewService(bookingRepository, userService, vehicleService, outboxService, cfg.Availability, cfg.Scheduler, cfg.Otp)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	schedulerService := scheduler.NewService(jobLeaseRepository, cfg.Scheduler,
+		scheduler.Job{Name: "booking-no-shows", Run: bookingService.HandleNoShows},
+		scheduler.Job{Name: "booking-overdue", Run: bookingService.HandleOverdueBookings},
+		scheduler.Job{Name: "booking-reminders", Run: bookingService.SendBookingReminders},
+		scheduler.Job{Name: "session-cleanup", Run: userService.DeleteStaleSessions},
+	)
+
+	return Dependencies{
+		UserService:      userService,
+		VehicleService:   vehicleService,
+		BookingService:   bookingService,
+		OutboxService:    outboxService,
+		EmailTemplates:   emailTemplates,
+		StorageService:   storageService,
+		UploadService:    uploadService,
+		SchedulerService: schedulerService,
+	}, nil
 }
@@ -0,0 +1,45 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+type fileService struct {
+	Directory string
+	FromName  string
+	FromEmail string
+}
+
+func newFileService(cfg config.EmailService) (Service, error) {
+	err := os.MkdirAll(cfg.File.Directory, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email directory: %w", err)
+	}
+
+	return &fileService{Directory: cfg.File.Directory, FromName: cfg.FromName, FromEmail: cfg.FromEmail}, nil
+}
+
+func (s *fileService) SendEmail(ctx context.Context, message Message) error {
+	rawMessage := buildMessage(s.FromName, s.FromEmail, message)
+
+	fileName := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), uuid.New().String())
+	filePath := filepath.Join(s.Directory, fileName)
+
+	err := os.WriteFile(filePath, rawMessage, 0o644)
+	if err != nil {
+		slog.Error("failed to write email to file", "error", err)
+		return apperrors.ErrEmailSendFailed
+	}
+
+	slog.Info("email written to file", "path", filePath, "to", message.ToEmail, "subject", message.Subject)
+	return nil
+}
@@ -0,0 +1,61 @@
+package email
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/response"
+)
+
+func GetTemplates(templateRenderer TemplateRenderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locales := make([]string, 0, len(SupportedLocales))
+		for locale := range SupportedLocales {
+			locales = append(locales, locale)
+		}
+
+		response.WriteJson(w, http.StatusOK, "email templates fetched successfully", map[string][]string{
+			"templates": templateRenderer.TemplateNames(),
+			"locales":   locales,
+		})
+	}
+}
+
+func PreviewTemplate(templateRenderer TemplateRenderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+
+		locale := r.URL.Query().Get("locale")
+		if locale == "" {
+			locale = DefaultLocale
+		}
+		if _, ok := SupportedLocales[locale]; !ok {
+			slog.Error("unsupported locale for email template preview", "locale", locale)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		rendered, err := templateRenderer.RenderSample(name, locale)
+		if err != nil {
+			slog.Error("failed to render email template preview", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		// Raw formats let QA open the rendered email directly in a browser.
+		switch r.URL.Query().Get("format") {
+		case "html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(rendered.HTMLContent))
+		case "text":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(rendered.Subject + "\n\n" + rendered.PlainTextContent))
+		default:
+			response.WriteJson(w, http.StatusOK, "email template rendered successfully", rendered)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Message struct {
+	ToName           string
+	ToEmail          string
+	Subject          string
+	PlainTextContent string
+	HTMLContent      string
+}
+
+func buildMessage(fromName, fromEmail string, msg Message) []byte {
+	from := mail.Address{Name: fromName, Address: fromEmail}
+	to := mail.Address{Name: msg.ToName, Address: msg.ToEmail}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from.String())
+	fmt.Fprintf(&message, "To: %s\r\n", to.String())
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: <%s@%s>\r\n", uuid.New().String(), domainOf(fromEmail))
+	message.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTMLContent == "" {
+		message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+		message.WriteString("\r\n")
+		writeQuotedPrintable(&message, msg.PlainTextContent)
+		return message.Bytes()
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%q\r\n", writer.Boundary())
+	message.WriteString("\r\n")
+
+	// Clients render the last alternative they support, so the HTML part goes after the plain text one.
+	writePart(writer, "text/plain; charset=utf-8", msg.PlainTextContent)
+	writePart(writer, "text/html; charset=utf-8", msg.HTMLContent)
+	writer.Close()
+
+	message.Write(body.Bytes())
+	return message.Bytes()
+}
+
+func writePart(writer *multipart.Writer, contentType, content string) {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return
+	}
+	qpWriter := quotedprintable.NewWriter(part)
+	qpWriter.Write([]byte(content))
+	qpWriter.Close()
+}
+
+func writeQuotedPrintable(buffer *bytes.Buffer, content string) {
+	writer := quotedprintable.NewWriter(buffer)
+	writer.Write([]byte(content))
+	writer.Close()
+}
+
+func domainOf(emailAddress string) string {
+	index := strings.LastIndex(emailAddress, "@")
+	if index == -1 {
+		return "localhost"
+	}
+
+	return emailAddress[index+1:]
+}
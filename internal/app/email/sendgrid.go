@@ -0,0 +1,49 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+type sendGridService struct {
+	APIKey      string
+	FromName    string
+	FromEmail   string
+	SendTimeout time.Duration
+}
+
+func newSendGridService(cfg config.EmailService) Service {
+	return &sendGridService{APIKey: cfg.ApiKey, FromName: cfg.FromName, FromEmail: cfg.FromEmail, SendTimeout: cfg.SendTimeout}
+}
+
+func (s *sendGridService) SendEmail(ctx context.Context, message Message) error {
+	from := mail.NewEmail(s.FromName, s.FromEmail)
+	to := mail.NewEmail(message.ToName, message.ToEmail)
+	sendGridMessage := mail.NewSingleEmail(from, message.Subject, to, message.PlainTextContent, message.HTMLContent)
+
+	client := sendgrid.NewSendClient(s.APIKey)
+
+	ctx, cancel := context.WithTimeout(ctx, s.SendTimeout)
+	defer cancel()
+
+	resp, err := client.SendWithContext(ctx, sendGridMessage)
+	if err != nil {
+		slog.Error("failed to send email", "error", err)
+		return apperrors.ErrEmailSendFailed
+	}
+
+	// The client only fails on transport errors; rejected requests come back as a response.
+	if resp.StatusCode >= 300 {
+		slog.Error("sendgrid rejected email", "status", resp.StatusCode, "body", resp.Body)
+		return fmt.Errorf("%w: sendgrid responded with status %d: %s", apperrors.ErrEmailSendFailed, resp.StatusCode, resp.Body)
+	}
+
+	return nil
+}
@@ -1,41 +1,41 @@
 package email
 
 import (
-	"log/slog"
+	"context"
+	"fmt"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
-	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
 )
 
-type service struct {
-	APIKey    string
-	FromName  string
-	FromEmail string
-}
+const (
+	// Email drivers
+	SendGridDriver = "sendgrid"
+	SMTPDriver     = "smtp"
+	FileDriver     = "file"
+)
 
+// Service delivers a single email. Implementations give up once ctx is done or after the
+// configured send timeout, whichever comes first.
 type Service interface {
-	SendEmail(toName, toEmail, subject, plainTextContent string) error
-}
-
-func NewService() Service {
-	cfg := config.GetConfig()
-	return &service{APIKey: cfg.EmailService.ApiKey, FromName: cfg.EmailService.FromName, FromEmail: cfg.EmailService.FromEmail}
+	SendEmail(ctx context.Context, message Message) error
 }
 
-func (s *service) SendEmail(toName, toEmail, subject, plainTextContent string) error {
-	from := mail.NewEmail(s.FromName, s.FromEmail)
-	to := mail.NewEmail(toName, toEmail)
-	message := mail.NewSingleEmail(from, subject, to, plainTextContent, "")
-
-	client := sendgrid.NewSendClient(s.APIKey)
-
-	_, err := client.Send(message)
-	if err != nil {
-		slog.Error("failed to send email", "error", err)
-		return apperrors.ErrEmailSendFailed
+func NewService(cfg config.EmailService) (Service, error) {
+	if cfg.SendTimeout <= 0 {
+		return nil, fmt.Errorf("email send timeout must be positive, got %s", cfg.SendTimeout)
 	}
 
-	return nil
+	switch cfg.Driver {
+	case SendGridDriver:
+		if cfg.ApiKey == "" {
+			return nil, fmt.Errorf("api key is required for the %s email driver", SendGridDriver)
+		}
+		return newSendGridService(cfg), nil
+	case SMTPDriver:
+		return newSMTPService(cfg), nil
+	case FileDriver:
+		return newFileService(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported email driver: %q", cfg.Driver)
+	}
 }
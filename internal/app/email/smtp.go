@@ -0,0 +1,114 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+type smtpService struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	FromName    string
+	FromEmail   string
+	SendTimeout time.Duration
+}
+
+func newSMTPService(cfg config.EmailService) Service {
+	return &smtpService{
+		Host:        cfg.SMTP.Host,
+		Port:        cfg.SMTP.Port,
+		Username:    cfg.SMTP.Username,
+		Password:    cfg.SMTP.Password,
+		FromName:    cfg.FromName,
+		FromEmail:   cfg.FromEmail,
+		SendTimeout: cfg.SendTimeout,
+	}
+}
+
+func (s *smtpService) SendEmail(ctx context.Context, message Message) error {
+	rawMessage := buildMessage(s.FromName, s.FromEmail, message)
+
+	ctx, cancel := context.WithTimeout(ctx, s.SendTimeout)
+	defer cancel()
+
+	err := s.sendMail(ctx, message.ToEmail, rawMessage)
+	if err != nil {
+		slog.Error("failed to send email over smtp", "error", err)
+		return apperrors.ErrEmailSendFailed
+	}
+
+	return nil
+}
+
+// sendMail follows smtp.SendMail, which has no timeout, over a connection whose deadline is
+// the one of ctx.
+func (s *smtpService) sendMail(ctx context.Context, to string, rawMessage []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", s.Host, s.Port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: s.Host})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Servers like MailHog accept unauthenticated mail, so auth is only used when configured.
+	if s.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(s.FromEmail)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(rawMessage)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,248 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+const (
+	// Template names
+	EmailVerificationTemplate = "email_verification"
+	PasswordResetTemplate     = "password_reset"
+	CheckoutOtpTemplate       = "checkout_otp"
+	ReturnOtpTemplate         = "return_otp"
+	BookingNoShowTemplate     = "booking_no_show"
+	BookingOverdueTemplate    = "booking_overdue"
+	PickupReminderTemplate    = "pickup_reminder"
+	DropoffReminderTemplate   = "dropoff_reminder"
+
+	// Locales
+	DefaultLocale = "en"
+	HindiLocale   = "hi"
+
+	layoutTemplateFile = "layout.html"
+	commonTemplateFile = "common.html"
+)
+
+var SupportedLocales = map[string]struct{}{
+	DefaultLocale: {},
+	HindiLocale:   {},
+}
+
+type EmailVerificationData struct {
+	Name             string
+	VerificationLink string
+	ExpiresInMinutes int
+}
+
+type PasswordResetData struct {
+	Name             string
+	ResetLink        string
+	ExpiresInMinutes int
+}
+
+type CheckoutOtpData struct {
+	Name string
+	Otp  string
+}
+
+type ReturnOtpData struct {
+	Name             string
+	Otp              string
+	ExpiresInMinutes int
+}
+
+type BookingNoShowData struct {
+	Name        string
+	BookingId   int
+	VehicleName string
+	PickupTime  time.Time
+	Cancelled   bool
+}
+
+type BookingOverdueData struct {
+	Name                  string
+	BookingId             int
+	VehicleName           string
+	DropoffTime           time.Time
+	OverdueFeeRatePerHour float64
+}
+
+type PickupReminderData struct {
+	Name           string
+	BookingId      int
+	VehicleName    string
+	PickupTime     time.Time
+	PickupLocation string
+}
+
+type DropoffReminderData struct {
+	Name                  string
+	BookingId             int
+	VehicleName           string
+	DropoffTime           time.Time
+	DropoffLocation       string
+	OverdueFeeRatePerHour float64
+}
+
+// templateSampleData backs template previews and doubles as the registry of known templates.
+var templateSampleData = map[string]any{
+	EmailVerificationTemplate: EmailVerificationData{Name: "Asha Patil", VerificationLink: "https://wheelio.example.com/verify-email?token=sample", ExpiresInMinutes: 10},
+	PasswordResetTemplate:     PasswordResetData{Name: "Asha Patil", ResetLink: "https://wheelio.example.com/reset-password?token=sample", ExpiresInMinutes: 10},
+	CheckoutOtpTemplate:       CheckoutOtpData{Name: "Asha Patil", Otp: "482913"},
+	ReturnOtpTemplate:         ReturnOtpData{Name: "Rahul Mehta", Otp: "735204", ExpiresInMinutes: 20},
+	BookingNoShowTemplate:     BookingNoShowData{Name: "Asha Patil", BookingId: 1042, VehicleName: "Honda City", PickupTime: time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC), Cancelled: true},
+	BookingOverdueTemplate:    BookingOverdueData{Name: "Rahul Mehta", BookingId: 1042, VehicleName: "Honda City", DropoffTime: time.Date(2025, 6, 3, 18, 0, 0, 0, time.UTC), OverdueFeeRatePerHour: 250},
+	PickupReminderTemplate:    PickupReminderData{Name: "Asha Patil", BookingId: 1042, VehicleName: "Honda City", PickupTime: time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC), PickupLocation: "12 MG Road, Pune"},
+	DropoffReminderTemplate:   DropoffReminderData{Name: "Asha Patil", BookingId: 1042, VehicleName: "Honda City", DropoffTime: time.Date(2025, 6, 3, 18, 0, 0, 0, time.UTC), DropoffLocation: "12 MG Road, Pune", OverdueFeeRatePerHour: 250},
+}
+
+type RenderedTemplate struct {
+	Subject          string `json:"subject"`
+	PlainTextContent string `json:"plainTextContent"`
+	HTMLContent      string `json:"htmlContent"`
+}
+
+type TemplateRenderer interface {
+	Render(name, locale string, data any) (RenderedTemplate, error)
+	RenderSample(name, locale string) (RenderedTemplate, error)
+	TemplateNames() []string
+}
+
+type localizedTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+type templateRenderer struct {
+	templates map[string]map[string]localizedTemplate
+}
+
+// NewTemplateRenderer parses every embedded template up front so that a broken template
+// fails at startup rather than when the email is sent. Every template must exist for the
+// default locale; other locales fall back to it for templates they do not translate.
+func NewTemplateRenderer() (TemplateRenderer, error) {
+	renderer := &templateRenderer{templates: make(map[string]map[string]localizedTemplate)}
+
+	for locale := range SupportedLocales {
+		renderer.templates[locale] = make(map[string]localizedTemplate)
+		for name := range templateSampleData {
+			tmpl, found, err := parseLocalizedTemplate(name, locale)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				if locale == DefaultLocale {
+					return nil, fmt.Errorf("email template %s is missing for the default locale", name)
+				}
+				continue
+			}
+			renderer.templates[locale][name] = tmpl
+		}
+	}
+
+	return renderer, nil
+}
+
+func parseLocalizedTemplate(name, locale string) (localizedTemplate, bool, error) {
+	textFile := path.Join("templates", locale, name+".txt")
+	htmlFile := path.Join("templates", locale, name+".html")
+
+	_, textErr := fs.Stat(templateFiles, textFile)
+	_, htmlErr := fs.Stat(templateFiles, htmlFile)
+	if textErr != nil || htmlErr != nil {
+		return localizedTemplate{}, false, nil
+	}
+
+	textTemplate, err := texttemplate.ParseFS(templateFiles, textFile)
+	if err != nil {
+		return localizedTemplate{}, false, fmt.Errorf("failed to parse email template %s: %w", textFile, err)
+	}
+	if textTemplate.Lookup("subject") == nil || textTemplate.Lookup("text") == nil {
+		return localizedTemplate{}, false, fmt.Errorf("email template %s must define subject and text", textFile)
+	}
+
+	commonFile := path.Join("templates", locale, commonTemplateFile)
+	if _, err := fs.Stat(templateFiles, commonFile); err != nil {
+		commonFile = path.Join("templates", DefaultLocale, commonTemplateFile)
+	}
+
+	htmlTemplate, err := htmltemplate.New(layoutTemplateFile).
+		Funcs(htmltemplate.FuncMap{"locale": func() string { return locale }}).
+		ParseFS(templateFiles, path.Join("templates", layoutTemplateFile), commonFile, htmlFile)
+	if err != nil {
+		return localizedTemplate{}, false, fmt.Errorf("failed to parse email template %s: %w", htmlFile, err)
+	}
+
+	return localizedTemplate{text: textTemplate, html: htmlTemplate}, true, nil
+}
+
+func (r *templateRenderer) Render(name, locale string, data any) (RenderedTemplate, error) {
+	tmpl, ok := r.templates[locale][name]
+	if !ok {
+		tmpl, ok = r.templates[DefaultLocale][name]
+		if !ok {
+			slog.Error("email template not found", "template", name, "locale", locale)
+			return RenderedTemplate{}, apperrors.ErrEmailTemplateNotFound
+		}
+	}
+
+	var subject, text, html bytes.Buffer
+	err := tmpl.text.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		slog.Error("failed to render email subject", "template", name, "locale", locale, "error", err)
+		return RenderedTemplate{}, apperrors.ErrInternalServer
+	}
+
+	err = tmpl.text.ExecuteTemplate(&text, "text", data)
+	if err != nil {
+		slog.Error("failed to render plain text email", "template", name, "locale", locale, "error", err)
+		return RenderedTemplate{}, apperrors.ErrInternalServer
+	}
+
+	err = tmpl.html.ExecuteTemplate(&html, layoutTemplateFile, data)
+	if err != nil {
+		slog.Error("failed to render html email", "template", name, "locale", locale, "error", err)
+		return RenderedTemplate{}, apperrors.ErrInternalServer
+	}
+
+	return RenderedTemplate{
+		Subject:          strings.TrimSpace(subject.String()),
+		PlainTextContent: strings.TrimSpace(text.String()),
+		HTMLContent:      html.String(),
+	}, nil
+}
+
+func (r *templateRenderer) RenderSample(name, locale string) (RenderedTemplate, error) {
+	data, ok := templateSampleData[name]
+	if !ok {
+		slog.Error("email template not found", "template", name)
+		return RenderedTemplate{}, apperrors.ErrEmailTemplateNotFound
+	}
+
+	return r.Render(name, locale, data)
+}
+
+func (r *templateRenderer) TemplateNames() []string {
+	names := make([]string, 0, len(templateSampleData))
+	for name := range templateSampleData {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,147 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+// Usage policy of the public Nominatim instance.
+const nominatimMinRequestInterval = time.Second
+
+// nominatimService geocodes through the OpenStreetMap Nominatim search API or a self-hosted
+// instance. The public instance requires an identifying user agent and at most one request
+// per second, so requests are spaced out across the whole process: every request reserves the
+// next free slot and waits for it without holding the lock.
+type nominatimService struct {
+	searchURL    string
+	userAgent    string
+	countryCodes string
+	client       *http.Client
+
+	mu         sync.Mutex
+	nextSlotAt time.Time
+}
+
+type nominatimPlace struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func newNominatimService(cfg config.Geocoding) (Service, error) {
+	baseURL, err := url.Parse(cfg.Nominatim.BaseURL)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid nominatim base url: %q", cfg.Nominatim.BaseURL)
+	}
+
+	if strings.TrimSpace(cfg.Nominatim.UserAgent) == "" {
+		return nil, fmt.Errorf("user agent is required for the %s geocoding driver", NominatimDriver)
+	}
+
+	return &nominatimService{
+		searchURL:    strings.TrimSuffix(baseURL.String(), "/") + "/search",
+		userAgent:    cfg.Nominatim.UserAgent,
+		countryCodes: cfg.Nominatim.CountryCodes,
+		client:       &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (s *nominatimService) Geocode(ctx context.Context, address Address) (Coordinates, error) {
+	query := url.Values{}
+	query.Set("q", address.String())
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+	if s.countryCodes != "" {
+		query.Set("countrycodes", s.countryCodes)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.searchURL+"?"+query.Encode(), nil)
+	if err != nil {
+		slog.Error("failed to build nominatim request", "error", err)
+		return Coordinates{}, apperrors.ErrInternalServer
+	}
+	request.Header.Set("User-Agent", s.userAgent)
+	request.Header.Set("Accept", "application/json")
+
+	err = s.waitForTurn(ctx)
+	if err != nil {
+		return Coordinates{}, err
+	}
+
+	res, err := s.client.Do(request)
+	if err != nil {
+		slog.Error("failed to call nominatim", "error", err)
+		return Coordinates{}, apperrors.ErrInternalServer
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		slog.Error("unexpected nominatim response", "status", res.StatusCode)
+		return Coordinates{}, apperrors.ErrInternalServer
+	}
+
+	var places []nominatimPlace
+	err = json.NewDecoder(res.Body).Decode(&places)
+	if err != nil {
+		slog.Error("failed to decode nominatim response", "error", err)
+		return Coordinates{}, apperrors.ErrInternalServer
+	}
+
+	if len(places) == 0 {
+		return Coordinates{}, apperrors.ErrLocationNotFound
+	}
+
+	latitude, err := strconv.ParseFloat(places[0].Lat, 64)
+	if err != nil {
+		slog.Error("invalid latitude in nominatim response", "lat", places[0].Lat, "error", err)
+		return Coordinates{}, apperrors.ErrInternalServer
+	}
+
+	longitude, err := strconv.ParseFloat(places[0].Lon, 64)
+	if err != nil {
+		slog.Error("invalid longitude in nominatim response", "lon", places[0].Lon, "error", err)
+		return Coordinates{}, apperrors.ErrInternalServer
+	}
+
+	return Coordinates{Latitude: latitude, Longitude: longitude}, nil
+}
+
+func (s *nominatimService) waitForTurn(ctx context.Context) error {
+	s.mu.Lock()
+	slot := time.Now()
+	if s.nextSlotAt.After(slot) {
+		slot = s.nextSlotAt
+	}
+	// A caller that would give up before its turn does not take a slot from those that won't.
+	if deadline, ok := ctx.Deadline(); ok && slot.After(deadline) {
+		s.mu.Unlock()
+		slog.Error("nominatim rate limit would exceed the request deadline", "wait", time.Until(slot))
+		return apperrors.ErrInternalServer
+	}
+	s.nextSlotAt = slot.Add(nominatimMinRequestInterval)
+	s.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			slog.Error("gave up waiting for nominatim rate limit", "error", ctx.Err())
+			return apperrors.ErrInternalServer
+		case <-timer.C:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package geocoding
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+// cityCentres backs the offline driver. Addresses resolve to the centre of their city, which is
+// precise enough for local development and radius searches across a city.
+var cityCentres = map[string]Coordinates{
+	"ahmedabad":  {Latitude: 23.0225, Longitude: 72.5714},
+	"bengaluru":  {Latitude: 12.9716, Longitude: 77.5946},
+	"bangalore":  {Latitude: 12.9716, Longitude: 77.5946},
+	"chennai":    {Latitude: 13.0827, Longitude: 80.2707},
+	"delhi":      {Latitude: 28.6139, Longitude: 77.2090},
+	"new delhi":  {Latitude: 28.6139, Longitude: 77.2090},
+	"hyderabad":  {Latitude: 17.3850, Longitude: 78.4867},
+	"jaipur":     {Latitude: 26.9124, Longitude: 75.7873},
+	"kolkata":    {Latitude: 22.5726, Longitude: 88.3639},
+	"mumbai":     {Latitude: 19.0760, Longitude: 72.8777},
+	"nagpur":     {Latitude: 21.1458, Longitude: 79.0882},
+	"nashik":     {Latitude: 19.9975, Longitude: 73.7898},
+	"pune":       {Latitude: 18.5204, Longitude: 73.8567},
+	"thane":      {Latitude: 19.2183, Longitude: 72.9781},
+	"kolhapur":   {Latitude: 16.7050, Longitude: 74.2433},
+	"aurangabad": {Latitude: 19.8762, Longitude: 75.3433},
+}
+
+// offlineService geocodes without any network access.
+type offlineService struct{}
+
+func newOfflineService() Service {
+	return &offlineService{}
+}
+
+func (s *offlineService) Geocode(ctx context.Context, address Address) (Coordinates, error) {
+	coordinates, ok := cityCentres[strings.ToLower(strings.TrimSpace(address.City))]
+	if !ok {
+		slog.Warn("city not known to the offline geocoder", "city", address.City)
+		return Coordinates{}, apperrors.ErrLocationNotFound
+	}
+
+	return coordinates, nil
+}
@@ -0,0 +1,57 @@
+package geocoding
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+)
+
+const (
+	// Geocoding drivers
+	NominatimDriver = "nominatim"
+	OfflineDriver   = "offline"
+)
+
+type Address struct {
+	Line    string
+	City    string
+	State   string
+	PinCode int
+}
+
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type Service interface {
+	Geocode(ctx context.Context, address Address) (Coordinates, error)
+}
+
+func NewService(cfg config.Geocoding) (Service, error) {
+	switch cfg.Driver {
+	case NominatimDriver:
+		return newNominatimService(cfg)
+	case OfflineDriver:
+		return newOfflineService(), nil
+	default:
+		return nil, fmt.Errorf("unsupported geocoding driver: %q", cfg.Driver)
+	}
+}
+
+func (a Address) String() string {
+	var parts []string
+	for _, part := range []string{a.Line, a.City, a.State} {
+		if strings.TrimSpace(part) != "" {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+	if a.PinCode > 0 {
+		parts = append(parts, strconv.Itoa(a.PinCode))
+	}
+
+	return strings.Join(parts, ", ")
+}
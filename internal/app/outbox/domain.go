@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+const (
+	// Outbox email status
+	Pending = "PENDING"
+	Sent    = "SENT"
+	Dead    = "DEAD"
+
+	// Upper bound on how much of a delivery error is persisted
+	maxLastErrorLength = 1000
+)
+
+var AvailableStatuses = map[string]struct{}{
+	Pending: {},
+	Sent:    {},
+	Dead:    {},
+}
+
+type Email struct {
+	ToName   string
+	ToEmail  string
+	Template string
+	Locale   string
+	Data     any
+	// Sensitive emails, such as OTPs, have their content cleared once delivered.
+	Sensitive bool
+}
+
+type OutboxEmail struct {
+	Id            int        `json:"id"`
+	ToName        string     `json:"toName"`
+	ToEmail       string     `json:"toEmail"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"maxAttempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LastError     string     `json:"lastError"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+type PaginatedOutboxEmails struct {
+	Data       []OutboxEmail   `json:"data"`
+	Pagination pagination.Page `json:"pagination"`
+}
+
+func mapOutboxEmailRepoToOutboxEmail(email repository.OutboxEmail) OutboxEmail {
+	return OutboxEmail{
+		Id:            email.Id,
+		ToName:        email.ToName,
+		ToEmail:       email.ToEmail,
+		Subject:       email.Subject,
+		Status:        email.Status,
+		Attempts:      email.Attempts,
+		MaxAttempts:   email.MaxAttempts,
+		NextAttemptAt: email.NextAttemptAt,
+		LastError:     email.LastError,
+		SentAt:        email.SentAt,
+		CreatedAt:     email.CreatedAt,
+		UpdatedAt:     email.UpdatedAt,
+	}
+}
@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/response"
+)
+
+func GetOutboxEmails(outboxService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		pageRequest, err := pagination.ParseRequest(r)
+		if err != nil {
+			slog.Error("failed to parse pagination parameters", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		emails, err := outboxService.GetOutboxEmails(ctx, r.URL.Query().Get("status"), pageRequest)
+		if err != nil {
+			slog.Error("failed to fetch outbox emails", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "outbox emails fetched successfully", emails)
+	}
+}
+
+func RedriveOutboxEmail(outboxService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		emailId := r.PathValue("id")
+		parsedEmailId, err := strconv.Atoi(emailId)
+		if err != nil {
+			slog.Error("invalid outbox email id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid outbox email id", nil)
+			return
+		}
+
+		err = outboxService.RedriveOutboxEmail(ctx, parsedEmailId)
+		if err != nil {
+			slog.Error("failed to redrive outbox email", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "outbox email queued for redelivery", nil)
+	}
+}
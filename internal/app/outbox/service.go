@@ -0,0 +1,179 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/worker"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+type service struct {
+	outboxRepository repository.OutboxRepository
+	emailService     email.Service
+	templateRenderer email.TemplateRenderer
+	cfg              config.Outbox
+}
+
+type Service interface {
+	EnqueueEmail(ctx context.Context, tx *sql.Tx, outboxEmail Email) (err error)
+	DeliverDueEmails(ctx context.Context) (err error)
+	StartWorker(ctx context.Context)
+	GetOutboxEmails(ctx context.Context, status string, pageRequest pagination.Request) (emails PaginatedOutboxEmails, err error)
+	RedriveOutboxEmail(ctx context.Context, emailId int) (err error)
+}
+
+func NewService(outboxRepository repository.OutboxRepository, emailService email.Service, templateRenderer email.TemplateRenderer, cfg config.Outbox) Service {
+	return &service{
+		outboxRepository: outboxRepository,
+		emailService:     emailService,
+		templateRenderer: templateRenderer,
+		cfg:              cfg,
+	}
+}
+
+// EnqueueEmail renders the email at enqueue time so that the outbox row holds exactly what will be delivered.
+func (s *service) EnqueueEmail(ctx context.Context, tx *sql.Tx, outboxEmail Email) (err error) {
+	rendered, err := s.templateRenderer.Render(outboxEmail.Template, outboxEmail.Locale, outboxEmail.Data)
+	if err != nil {
+		slog.Error("failed to render email template", "template", outboxEmail.Template, "error", err)
+		return err
+	}
+
+	emailData := repository.CreateOutboxEmailData{
+		ToName:           outboxEmail.ToName,
+		ToEmail:          outboxEmail.ToEmail,
+		Subject:          rendered.Subject,
+		PlainTextContent: rendered.PlainTextContent,
+		HTMLContent:      rendered.HTMLContent,
+		MaxAttempts:      s.cfg.MaxAttempts,
+		Sensitive:        outboxEmail.Sensitive,
+	}
+	err = s.outboxRepository.CreateOutboxEmail(ctx, tx, emailData)
+	if err != nil {
+		slog.Error("failed to enqueue email", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeliverDueEmails(ctx context.Context) (err error) {
+	emails, err := s.outboxRepository.ClaimDueOutboxEmails(ctx, nil, s.cfg.BatchSize, s.cfg.LeaseDuration)
+	if err != nil {
+		slog.Error("failed to claim due outbox emails", "error", err)
+		return err
+	}
+
+	// The claimed rows can be claimed again once their lease expires, so no send may outlive it.
+	leaseCtx, cancel := context.WithTimeout(ctx, s.cfg.LeaseDuration)
+	defer cancel()
+
+	for _, outboxEmail := range emails {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if leaseCtx.Err() != nil {
+			slog.Warn("outbox lease expired before the batch was delivered", "emailId", outboxEmail.Id)
+			return nil
+		}
+
+		sendErr := s.emailService.SendEmail(leaseCtx, email.Message{
+			ToName:           outboxEmail.ToName,
+			ToEmail:          outboxEmail.ToEmail,
+			Subject:          outboxEmail.Subject,
+			PlainTextContent: outboxEmail.PlainTextContent,
+			HTMLContent:      outboxEmail.HTMLContent,
+		})
+		if sendErr == nil {
+			err = s.outboxRepository.MarkOutboxEmailSent(ctx, nil, outboxEmail.Id)
+			if err != nil {
+				slog.Error("failed to mark outbox email as sent", "emailId", outboxEmail.Id, "error", err)
+			}
+			continue
+		}
+		// Another worker may own the row by now, so the aborted send is left for it to retry.
+		if leaseCtx.Err() != nil && ctx.Err() == nil {
+			slog.Warn("outbox lease expired while sending email", "emailId", outboxEmail.Id, "error", sendErr)
+			return nil
+		}
+
+		status := Pending
+		nextAttemptAt := time.Now().Add(s.backoff(outboxEmail.Attempts))
+		if outboxEmail.Attempts >= outboxEmail.MaxAttempts {
+			status = Dead
+		}
+		slog.Warn("failed to deliver outbox email", "emailId", outboxEmail.Id, "attempt", outboxEmail.Attempts, "status", status, "error", sendErr)
+
+		lastError := sendErr.Error()
+		if len(lastError) > maxLastErrorLength {
+			lastError = lastError[:maxLastErrorLength]
+		}
+		err = s.outboxRepository.MarkOutboxEmailFailed(ctx, nil, outboxEmail.Id, status, nextAttemptAt, lastError)
+		if err != nil {
+			slog.Error("failed to record outbox email delivery failure", "emailId", outboxEmail.Id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) StartWorker(ctx context.Context) {
+	worker.RunPeriodically(ctx, "email-outbox", s.cfg.PollInterval, s.DeliverDueEmails)
+}
+
+func (s *service) GetOutboxEmails(ctx context.Context, status string, pageRequest pagination.Request) (emails PaginatedOutboxEmails, err error) {
+	if status != "" {
+		if _, ok := AvailableStatuses[status]; !ok {
+			slog.Error("invalid outbox status provided", "status", status)
+			return PaginatedOutboxEmails{}, apperrors.ErrInvalidQueryParams
+		}
+	}
+
+	pageParams, err := pageRequest.Params(repository.OutboxSortNewest)
+	if err != nil {
+		return PaginatedOutboxEmails{}, err
+	}
+
+	emailList, pageInfo, err := s.outboxRepository.GetOutboxEmails(ctx, nil, repository.GetOutboxEmailsParams{Status: status, Page: pageParams})
+	if err != nil {
+		slog.Error("failed to get outbox emails", "error", err)
+		return PaginatedOutboxEmails{}, err
+	}
+
+	emailData := make([]OutboxEmail, len(emailList))
+	for i, e := range emailList {
+		emailData[i] = mapOutboxEmailRepoToOutboxEmail(e)
+	}
+
+	return PaginatedOutboxEmails{
+		Data:       emailData,
+		Pagination: pagination.NewPage(pageRequest, repository.OutboxSortNewest, pageInfo),
+	}, nil
+}
+
+func (s *service) RedriveOutboxEmail(ctx context.Context, emailId int) (err error) {
+	err = s.outboxRepository.RedriveOutboxEmail(ctx, nil, emailId)
+	if err != nil {
+		slog.Error("failed to redrive outbox email", "emailId", emailId, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// backoff doubles the delay for every attempt already made, capped at the configured maximum.
+func (s *service) backoff(attempts int) time.Duration {
+	delay := s.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.cfg.MaxBackoff)
+}
@@ -4,6 +4,9 @@ import (
 	"net/http"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/booking"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/storage"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/user"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/vehicle"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
@@ -12,6 +15,7 @@ import (
 
 func NewRouter(deps Dependencies) http.Handler {
 	router := http.NewServeMux()
+	authenticationMiddleware := middleware.AuthenticationMiddleware(deps.UserService)
 
 	router.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
 		response.WriteJson(w, http.StatusOK, "Wheelio server is up and running..", nil)
@@ -26,7 +30,7 @@ func NewRouter(deps Dependencies) http.Handler {
 		"GET /api/v1/auth/user",
 		middleware.ChainMiddleware(
 			user.GetLoggedInUser(deps.UserService),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -34,14 +38,36 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			user.UpgradeUserRoleToHost(deps.UserService),
 			middleware.AuthorizationMiddleware(user.Seeker),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
-		"POST /api/v1/auth/access/refresh",
+		"PATCH /api/v1/auth/user/language",
 		middleware.ChainMiddleware(
-			user.RefreshAccessToken(deps.UserService),
-			middleware.AuthenticationMiddleware,
+			user.UpdatePreferredLanguage(deps.UserService),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc("POST /api/v1/auth/access/refresh", user.RefreshAccessToken(deps.UserService))
+	router.HandleFunc(
+		"POST /api/v1/auth/logout",
+		middleware.ChainMiddleware(
+			user.Logout(deps.UserService),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/auth/logout/all",
+		middleware.ChainMiddleware(
+			user.LogoutAllSessions(deps.UserService),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"GET /api/v1/auth/sessions",
+		middleware.ChainMiddleware(
+			user.GetActiveSessions(deps.UserService),
+			authenticationMiddleware,
 		),
 	)
 
@@ -50,7 +76,7 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			vehicle.CreateVehicle(deps.VehicleService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -58,7 +84,7 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			vehicle.UpdateVehicle(deps.VehicleService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -66,30 +92,114 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			vehicle.SoftDeleteVehicle(deps.VehicleService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/vehicles/{id}/images",
+		middleware.ChainMiddleware(
+			vehicle.AddVehicleImage(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"DELETE /api/v1/vehicles/{id}/images/{imageId}",
+		middleware.ChainMiddleware(
+			vehicle.DeleteVehicleImage(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"PUT /api/v1/vehicles/{id}/images/order",
+		middleware.ChainMiddleware(
+			vehicle.ReorderVehicleImages(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"PATCH /api/v1/vehicles/{id}/images/{imageId}/featured",
+		middleware.ChainMiddleware(
+			vehicle.SetFeaturedVehicleImage(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"PATCH /api/v1/vehicles/{id}/pause",
+		middleware.ChainMiddleware(
+			vehicle.PauseVehicle(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"PATCH /api/v1/vehicles/{id}/unpause",
+		middleware.ChainMiddleware(
+			vehicle.UnpauseVehicle(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"GET /api/v1/vehicles/{id}/blackouts",
+		middleware.ChainMiddleware(
+			vehicle.GetVehicleBlackouts(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/vehicles/{id}/blackouts",
+		middleware.ChainMiddleware(
+			vehicle.CreateVehicleBlackout(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"DELETE /api/v1/vehicles/{id}/blackouts/{blackoutId}",
+		middleware.ChainMiddleware(
+			vehicle.DeleteVehicleBlackout(deps.VehicleService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
 		"POST /api/v1/vehicles/image/upload/signed-url",
 		middleware.ChainMiddleware(
 			vehicle.GenerateSignedVehicleImageUploadURL(deps.VehicleService),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
 		"GET /api/v1/vehicles/{id}",
 		vehicle.GetVehicleById(deps.VehicleService),
 	)
+	router.HandleFunc(
+		"GET /api/v1/vehicles/{id}/availability",
+		vehicle.GetVehicleAvailability(deps.VehicleService),
+	)
 	router.HandleFunc(
 		"GET /api/v1/vehicles",
 		vehicle.GetVehicles(deps.VehicleService),
 	)
+	router.HandleFunc(
+		"GET /api/v1/vehicles/autocomplete",
+		vehicle.GetVehicleSuggestions(deps.VehicleService),
+	)
+	router.HandleFunc(
+		"GET /api/v1/vehicles/cancellation-policies",
+		vehicle.GetCancellationPolicies(),
+	)
 	router.HandleFunc(
 		"GET /api/v1/vehicles/host",
 		middleware.ChainMiddleware(
 			vehicle.GetVehiclesForHost(deps.VehicleService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 
@@ -97,14 +207,14 @@ func NewRouter(deps Dependencies) http.Handler {
 		"POST /api/v1/bookings",
 		middleware.ChainMiddleware(
 			booking.CreateBooking(deps.BookingService),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
 		"PATCH /api/v1/bookings/{id}/cancel",
 		middleware.ChainMiddleware(
 			booking.CancelBooking(deps.BookingService),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -112,7 +222,7 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			booking.ConfirmPickup(deps.BookingService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -120,7 +230,7 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			booking.InitiateReturn(deps.BookingService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -128,14 +238,21 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			booking.ConfirmReturn(deps.BookingService),
 			middleware.AuthorizationMiddleware(user.Seeker),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/bookings/{id}/otp/resend",
+		middleware.ChainMiddleware(
+			booking.ResendOtp(deps.BookingService),
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
 		"GET /api/v1/bookings",
 		middleware.ChainMiddleware(
 			booking.GetSeekerBookings(deps.BookingService),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
@@ -143,14 +260,115 @@ func NewRouter(deps Dependencies) http.Handler {
 		middleware.ChainMiddleware(
 			booking.GetHostBookings(deps.BookingService),
 			middleware.AuthorizationMiddleware(user.Host),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
 		),
 	)
 	router.HandleFunc(
 		"GET /api/v1/bookings/{id}",
 		middleware.ChainMiddleware(
 			booking.GetBookingDetailsById(deps.BookingService),
-			middleware.AuthenticationMiddleware,
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"GET /api/v1/bookings/{id}/history",
+		middleware.ChainMiddleware(
+			booking.GetBookingStatusHistory(deps.BookingService),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"GET /api/v1/bookings/{id}/cancellation",
+		middleware.ChainMiddleware(
+			booking.GetBookingCancellation(deps.BookingService),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/bookings/{id}/extend",
+		middleware.ChainMiddleware(
+			booking.ExtendBooking(deps.BookingService),
+			middleware.AuthorizationMiddleware(user.Seeker),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/bookings/{id}/reschedule",
+		middleware.ChainMiddleware(
+			booking.RescheduleBooking(deps.BookingService),
+			middleware.AuthorizationMiddleware(user.Seeker),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"GET /api/v1/bookings/{id}/changes",
+		middleware.ChainMiddleware(
+			booking.GetBookingChangeRequests(deps.BookingService),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"PATCH /api/v1/bookings/{id}/changes/{requestId}/approve",
+		middleware.ChainMiddleware(
+			booking.ApproveBookingChange(deps.BookingService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"PATCH /api/v1/bookings/{id}/changes/{requestId}/decline",
+		middleware.ChainMiddleware(
+			booking.DeclineBookingChange(deps.BookingService),
+			middleware.AuthorizationMiddleware(user.Host),
+			authenticationMiddleware,
+		),
+	)
+
+	if localStore, ok := deps.StorageService.(storage.LocalObjectStore); ok {
+		router.HandleFunc("PUT "+storage.LocalObjectRoute+"{path...}", storage.UploadLocalObject(localStore))
+		router.HandleFunc("GET "+storage.LocalObjectRoute+"{path...}", storage.DownloadLocalObject(localStore))
+	}
+
+	router.HandleFunc(
+		"GET /api/v1/admin/outbox",
+		middleware.ChainMiddleware(
+			outbox.GetOutboxEmails(deps.OutboxService),
+			middleware.AuthorizationMiddleware(user.Admin),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"POST /api/v1/admin/outbox/{id}/redrive",
+		middleware.ChainMiddleware(
+			outbox.RedriveOutboxEmail(deps.OutboxService),
+			middleware.AuthorizationMiddleware(user.Admin),
+			authenticationMiddleware,
+		),
+	)
+
+	router.HandleFunc(
+		"GET /api/v1/admin/hosts/{id}/cancellations",
+		middleware.ChainMiddleware(
+			booking.GetHostCancellations(deps.BookingService),
+			middleware.AuthorizationMiddleware(user.Admin),
+			authenticationMiddleware,
+		),
+	)
+
+	router.HandleFunc(
+		"GET /api/v1/admin/email/templates",
+		middleware.ChainMiddleware(
+			email.GetTemplates(deps.EmailTemplates),
+			middleware.AuthorizationMiddleware(user.Admin),
+			authenticationMiddleware,
+		),
+	)
+	router.HandleFunc(
+		"GET /api/v1/admin/email/templates/{name}/preview",
+		middleware.ChainMiddleware(
+			email.PreviewTemplate(deps.EmailTemplates),
+			middleware.AuthorizationMiddleware(user.Admin),
+			authenticationMiddleware,
 		),
 	)
 
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/cryptokit"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/worker"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+// Job is run on every poll interval by whichever replica holds its lease. Jobs must be safe
+// to run again on work they have already done, as a lease can change hands mid-run.
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type service struct {
+	jobLeaseRepository repository.JobLeaseRepository
+	jobs               []Job
+	owner              string
+	cfg                config.Scheduler
+}
+
+type Service interface {
+	StartWorker(ctx context.Context)
+}
+
+func NewService(jobLeaseRepository repository.JobLeaseRepository, cfg config.Scheduler, jobs ...Job) Service {
+	return &service{
+		jobLeaseRepository: jobLeaseRepository,
+		jobs:               jobs,
+		owner:              leaseOwner(),
+		cfg:                cfg,
+	}
+}
+
+func (s *service) StartWorker(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.RunPeriodically(ctx, job.Name, s.cfg.PollInterval, s.runLeased(job))
+		}()
+	}
+
+	wg.Wait()
+}
+
+// runLeased skips the run unless this replica holds the job's lease. The holder renews the
+// lease on every run, so other replicas only take over after it has stopped for lease_duration.
+func (s *service) runLeased(job Job) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		acquired, err := s.jobLeaseRepository.AcquireJobLease(ctx, nil, job.Name, s.owner, s.cfg.LeaseDuration)
+		if err != nil {
+			return err
+		}
+
+		if !acquired {
+			slog.Debug("job lease is held by another replica", "job", job.Name)
+			return nil
+		}
+
+		return job.Run(ctx)
+	}
+}
+
+// leaseOwner identifies this process among the replicas sharing the database.
+func leaseOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	suffix, err := cryptokit.GenerateSecureToken(4)
+	if err != nil {
+		suffix = "0"
+	}
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), suffix)
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"cloud.google.com/go/storage"
+	firebase "firebase.google.com/go"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"google.golang.org/api/option"
+)
+
+const firebaseAccessURLFormat = "https://firebasestorage.googleapis.com/v0/b/%s/o/%s?alt=media"
+
+type firebaseService struct {
+	bucket     *storage.BucketHandle
+	bucketName string
+	accessURLs accessURLOptions
+}
+
+func newFirebaseService(ctx context.Context, cfg config.FirebaseStorage, accessURLs accessURLOptions) (Service, error) {
+	bucket, err := initFirebaseStorage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &firebaseService{bucket: bucket, bucketName: cfg.BucketName, accessURLs: accessURLs}, nil
+}
+
+func initFirebaseStorage(ctx context.Context, cfg config.FirebaseStorage) (*storage.BucketHandle, error) {
+	opt := option.WithCredentialsFile(cfg.CredentialsFile)
+
+	app, err := firebase.NewApp(ctx, nil, opt)
+	if err != nil {
+		slog.Error("failed to initialize firebase app", "error", err)
+		return nil, err
+	}
+
+	client, err := app.Storage(ctx)
+	if err != nil {
+		slog.Error("failed to create firebase client", "error", err)
+
+		return nil, err
+	}
+
+	bucket, err := client.Bucket(cfg.BucketName)
+	if err != nil {
+		slog.Error("failed to initialize firebase bucket", "error", err)
+		return nil, err
+	}
+
+	err = configureCORS(ctx, bucket)
+	if err != nil {
+		slog.Error("failed to configure CORS, uploads may not work from browser", "error", err)
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+func configureCORS(ctx context.Context, bucket *storage.BucketHandle) error {
+	cors := []storage.CORS{
+		{
+			MaxAge:          3600,
+			Methods:         []string{"PUT", "POST", "GET", "HEAD", "DELETE", "OPTIONS"},
+			Origins:         []string{"*"},
+			ResponseHeaders: []string{"Content-Type", "x-goog-meta-*"},
+		},
+	}
+
+	if _, err := bucket.Update(ctx, storage.BucketAttrsToUpdate{
+		CORS: cors,
+	}); err != nil {
+		slog.Error("failed to set CORS configuration", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *firebaseService) GenerateSignedUploadURL(ctx context.Context, objectPath, contentType string, expires time.Duration) (string, error) {
+	opts := &storage.SignedURLOptions{
+		Method:      "PUT",
+		ContentType: contentType,
+		Expires:     time.Now().Add(expires),
+		Headers: []string{
+			"Access-Control-Allow-Origin: *",
+			"Access-Control-Allow-Methods: PUT, POST, GET, HEAD, DELETE, OPTIONS",
+		},
+	}
+
+	url, err := s.bucket.SignedURL(objectPath, opts)
+	if err != nil {
+		slog.Error("failed to generate signed url for upload", "error", err)
+		return "", err
+	}
+
+	return url, nil
+}
+
+func (s *firebaseService) GenerateSignedDownloadURL(ctx context.Context, objectPath string, expires time.Duration) (string, error) {
+	opts := &storage.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(expires),
+	}
+
+	url, err := s.bucket.SignedURL(objectPath, opts)
+	if err != nil {
+		slog.Error("failed to generate signed url for download", "error", err)
+		return "", err
+	}
+
+	return url, nil
+}
+
+func (s *firebaseService) StatObject(ctx context.Context, objectPath string) (ObjectInfo, error) {
+	attrs, err := s.bucket.Object(objectPath).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return ObjectInfo{}, apperrors.ErrObjectNotFound
+		}
+		slog.Error("failed to get object attributes", "objectPath", objectPath, "error", err)
+		return ObjectInfo{}, apperrors.ErrInternalServer
+	}
+
+	return ObjectInfo{Size: attrs.Size, ContentType: attrs.ContentType, UpdatedAt: attrs.Updated}, nil
+}
+
+func (s *firebaseService) DeleteObject(ctx context.Context, objectPath string) error {
+	err := s.bucket.Object(objectPath).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		slog.Error("failed to delete object", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *firebaseService) GetObject(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	reader, err := s.bucket.Object(objectPath).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, apperrors.ErrObjectNotFound
+		}
+		slog.Error("failed to open object", "objectPath", objectPath, "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return reader, nil
+}
+
+func (s *firebaseService) PutObject(ctx context.Context, objectPath, contentType string, content io.Reader) error {
+	writer := s.bucket.Object(objectPath).NewWriter(ctx)
+	writer.ContentType = contentType
+
+	_, err := io.Copy(writer, content)
+	closeErr := writer.Close()
+	if err != nil || closeErr != nil {
+		slog.Error("failed to write object", "objectPath", objectPath, "error", errors.Join(err, closeErr))
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *firebaseService) PublicURL(objectPath string) string {
+	return s.accessURLs.publicURL(s, objectPath)
+}
+
+func (s *firebaseService) AccessURL(ctx context.Context, objectRef string) (string, error) {
+	return s.accessURLs.accessURL(ctx, s, objectRef)
+}
+
+func (s *firebaseService) defaultPublicURL(objectPath string) string {
+	return fmt.Sprintf(firebaseAccessURLFormat, s.bucketName, url.PathEscape(objectPath))
+}
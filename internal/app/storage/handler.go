@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/response"
+)
+
+// inlineMediaTypes are the raster image types that local downloads may display inline.
+var inlineMediaTypes = map[string]struct{}{
+	"image/jpeg": {},
+	"image/png":  {},
+	"image/webp": {},
+	"image/gif":  {},
+}
+
+func UploadLocalObject(localStore LocalObjectStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		objectPath := r.PathValue("path")
+
+		err := localStore.VerifySignedURL(http.MethodPut, objectPath, r.URL.Query())
+		if err != nil {
+			slog.Error("invalid signed upload url", "objectPath", objectPath)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		if contentType := r.URL.Query().Get("contentType"); contentType != "" && r.Header.Get("Content-Type") != contentType {
+			slog.Error("upload content type does not match signed url", "objectPath", objectPath)
+			response.WriteJson(w, http.StatusForbidden, apperrors.ErrInvalidSignedURL.Error(), nil)
+			return
+		}
+
+		// Only the signed content type is stored; an unsigned one is served as opaque bytes.
+		r.Body = http.MaxBytesReader(w, r.Body, localStore.MaxUploadBytes())
+		err = localStore.WriteObject(objectPath, r.URL.Query().Get("contentType"), r.Body)
+		if err != nil {
+			slog.Error("failed to store uploaded object", "objectPath", objectPath, "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "object uploaded successfully", nil)
+	}
+}
+
+func DownloadLocalObject(localStore LocalObjectStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		objectPath := r.PathValue("path")
+
+		if localStore.SignedDownloadsRequired() {
+			err := localStore.VerifySignedURL(http.MethodGet, objectPath, r.URL.Query())
+			if err != nil {
+				slog.Error("invalid signed download url", "objectPath", objectPath)
+				status, errorMessage := apperrors.MapError(err)
+				response.WriteJson(w, status, errorMessage, nil)
+				return
+			}
+		}
+
+		file, info, err := localStore.OpenObject(objectPath)
+		if err != nil {
+			slog.Error("failed to open object", "objectPath", objectPath, "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+		defer file.Close()
+
+		// Objects are uploaded by users, so browsers must neither guess their type nor render
+		// anything but images inline.
+		disposition := "attachment"
+		if mediaType, _, err := mime.ParseMediaType(info.ContentType); err == nil {
+			if _, ok := inlineMediaTypes[mediaType]; ok {
+				disposition = "inline"
+			}
+		}
+		w.Header().Set("Content-Type", info.ContentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": path.Base(objectPath)}))
+		http.ServeContent(w, r, path.Base(objectPath), info.UpdatedAt, file)
+	}
+}
@@ -0,0 +1,328 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+// LocalObjectRoute is where the HTTP server serves objects of the local storage driver.
+const LocalObjectRoute = "/api/v1/storage/objects/"
+
+// localMetadataDirectory holds the content type of every local object, in a file mirroring the
+// object path. Object paths may not start a segment with a dot, so it is never served itself.
+const localMetadataDirectory = ".metadata"
+
+// defaultContentType is served for objects stored without a content type.
+const defaultContentType = "application/octet-stream"
+
+// LocalObjectStore is implemented by the local driver, whose signed URLs point back at this
+// server instead of at a cloud provider.
+type LocalObjectStore interface {
+	Service
+	VerifySignedURL(method, objectPath string, query url.Values) error
+	SignedDownloadsRequired() bool
+	WriteObject(objectPath, contentType string, content io.Reader) error
+	OpenObject(objectPath string) (*os.File, ObjectInfo, error)
+	MaxUploadBytes() int64
+}
+
+type localService struct {
+	directory      string
+	publicBaseURL  string
+	signingSecret  []byte
+	maxUploadBytes int64
+	accessURLs     accessURLOptions
+}
+
+func newLocalService(cfg config.LocalStorage, accessURLs accessURLOptions) (Service, error) {
+	err := os.MkdirAll(cfg.Directory, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &localService{
+		directory:      cfg.Directory,
+		publicBaseURL:  strings.TrimSuffix(cfg.PublicBaseURL, "/"),
+		signingSecret:  []byte(cfg.SigningSecret),
+		maxUploadBytes: cfg.MaxUploadBytes,
+		accessURLs:     accessURLs,
+	}, nil
+}
+
+func (s *localService) GenerateSignedUploadURL(ctx context.Context, objectPath, contentType string, expires time.Duration) (string, error) {
+	return s.signedURL(http.MethodPut, objectPath, contentType, expires), nil
+}
+
+func (s *localService) GenerateSignedDownloadURL(ctx context.Context, objectPath string, expires time.Duration) (string, error) {
+	return s.signedURL(http.MethodGet, objectPath, "", expires), nil
+}
+
+func (s *localService) StatObject(ctx context.Context, objectPath string) (ObjectInfo, error) {
+	file, info, err := s.OpenObject(objectPath)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	file.Close()
+
+	return info, nil
+}
+
+func (s *localService) DeleteObject(ctx context.Context, objectPath string) error {
+	filePath, err := s.resolvePath(objectPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Error("failed to delete local object", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	err = os.Remove(s.metadataPath(objectPath))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Error("failed to delete local object metadata", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *localService) GetObject(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	file, _, err := s.OpenObject(objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *localService) PutObject(ctx context.Context, objectPath, contentType string, content io.Reader) error {
+	return s.WriteObject(objectPath, contentType, content)
+}
+
+func (s *localService) VerifySignedURL(method, objectPath string, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return apperrors.ErrInvalidSignedURL
+	}
+
+	if query.Get("method") != method {
+		return apperrors.ErrInvalidSignedURL
+	}
+
+	expected := s.signature(method, objectPath, query.Get("contentType"), expires)
+	provided, err := hex.DecodeString(query.Get("signature"))
+	if err != nil || !hmac.Equal(expected, provided) {
+		return apperrors.ErrInvalidSignedURL
+	}
+
+	return nil
+}
+
+// WriteObject writes to a temporary file first so that readers never observe a partial upload.
+// The content type is stored before the object is moved into place, so that an object is never
+// served without the type it was uploaded with.
+func (s *localService) WriteObject(objectPath, contentType string, content io.Reader) error {
+	filePath, err := s.resolvePath(objectPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		slog.Error("failed to create local object directory", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		slog.Error("failed to create temporary upload file", "error", err)
+		return apperrors.ErrInternalServer
+	}
+	defer os.Remove(tempFile.Name())
+
+	written, err := io.Copy(tempFile, io.LimitReader(content, s.maxUploadBytes+1))
+	closeErr := tempFile.Close()
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return apperrors.ErrObjectTooLarge
+	}
+	if err != nil || closeErr != nil {
+		slog.Error("failed to write local object", "objectPath", objectPath, "error", errors.Join(err, closeErr))
+		return apperrors.ErrInternalServer
+	}
+
+	if written > s.maxUploadBytes {
+		return apperrors.ErrObjectTooLarge
+	}
+
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	err = s.writeMetadata(objectPath, contentType)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempFile.Name(), filePath)
+	if err != nil {
+		slog.Error("failed to move uploaded local object into place", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *localService) OpenObject(objectPath string) (*os.File, ObjectInfo, error) {
+	filePath, err := s.resolvePath(objectPath)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ObjectInfo{}, apperrors.ErrObjectNotFound
+		}
+		slog.Error("failed to open local object", "objectPath", objectPath, "error", err)
+		return nil, ObjectInfo{}, apperrors.ErrInternalServer
+	}
+
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		file.Close()
+		return nil, ObjectInfo{}, apperrors.ErrObjectNotFound
+	}
+
+	// Objects stored before content types were recorded have no metadata and are served as
+	// opaque bytes rather than with a type sniffed from uploader-controlled content.
+	contentType := defaultContentType
+	metadata, err := os.ReadFile(s.metadataPath(objectPath))
+	if err == nil {
+		contentType = string(metadata)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		file.Close()
+		slog.Error("failed to read local object metadata", "objectPath", objectPath, "error", err)
+		return nil, ObjectInfo{}, apperrors.ErrInternalServer
+	}
+
+	return file, ObjectInfo{
+		Size:        stat.Size(),
+		ContentType: contentType,
+		UpdatedAt:   stat.ModTime(),
+	}, nil
+}
+
+func (s *localService) PublicURL(objectPath string) string {
+	return s.accessURLs.publicURL(s, objectPath)
+}
+
+func (s *localService) AccessURL(ctx context.Context, objectRef string) (string, error) {
+	return s.accessURLs.accessURL(ctx, s, objectRef)
+}
+
+func (s *localService) defaultPublicURL(objectPath string) string {
+	return s.publicBaseURL + LocalObjectRoute + escapeObjectPath(objectPath)
+}
+
+// SignedDownloadsRequired mirrors a private bucket: downloads only need a signature when
+// access URLs are configured to be signed.
+func (s *localService) SignedDownloadsRequired() bool {
+	return s.accessURLs.signed
+}
+
+func (s *localService) MaxUploadBytes() int64 {
+	return s.maxUploadBytes
+}
+
+func (s *localService) signedURL(method, objectPath, contentType string, expires time.Duration) string {
+	expiresAt := time.Now().Add(expires).Unix()
+
+	query := url.Values{}
+	query.Set("method", method)
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	if contentType != "" {
+		query.Set("contentType", contentType)
+	}
+	query.Set("signature", hex.EncodeToString(s.signature(method, objectPath, contentType, expiresAt)))
+
+	return s.publicBaseURL + LocalObjectRoute + escapeObjectPath(objectPath) + "?" + query.Encode()
+}
+
+func (s *localService) signature(method, objectPath, contentType string, expiresAt int64) []byte {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d", method, objectPath, contentType, expiresAt)
+	return mac.Sum(nil)
+}
+
+// resolvePath maps an object path onto the storage directory and rejects paths that would escape it.
+// Segments starting with a dot are reserved for metadata and temporary uploads.
+func (s *localService) resolvePath(objectPath string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+objectPath), "/")
+	if cleaned == "" || cleaned != objectPath {
+		return "", apperrors.ErrObjectNotFound
+	}
+
+	for _, segment := range strings.Split(cleaned, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return "", apperrors.ErrObjectNotFound
+		}
+	}
+
+	return filepath.Join(s.directory, filepath.FromSlash(cleaned)), nil
+}
+
+// metadataPath expects an object path already accepted by resolvePath.
+func (s *localService) metadataPath(objectPath string) string {
+	return filepath.Join(s.directory, localMetadataDirectory, filepath.FromSlash(objectPath))
+}
+
+func (s *localService) writeMetadata(objectPath, contentType string) error {
+	metadataPath := s.metadataPath(objectPath)
+
+	err := os.MkdirAll(filepath.Dir(metadataPath), 0o755)
+	if err != nil {
+		slog.Error("failed to create local metadata directory", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(metadataPath), ".metadata-*")
+	if err != nil {
+		slog.Error("failed to create temporary metadata file", "error", err)
+		return apperrors.ErrInternalServer
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = io.WriteString(tempFile, contentType)
+	closeErr := tempFile.Close()
+	if err != nil || closeErr != nil {
+		slog.Error("failed to write local object metadata", "objectPath", objectPath, "error", errors.Join(err, closeErr))
+		return apperrors.ErrInternalServer
+	}
+
+	err = os.Rename(tempFile.Name(), metadataPath)
+	if err != nil {
+		slog.Error("failed to move local object metadata into place", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
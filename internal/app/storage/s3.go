@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+const (
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3RequestTimeout   = 15 * time.Second
+	// Presigned URLs used for the server's own HEAD and DELETE calls only need to outlive the request.
+	s3InternalURLExpiry = time.Minute
+)
+
+// s3Service talks to any S3-compatible API (AWS S3, MinIO, R2, ...) using query-string
+// signed (SigV4 presigned) URLs, so no SDK is needed for the handful of operations we use.
+type s3Service struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	accessKeyId     string
+	secretAccessKey string
+	usePathStyle    bool
+	client          *http.Client
+	accessURLs      accessURLOptions
+}
+
+func newS3Service(cfg config.S3Storage, accessURLs accessURLOptions) (Service, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %q", cfg.Endpoint)
+	}
+
+	return &s3Service{
+		endpoint:        endpoint,
+		region:          cfg.Region,
+		bucket:          cfg.Bucket,
+		accessKeyId:     cfg.AccessKeyId,
+		secretAccessKey: cfg.SecretAccessKey,
+		usePathStyle:    cfg.UsePathStyle,
+		client:          &http.Client{Timeout: s3RequestTimeout},
+		accessURLs:      accessURLs,
+	}, nil
+}
+
+func (s *s3Service) GenerateSignedUploadURL(ctx context.Context, objectPath, contentType string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodPut, objectPath, map[string]string{"content-type": contentType}, expires, time.Now()), nil
+}
+
+func (s *s3Service) GenerateSignedDownloadURL(ctx context.Context, objectPath string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, objectPath, nil, expires, time.Now()), nil
+}
+
+func (s *s3Service) StatObject(ctx context.Context, objectPath string) (ObjectInfo, error) {
+	signedUrl := s.presign(http.MethodHead, objectPath, nil, s3InternalURLExpiry, time.Now())
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, signedUrl, nil)
+	if err != nil {
+		slog.Error("failed to build s3 head request", "error", err)
+		return ObjectInfo{}, apperrors.ErrInternalServer
+	}
+
+	res, err := s.client.Do(request)
+	if err != nil {
+		slog.Error("failed to get s3 object attributes", "objectPath", objectPath, "error", err)
+		return ObjectInfo{}, apperrors.ErrInternalServer
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ObjectInfo{}, apperrors.ErrObjectNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		slog.Error("unexpected s3 head response", "objectPath", objectPath, "status", res.StatusCode)
+		return ObjectInfo{}, apperrors.ErrInternalServer
+	}
+
+	size, _ := strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64)
+	updatedAt, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+
+	return ObjectInfo{Size: size, ContentType: res.Header.Get("Content-Type"), UpdatedAt: updatedAt}, nil
+}
+
+func (s *s3Service) DeleteObject(ctx context.Context, objectPath string) error {
+	signedUrl := s.presign(http.MethodDelete, objectPath, nil, s3InternalURLExpiry, time.Now())
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, signedUrl, nil)
+	if err != nil {
+		slog.Error("failed to build s3 delete request", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	res, err := s.client.Do(request)
+	if err != nil {
+		slog.Error("failed to delete s3 object", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+	defer res.Body.Close()
+
+	// S3 answers 204 for deletes, including deletes of keys that do not exist.
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		slog.Error("unexpected s3 delete response", "objectPath", objectPath, "status", res.StatusCode)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *s3Service) GetObject(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	signedUrl := s.presign(http.MethodGet, objectPath, nil, s3InternalURLExpiry, time.Now())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, signedUrl, nil)
+	if err != nil {
+		slog.Error("failed to build s3 get request", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	// The shared client's timeout would cut off large downloads, so reads rely on ctx instead.
+	res, err := http.DefaultClient.Do(request)
+	if err != nil {
+		slog.Error("failed to get s3 object", "objectPath", objectPath, "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, apperrors.ErrObjectNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		slog.Error("unexpected s3 get response", "objectPath", objectPath, "status", res.StatusCode)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return res.Body, nil
+}
+
+func (s *s3Service) PutObject(ctx context.Context, objectPath, contentType string, content io.Reader) error {
+	// S3 rejects chunked uploads on presigned URLs, so the body is buffered to send a Content-Length.
+	body, err := io.ReadAll(content)
+	if err != nil {
+		slog.Error("failed to read s3 object content", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	signedUrl := s.presign(http.MethodPut, objectPath, map[string]string{"content-type": contentType}, s3InternalURLExpiry, time.Now())
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, signedUrl, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("failed to build s3 put request", "error", err)
+		return apperrors.ErrInternalServer
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	res, err := s.client.Do(request)
+	if err != nil {
+		slog.Error("failed to put s3 object", "objectPath", objectPath, "error", err)
+		return apperrors.ErrInternalServer
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		slog.Error("unexpected s3 put response", "objectPath", objectPath, "status", res.StatusCode)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *s3Service) PublicURL(objectPath string) string {
+	return s.accessURLs.publicURL(s, objectPath)
+}
+
+func (s *s3Service) AccessURL(ctx context.Context, objectRef string) (string, error) {
+	return s.accessURLs.accessURL(ctx, s, objectRef)
+}
+
+func (s *s3Service) defaultPublicURL(objectPath string) string {
+	return s.objectURL(objectPath).String()
+}
+
+func (s *s3Service) objectURL(objectPath string) *url.URL {
+	objectURL := *s.endpoint
+	basePath := strings.TrimSuffix(objectURL.Path, "/")
+	if s.usePathStyle {
+		objectURL.Path = basePath + "/" + s.bucket + "/" + objectPath
+		objectURL.RawPath = basePath + "/" + s3Escape(s.bucket) + "/" + s3EscapePath(objectPath)
+	} else {
+		objectURL.Host = s.bucket + "." + objectURL.Host
+		objectURL.Path = basePath + "/" + objectPath
+		objectURL.RawPath = basePath + "/" + s3EscapePath(objectPath)
+	}
+
+	return &objectURL
+}
+
+// presign builds a SigV4 query-string signed URL. Headers passed in are signed, so the
+// client has to send them verbatim, which is how the upload content type is enforced.
+func (s *s3Service) presign(method, objectPath string, headers map[string]string, expires time.Duration, now time.Time) string {
+	objectURL := s.objectURL(objectPath)
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", shortDate, s.region)
+
+	signedHeaders := map[string]string{"host": objectURL.Host}
+	for name, value := range headers {
+		if value != "" {
+			signedHeaders[strings.ToLower(name)] = strings.TrimSpace(value)
+		}
+	}
+	headerNames := make([]string, 0, len(signedHeaders))
+	for name := range signedHeaders {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + signedHeaders[name] + "\n")
+	}
+	signedHeaderList := strings.Join(headerNames, ";")
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3SigningAlgorithm)
+	query.Set("X-Amz-Credential", s.accessKeyId+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", signedHeaderList)
+	canonicalQuery := s3CanonicalQuery(query)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		objectURL.EscapedPath(),
+		canonicalQuery,
+		canonicalHeaders.String(),
+		signedHeaderList,
+		s3UnsignedPayload,
+	}, "\n")
+
+	hashedCanonicalRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(hashedCanonicalRequest[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	objectURL.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+	return objectURL.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, s3Escape(key)+"="+s3Escape(query.Get(key)))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func s3EscapePath(objectPath string) string {
+	segments := strings.Split(objectPath, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// s3Escape percent-encodes everything except the RFC 3986 unreserved characters, as SigV4 requires.
+func s3Escape(value string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~' {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+
+	return escaped.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
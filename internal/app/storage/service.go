@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+)
+
+const (
+	// Storage drivers
+	FirebaseDriver = "firebase"
+	S3Driver       = "s3"
+	LocalDriver    = "local"
+)
+
+var firebaseAccessURLRegex = regexp.MustCompile(`^https://firebasestorage\.googleapis\.com/v0/b/[^/]+/o/([^?]+)`)
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+	UpdatedAt   time.Time
+}
+
+type Service interface {
+	GenerateSignedUploadURL(ctx context.Context, objectPath, contentType string, expires time.Duration) (string, error)
+	GenerateSignedDownloadURL(ctx context.Context, objectPath string, expires time.Duration) (string, error)
+	StatObject(ctx context.Context, objectPath string) (ObjectInfo, error)
+	DeleteObject(ctx context.Context, objectPath string) error
+	GetObject(ctx context.Context, objectPath string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, objectPath, contentType string, content io.Reader) error
+	PublicURL(objectPath string) string
+	AccessURL(ctx context.Context, objectRef string) (string, error)
+}
+
+// accessURLOptions controls how clients are handed URLs for stored objects. They are shared
+// by every driver; only the driver's own public URL shape differs.
+type accessURLOptions struct {
+	publicBaseURL string
+	signed        bool
+	expiry        time.Duration
+}
+
+type urlProvider interface {
+	GenerateSignedDownloadURL(ctx context.Context, objectPath string, expires time.Duration) (string, error)
+	defaultPublicURL(objectPath string) string
+}
+
+func NewService(ctx context.Context, cfg config.StorageService) (Service, error) {
+	opts := accessURLOptions{
+		publicBaseURL: strings.TrimSuffix(cfg.PublicBaseURL, "/"),
+		signed:        cfg.SignedAccessURLs,
+		expiry:        cfg.AccessURLExpiry,
+	}
+
+	switch cfg.Driver {
+	case FirebaseDriver:
+		if cfg.Firebase.BucketName == "" || cfg.Firebase.CredentialsFile == "" {
+			return nil, fmt.Errorf("bucket name and credentials file are required for the %s storage driver", FirebaseDriver)
+		}
+		return newFirebaseService(ctx, cfg.Firebase, opts)
+	case S3Driver:
+		if cfg.S3.Bucket == "" || cfg.S3.AccessKeyId == "" || cfg.S3.SecretAccessKey == "" {
+			return nil, fmt.Errorf("bucket and credentials are required for the %s storage driver", S3Driver)
+		}
+		return newS3Service(cfg.S3, opts)
+	case LocalDriver:
+		if cfg.Local.SigningSecret == "" {
+			return nil, fmt.Errorf("signing secret is required for the %s storage driver", LocalDriver)
+		}
+		return newLocalService(cfg.Local, opts)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %q", cfg.Driver)
+	}
+}
+
+func (o accessURLOptions) publicURL(provider urlProvider, objectPath string) string {
+	if o.publicBaseURL != "" {
+		return o.publicBaseURL + "/" + escapeObjectPath(objectPath)
+	}
+
+	return provider.defaultPublicURL(objectPath)
+}
+
+// accessURL accepts either an object path or an absolute URL. Absolute URLs come from images
+// stored before object paths were tracked and are returned unchanged.
+func (o accessURLOptions) accessURL(ctx context.Context, provider urlProvider, objectRef string) (string, error) {
+	if objectRef == "" || isAbsoluteURL(objectRef) {
+		return objectRef, nil
+	}
+
+	if o.signed {
+		return provider.GenerateSignedDownloadURL(ctx, objectRef, o.expiry)
+	}
+
+	return o.publicURL(provider, objectRef), nil
+}
+
+// ObjectPathFromURL recovers the object path from a previously issued access URL, either by
+// stripping baseURL or by recognising the Firebase download URL format.
+func ObjectPathFromURL(rawURL, baseURL string) (string, bool) {
+	var escapedPath string
+	if baseURL != "" && strings.HasPrefix(rawURL, baseURL) {
+		escapedPath = strings.TrimPrefix(strings.TrimPrefix(rawURL, baseURL), "/")
+		escapedPath, _, _ = strings.Cut(escapedPath, "?")
+	} else if matches := firebaseAccessURLRegex.FindStringSubmatch(rawURL); matches != nil {
+		escapedPath = matches[1]
+	} else {
+		return "", false
+	}
+
+	objectPath, err := url.PathUnescape(escapedPath)
+	if err != nil || objectPath == "" {
+		return "", false
+	}
+
+	return objectPath, true
+}
+
+func isAbsoluteURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+func escapeObjectPath(objectPath string) string {
+	segments := strings.Split(objectPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,42 @@
+package upload
+
+import (
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+const (
+	// Upload status
+	Pending = "PENDING"
+	Linked  = "LINKED"
+
+	// http.DetectContentType considers at most this many leading bytes
+	sniffLength = 512
+)
+
+var AllowedImageContentTypes = map[string]struct{}{
+	"image/jpeg": {},
+	"image/png":  {},
+	"image/webp": {},
+}
+
+type Upload struct {
+	Id          int
+	UserId      int
+	ObjectPath  string
+	ContentType string
+	Status      string
+	ExpiresAt   time.Time
+}
+
+func mapUploadRepoToUpload(upload repository.Upload) Upload {
+	return Upload{
+		Id:          upload.Id,
+		UserId:      upload.UserId,
+		ObjectPath:  upload.ObjectPath,
+		ContentType: upload.ContentType,
+		Status:      upload.Status,
+		ExpiresAt:   upload.ExpiresAt,
+	}
+}
@@ -0,0 +1,224 @@
+package upload
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/storage"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/imaging"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/worker"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+type service struct {
+	uploadRepository repository.UploadRepository
+	storageService   storage.Service
+	cfg              config.Uploads
+}
+
+type Service interface {
+	CreateImageUpload(ctx context.Context, objectPath, contentType string) (upload Upload, err error)
+	LinkImageUploads(ctx context.Context, tx *sql.Tx, uploadIds []int) (uploads map[int]Upload, err error)
+	CollectOrphanedUploads(ctx context.Context) (err error)
+	StartWorker(ctx context.Context)
+}
+
+func NewService(uploadRepository repository.UploadRepository, storageService storage.Service, cfg config.Uploads) Service {
+	return &service{
+		uploadRepository: uploadRepository,
+		storageService:   storageService,
+		cfg:              cfg,
+	}
+}
+
+// CreateImageUpload records an object the current user is about to upload. It stays pending
+// until it is linked, and is garbage-collected if that does not happen before it expires.
+func (s *service) CreateImageUpload(ctx context.Context, objectPath, contentType string) (Upload, error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return Upload{}, apperrors.ErrInternalServer
+	}
+
+	if _, ok := AllowedImageContentTypes[contentType]; !ok {
+		slog.Error("unsupported image content type", "contentType", contentType)
+		return Upload{}, apperrors.ErrUnsupportedImageType
+	}
+
+	uploadData := repository.CreateUploadData{
+		UserId:      userId,
+		ObjectPath:  objectPath,
+		ContentType: contentType,
+		ExpiresAt:   time.Now().Add(s.cfg.PendingTTL),
+	}
+	upload, err := s.uploadRepository.CreateUpload(ctx, nil, uploadData)
+	if err != nil {
+		slog.Error("failed to create upload", "error", err)
+		return Upload{}, err
+	}
+
+	return mapUploadRepoToUpload(upload), nil
+}
+
+// LinkImageUploads verifies that every upload belongs to the current user, is still pending and
+// that the uploaded object is an image within the size limit, then marks them linked inside tx.
+func (s *service) LinkImageUploads(ctx context.Context, tx *sql.Tx, uploadIds []int) (map[int]Upload, error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	if len(uploadIds) == 0 {
+		return map[int]Upload{}, nil
+	}
+
+	uploadList, err := s.uploadRepository.GetUploadsForUpdate(ctx, tx, uploadIds, userId)
+	if err != nil {
+		slog.Error("failed to get uploads", "error", err)
+		return nil, err
+	}
+
+	uploads := make(map[int]Upload, len(uploadList))
+	for _, u := range uploadList {
+		uploads[u.Id] = mapUploadRepoToUpload(u)
+	}
+
+	now := time.Now()
+	for _, uploadId := range uploadIds {
+		upload, ok := uploads[uploadId]
+		if !ok || upload.Status != Pending || !upload.ExpiresAt.After(now) {
+			slog.Error("upload is not available for linking", "uploadId", uploadId)
+			return nil, apperrors.ErrInvalidImageToLink
+		}
+
+		err = s.verifyImageObject(ctx, upload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.uploadRepository.MarkUploadsLinked(ctx, tx, uploadIds)
+	if err != nil {
+		slog.Error("failed to mark uploads as linked", "error", err)
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+func (s *service) verifyImageObject(ctx context.Context, upload Upload) error {
+	info, err := s.storageService.StatObject(ctx, upload.ObjectPath)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrObjectNotFound) {
+			slog.Error("uploaded object not found", "uploadId", upload.Id, "objectPath", upload.ObjectPath)
+			return apperrors.ErrInvalidImageToLink
+		}
+		slog.Error("failed to stat uploaded object", "uploadId", upload.Id, "error", err)
+		return err
+	}
+
+	if info.Size <= 0 {
+		slog.Error("uploaded object is empty", "uploadId", upload.Id)
+		return apperrors.ErrInvalidImageToLink
+	}
+
+	if info.Size > s.cfg.MaxImageBytes {
+		slog.Error("uploaded object exceeds the maximum image size", "uploadId", upload.Id, "size", info.Size)
+		return apperrors.ErrObjectTooLarge
+	}
+
+	mediaType, _, err := mime.ParseMediaType(info.ContentType)
+	if err != nil || mediaType != upload.ContentType {
+		slog.Error("uploaded object content type mismatch", "uploadId", upload.Id, "expected", upload.ContentType, "actual", info.ContentType)
+		return apperrors.ErrUnsupportedImageType
+	}
+
+	// Both content types above are set by the client, so the object's own bytes decide.
+	sniffedType, err := s.sniffContentType(ctx, upload.ObjectPath)
+	if err != nil {
+		slog.Error("failed to read uploaded object", "uploadId", upload.Id, "error", err)
+		return err
+	}
+	if sniffedType != upload.ContentType {
+		slog.Error("uploaded object is not the declared image type", "uploadId", upload.Id, "expected", upload.ContentType, "detected", sniffedType)
+		return apperrors.ErrUnsupportedImageType
+	}
+
+	return nil
+}
+
+// sniffContentType detects the content type of a stored object from its leading bytes.
+func (s *service) sniffContentType(ctx context.Context, objectPath string) (string, error) {
+	object, err := s.storageService.GetObject(ctx, objectPath)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrObjectNotFound) {
+			return "", apperrors.ErrInvalidImageToLink
+		}
+		return "", err
+	}
+	defer object.Close()
+
+	header := make([]byte, sniffLength)
+	n, err := io.ReadFull(object, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return http.DetectContentType(header[:n]), nil
+}
+
+// CollectOrphanedUploads deletes the stored objects of uploads that were never linked or whose
+// image has been removed. The object goes first so a failed delete is retried on the next run.
+func (s *service) CollectOrphanedUploads(ctx context.Context) error {
+	uploads, err := s.uploadRepository.GetOrphanedUploads(ctx, nil, s.cfg.GCBatchSize)
+	if err != nil {
+		slog.Error("failed to get orphaned uploads", "error", err)
+		return err
+	}
+
+	for _, upload := range uploads {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = s.deleteObjectWithVariants(ctx, upload.ObjectPath)
+		if err != nil {
+			slog.Error("failed to delete orphaned upload object", "uploadId", upload.Id, "error", err)
+			continue
+		}
+
+		err = s.uploadRepository.DeleteUpload(ctx, nil, upload.Id)
+		if err != nil {
+			slog.Error("failed to delete orphaned upload", "uploadId", upload.Id, "error", err)
+			continue
+		}
+		slog.Info("orphaned upload collected", "uploadId", upload.Id, "objectPath", upload.ObjectPath)
+	}
+
+	return nil
+}
+
+func (s *service) deleteObjectWithVariants(ctx context.Context, objectPath string) error {
+	for _, variant := range imaging.Variants {
+		err := s.storageService.DeleteObject(ctx, imaging.VariantPath(objectPath, variant))
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.storageService.DeleteObject(ctx, objectPath)
+}
+
+func (s *service) StartWorker(ctx context.Context) {
+	worker.RunPeriodically(ctx, "upload-gc", s.cfg.GCInterval, s.CollectOrphanedUploads)
+}
@@ -6,12 +6,16 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
 )
 
 const (
 	// User roles
 	Host   = "HOST"
 	Seeker = "SEEKER"
+	// Admin is never self-assigned; it has to be granted directly in the database.
+	Admin = "ADMIN"
 
 	// Verification token types
 	EmailVerification = "EMAIL_VERIFICATION"
@@ -22,13 +26,13 @@ const (
 	PhoneRegex = `^(?:(?:\+91)|91)?[0-9]{10}$`
 
 	// Time to live constants
-	accessTokenTTL       = time.Hour * 24 * 30
+	accessTokenTTL       = time.Minute * 15
+	refreshTokenTTL      = time.Hour * 24 * 30
 	verificationTokenTTL = time.Minute * 10
-)
 
-const (
-	emailVerificationEmailContent = "Hello %s,\n\nThank you for registering on Wheelio. Please verify your email address by clicking the link below:\n\n%s\n\nThis link will expire in 10 minutes.\n\nBest regards,\nThe Wheelio Team"
-	resetPasswordEmailContent     = "Hello %s,\n\nWe received a request to reset your password for your Wheelio account. Click the link below to set a new password:\n\n%s\n\nIf you did not request a password reset, please ignore this email. This link will expire in 10 minutes for security reasons.\n\nBest regards,\nThe Wheelio Team"
+	// Ended sessions are kept this long before they are deleted, in batches of this size.
+	staleSessionRetention = time.Hour * 24 * 7
+	staleSessionBatchSize = 1000
 )
 
 var AvailableRoles = map[string]struct{}{
@@ -37,23 +41,25 @@ var AvailableRoles = map[string]struct{}{
 }
 
 type User struct {
-	Id          int       `json:"id"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	PhoneNumber string    `json:"phoneNumber"`
-	Password    string    `json:"-"`
-	Role        string    `json:"role"`
-	IsVerified  bool      `json:"isVerified"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	Id                int       `json:"id"`
+	Name              string    `json:"name"`
+	Email             string    `json:"email"`
+	PhoneNumber       string    `json:"phoneNumber"`
+	Password          string    `json:"-"`
+	Role              string    `json:"role"`
+	IsVerified        bool      `json:"isVerified"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+	PreferredLanguage string    `json:"preferredLanguage"`
 }
 
 type CreateUserRequestBody struct {
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	PhoneNumber string `json:"phoneNumber"`
-	Password    string `json:"password"`
-	Role        string `json:"role"`
+	Name              string `json:"name"`
+	Email             string `json:"email"`
+	PhoneNumber       string `json:"phoneNumber"`
+	Password          string `json:"password"`
+	Role              string `json:"role"`
+	PreferredLanguage string `json:"preferredLanguage"`
 }
 
 type LoginUserRequestBody struct {
@@ -66,8 +72,30 @@ type ResetPasswordRequestBody struct {
 	Password string `json:"password"`
 }
 
-type AccessToken struct {
-	AccessToken string `json:"accessToken"`
+type AuthTokens struct {
+	AccessToken           string    `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+type RefreshTokenRequestBody struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type SessionMetadata struct {
+	UserAgent string
+	IpAddress string
+}
+
+type Session struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IpAddress  string    `json:"ipAddress"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
 }
 
 type Token struct {
@@ -78,6 +106,10 @@ type Email struct {
 	Email string `json:"email"`
 }
 
+type PreferredLanguageRequestBody struct {
+	PreferredLanguage string `json:"preferredLanguage"`
+}
+
 func (c CreateUserRequestBody) validate() error {
 	var validationErrors []string
 
@@ -107,6 +139,10 @@ func (c CreateUserRequestBody) validate() error {
 		validationErrors = append(validationErrors, "invalid role")
 	}
 
+	if _, ok := email.SupportedLocales[c.PreferredLanguage]; c.PreferredLanguage != "" && !ok {
+		validationErrors = append(validationErrors, "unsupported preferred language")
+	}
+
 	if len(validationErrors) > 0 {
 		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
 	}
@@ -160,6 +196,14 @@ func (c Token) validate() error {
 	return nil
 }
 
+func (c RefreshTokenRequestBody) validate() error {
+	if strings.TrimSpace(c.RefreshToken) == "" {
+		return errors.New("validation failed: refresh token is required")
+	}
+
+	return nil
+}
+
 func (c Email) validate() error {
 	if strings.TrimSpace(c.Email) == "" {
 		return errors.New("validation failed: email is required")
@@ -169,3 +213,13 @@ func (c Email) validate() error {
 
 	return nil
 }
+
+func (c PreferredLanguageRequestBody) validate() error {
+	if strings.TrimSpace(c.PreferredLanguage) == "" {
+		return errors.New("validation failed: preferred language is required")
+	} else if _, ok := email.SupportedLocales[c.PreferredLanguage]; !ok {
+		return errors.New("validation failed: unsupported preferred language")
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"encoding/json"
 	"log/slog"
+	"net"
 	"net/http"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
@@ -45,7 +46,11 @@ func SignInUser(userService Service) http.HandlerFunc {
 			return
 		}
 
-		loginData, err := userService.LoginUser(ctx, requestBody)
+		metadata := SessionMetadata{
+			UserAgent: r.UserAgent(),
+			IpAddress: clientIpAddress(r),
+		}
+		loginData, err := userService.LoginUser(ctx, requestBody, metadata)
 		if err != nil {
 			slog.Error("failed to login user", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -161,11 +166,43 @@ func UpgradeUserRoleToHost(userService Service) http.HandlerFunc {
 	}
 }
 
+func UpdatePreferredLanguage(userService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var requestBody PreferredLanguageRequestBody
+		err := json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		err = userService.UpdatePreferredLanguage(ctx, requestBody)
+		if err != nil {
+			slog.Error("failed to update preferred language", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "preferred language updated successfully", nil)
+	}
+}
+
 func RefreshAccessToken(userService Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		loginData, err := userService.RefreshAccessToken(ctx)
+		var requestBody RefreshTokenRequestBody
+		err := json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		loginData, err := userService.RefreshAccessToken(ctx, requestBody)
 		if err != nil {
 			slog.Error("failed to refresh access token", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -176,3 +213,60 @@ func RefreshAccessToken(userService Service) http.HandlerFunc {
 		response.WriteJson(w, http.StatusOK, "access token refreshed successfully", loginData)
 	}
 }
+
+func Logout(userService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		err := userService.Logout(ctx)
+		if err != nil {
+			slog.Error("failed to logout user", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "logged out successfully", nil)
+	}
+}
+
+func LogoutAllSessions(userService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		err := userService.LogoutAllSessions(ctx)
+		if err != nil {
+			slog.Error("failed to logout user from all devices", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "logged out from all devices successfully", nil)
+	}
+}
+
+func GetActiveSessions(userService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		sessions, err := userService.GetActiveSessions(ctx)
+		if err != nil {
+			slog.Error("failed to fetch active sessions", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "sessions fetched successfully", sessions)
+	}
+}
+
+func clientIpAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
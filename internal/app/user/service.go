@@ -2,40 +2,50 @@ package user
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/email"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/outbox"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/cryptokit"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type service struct {
 	userRepository repository.UserRepository
-	emailService   email.Service
+	outboxService  outbox.Service
 }
 
 type Service interface {
 	RegisterUser(ctx context.Context, userDetails CreateUserRequestBody) (err error)
-	LoginUser(ctx context.Context, loginDetails LoginUserRequestBody) (accessToken AccessToken, err error)
+	LoginUser(ctx context.Context, loginDetails LoginUserRequestBody, metadata SessionMetadata) (authTokens AuthTokens, err error)
 	VerifyEmail(ctx context.Context, token Token) (err error)
-	ForgotPassword(ctx context.Context, email Email) (err error)
+	ForgotPassword(ctx context.Context, emailDetails Email) (err error)
 	ResetPassword(ctx context.Context, resetPasswordDetails ResetPasswordRequestBody) (err error)
 	GetLoggedInUser(ctx context.Context) (user User, err error)
 	UpgradeUserRoleToHost(ctx context.Context) (err error)
+	UpdatePreferredLanguage(ctx context.Context, languageDetails PreferredLanguageRequestBody) (err error)
 	GetUserById(ctx context.Context, userId int) (user User, err error)
-	RefreshAccessToken(ctx context.Context) (accessToken AccessToken, err error)
+	RefreshAccessToken(ctx context.Context, refreshTokenData RefreshTokenRequestBody) (authTokens AuthTokens, err error)
+	Logout(ctx context.Context) (err error)
+	LogoutAllSessions(ctx context.Context) (err error)
+	GetActiveSessions(ctx context.Context) (sessions []Session, err error)
+	ValidateSession(ctx context.Context, sessionId string, userId int) (err error)
+	DeleteStaleSessions(ctx context.Context) (err error)
 }
 
-func NewService(userRepository repository.UserRepository, emailService email.Service) Service {
+func NewService(userRepository repository.UserRepository, outboxService outbox.Service) Service {
 	return &service{
 		userRepository: userRepository,
-		emailService:   emailService,
+		outboxService:  outboxService,
 	}
 }
 
@@ -53,6 +63,9 @@ func (s *service) RegisterUser(ctx context.Context, userDetails CreateUserReques
 	}
 
 	userDetails.Password = hashedPassword
+	if userDetails.PreferredLanguage == "" {
+		userDetails.PreferredLanguage = email.DefaultLocale
+	}
 
 	tx, err := s.userRepository.BeginTx(ctx)
 	if err != nil {
@@ -88,53 +101,71 @@ func (s *service) RegisterUser(ctx context.Context, userDetails CreateUserReques
 
 	cfg := config.GetConfig()
 	verificationLink := fmt.Sprintf("%s/verify-email?token=%s", cfg.ClientURL, token)
-	emailBody := fmt.Sprintf(emailVerificationEmailContent, newUser.Name, verificationLink)
 
-	err = s.emailService.SendEmail(newUser.Name, newUser.Email, "Action Required: Verify Your Wheelio Account", emailBody)
+	err = s.outboxService.EnqueueEmail(ctx, tx, outbox.Email{
+		ToName:   newUser.Name,
+		ToEmail:  newUser.Email,
+		Template: email.EmailVerificationTemplate,
+		Locale:   newUser.PreferredLanguage,
+		Data: email.EmailVerificationData{
+			Name:             newUser.Name,
+			VerificationLink: verificationLink,
+			ExpiresInMinutes: int(verificationTokenTTL.Minutes()),
+		},
+	})
 	if err != nil {
-		slog.Error("failed to send verification email", "error", err)
+		slog.Error("failed to enqueue verification email", "error", err)
 		return err
 	}
 
 	return nil
 }
 
-func (s *service) LoginUser(ctx context.Context, loginDetails LoginUserRequestBody) (accessToken AccessToken, err error) {
+func (s *service) LoginUser(ctx context.Context, loginDetails LoginUserRequestBody, metadata SessionMetadata) (authTokens AuthTokens, err error) {
 	err = loginDetails.validate()
 	if err != nil {
 		slog.Error("failed to validate login details", "error", err)
-		return AccessToken{}, apperrors.ErrInvalidRequestBody
+		return AuthTokens{}, apperrors.ErrInvalidRequestBody
 	}
 
 	user, err := s.userRepository.GetUserByEmail(ctx, nil, loginDetails.Email)
 	if err != nil {
 		slog.Error("user not found by email", "error", err)
-		return AccessToken{}, apperrors.ErrInvalidLoginCredentials
+		return AuthTokens{}, apperrors.ErrInvalidLoginCredentials
 	}
 
 	if !user.IsVerified {
 		slog.Error("user is not verified", "email", loginDetails.Email)
-		return AccessToken{}, apperrors.ErrUserNotVerified
+		return AuthTokens{}, apperrors.ErrUserNotVerified
 	}
 
 	isPasswordCorrect := cryptokit.CheckPasswordHash(loginDetails.Password, user.Password)
 	if !isPasswordCorrect {
 		slog.Error("invalid login password", "email", loginDetails.Email)
-		return AccessToken{}, apperrors.ErrInvalidLoginCredentials
+		return AuthTokens{}, apperrors.ErrInvalidLoginCredentials
 	}
 
-	token, err := cryptokit.CreateJWTToken(jwt.MapClaims{
-		"id":    user.Id,
-		"email": user.Email,
-		"role":  user.Role,
-		"exp":   time.Now().Add(accessTokenTTL).Unix(),
-	})
+	refreshSecret, err := cryptokit.GenerateSecureToken(32)
 	if err != nil {
-		slog.Error("failed to create jwt token", "error", err)
-		return AccessToken{}, apperrors.ErrJWTCreationFailed
+		slog.Error("failed to generate secure refresh token", "error", err)
+		return AuthTokens{}, apperrors.ErrInternalServer
 	}
 
-	return AccessToken{AccessToken: token}, nil
+	sessionData := repository.UserSession{
+		Id:               uuid.New().String(),
+		UserId:           user.Id,
+		RefreshTokenHash: cryptokit.HashToken(refreshSecret),
+		UserAgent:        metadata.UserAgent,
+		IpAddress:        metadata.IpAddress,
+		ExpiresAt:        time.Now().Add(refreshTokenTTL),
+	}
+	session, err := s.userRepository.CreateUserSession(ctx, nil, sessionData)
+	if err != nil {
+		slog.Error("failed to create user session", "error", err)
+		return AuthTokens{}, err
+	}
+
+	return createAuthTokens(user, session.Id, refreshSecret, session.ExpiresAt)
 }
 
 func (s *service) VerifyEmail(ctx context.Context, token Token) (err error) {
@@ -164,16 +195,16 @@ func (s *service) VerifyEmail(ctx context.Context, token Token) (err error) {
 	return nil
 }
 
-func (s *service) ForgotPassword(ctx context.Context, email Email) (err error) {
-	err = email.validate()
+func (s *service) ForgotPassword(ctx context.Context, emailDetails Email) (err error) {
+	err = emailDetails.validate()
 	if err != nil {
 		slog.Error("failed to validate email", "error", err)
 		return apperrors.ErrInvalidRequestBody
 	}
 
-	user, err := s.userRepository.GetUserByEmail(ctx, nil, email.Email)
+	user, err := s.userRepository.GetUserByEmail(ctx, nil, emailDetails.Email)
 	if err != nil {
-		slog.Warn("no user found for the given email for password reset request", "email", email.Email)
+		slog.Warn("no user found for the given email for password reset request", "email", emailDetails.Email)
 		return nil
 	}
 
@@ -192,11 +223,20 @@ func (s *service) ForgotPassword(ctx context.Context, email Email) (err error) {
 
 	cfg := config.GetConfig()
 	resetLink := fmt.Sprintf("%s/reset-password?token=%s", cfg.ClientURL, token)
-	emailBody := fmt.Sprintf(resetPasswordEmailContent, user.Name, resetLink)
 
-	err = s.emailService.SendEmail(user.Name, user.Email, "Reset Your Wheelio Password", emailBody)
+	err = s.outboxService.EnqueueEmail(ctx, nil, outbox.Email{
+		ToName:   user.Name,
+		ToEmail:  user.Email,
+		Template: email.PasswordResetTemplate,
+		Locale:   user.PreferredLanguage,
+		Data: email.PasswordResetData{
+			Name:             user.Name,
+			ResetLink:        resetLink,
+			ExpiresInMinutes: int(verificationTokenTTL.Minutes()),
+		},
+	})
 	if err != nil {
-		slog.Error("failed to send reset password email", "error", err)
+		slog.Error("failed to enqueue reset password email", "error", err)
 		return err
 	}
 
@@ -234,6 +274,12 @@ func (s *service) ResetPassword(ctx context.Context, resetPasswordDetails ResetP
 		return err
 	}
 
+	err = s.userRepository.RevokeAllUserSessions(ctx, nil, verificationToken.UserId)
+	if err != nil {
+		slog.Error("failed to revoke user sessions after password reset", "error", err)
+		return err
+	}
+
 	err = s.userRepository.DeleteVerificationTokenById(ctx, nil, verificationToken.Id)
 	if err != nil {
 		slog.Warn("failed to delete verification token", "error", err)
@@ -274,6 +320,28 @@ func (s *service) UpgradeUserRoleToHost(ctx context.Context) (err error) {
 	return nil
 }
 
+func (s *service) UpdatePreferredLanguage(ctx context.Context, languageDetails PreferredLanguageRequestBody) (err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	err = languageDetails.validate()
+	if err != nil {
+		slog.Error("preferred language validation failed", "error", err)
+		return apperrors.ErrInvalidRequestBody
+	}
+
+	err = s.userRepository.UpdateUserPreferredLanguage(ctx, nil, userId, languageDetails.PreferredLanguage)
+	if err != nil {
+		slog.Error("failed to update user preferred language", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) GetUserById(ctx context.Context, userId int) (user User, err error) {
 	userData, err := s.userRepository.GetUserById(ctx, nil, userId)
 	if err != nil {
@@ -284,29 +352,186 @@ func (s *service) GetUserById(ctx context.Context, userId int) (user User, err e
 	return User(userData), nil
 }
 
-func (s *service) RefreshAccessToken(ctx context.Context) (accessToken AccessToken, err error) {
+func (s *service) RefreshAccessToken(ctx context.Context, refreshTokenData RefreshTokenRequestBody) (authTokens AuthTokens, err error) {
+	err = refreshTokenData.validate()
+	if err != nil {
+		slog.Error("failed to validate refresh token", "error", err)
+		return AuthTokens{}, apperrors.ErrInvalidRequestBody
+	}
+
+	sessionId, refreshSecret, err := parseRefreshToken(refreshTokenData.RefreshToken)
+	if err != nil {
+		slog.Error("malformed refresh token", "error", err)
+		return AuthTokens{}, err
+	}
+
+	session, err := s.userRepository.GetUserSessionById(ctx, nil, sessionId)
+	if err != nil {
+		slog.Error("failed to get user session", "error", err)
+		return AuthTokens{}, err
+	}
+
+	if session.RevokedAt != nil || session.ExpiresAt.Before(time.Now()) {
+		slog.Error("refresh attempted on revoked or expired session", "sessionId", sessionId)
+		return AuthTokens{}, apperrors.ErrUnauthorizedAccess
+	}
+
+	if subtle.ConstantTimeCompare([]byte(cryptokit.HashToken(refreshSecret)), []byte(session.RefreshTokenHash)) != 1 {
+		slog.Warn("refresh token reuse detected, revoking session", "sessionId", sessionId)
+		err = s.userRepository.RevokeUserSession(ctx, nil, sessionId)
+		if err != nil {
+			slog.Error("failed to revoke user session", "error", err)
+		}
+		return AuthTokens{}, apperrors.ErrUnauthorizedAccess
+	}
+
+	user, err := s.userRepository.GetUserById(ctx, nil, session.UserId)
+	if err != nil {
+		slog.Error("user not found by id", "error", err)
+		return AuthTokens{}, apperrors.ErrInternalServer
+	}
+
+	newRefreshSecret, err := cryptokit.GenerateSecureToken(32)
+	if err != nil {
+		slog.Error("failed to generate secure refresh token", "error", err)
+		return AuthTokens{}, apperrors.ErrInternalServer
+	}
+
+	refreshTokenExpiresAt := time.Now().Add(refreshTokenTTL)
+	err = s.userRepository.RotateUserSessionRefreshToken(ctx, nil, sessionId, session.RefreshTokenHash, cryptokit.HashToken(newRefreshSecret), refreshTokenExpiresAt)
+	if err != nil {
+		slog.Error("failed to rotate refresh token", "error", err)
+		return AuthTokens{}, err
+	}
+
+	return createAuthTokens(user, sessionId, newRefreshSecret, refreshTokenExpiresAt)
+}
+
+func (s *service) Logout(ctx context.Context) (err error) {
+	sessionId, ok := ctx.Value(middleware.RequestContextSessionIdKey).(string)
+	if !ok {
+		slog.Error("failed to retrieve session id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	err = s.userRepository.RevokeUserSession(ctx, nil, sessionId)
+	if err != nil {
+		slog.Error("failed to revoke user session", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) LogoutAllSessions(ctx context.Context) (err error) {
 	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
 	if !ok {
 		slog.Error("failed to retrieve user id from context")
-		return AccessToken{}, apperrors.ErrInternalServer
+		return apperrors.ErrInternalServer
 	}
 
-	user, err := s.userRepository.GetUserById(ctx, nil, userId)
+	err = s.userRepository.RevokeAllUserSessions(ctx, nil, userId)
 	if err != nil {
-		slog.Error("user not found by id", "error", err)
-		return AccessToken{}, apperrors.ErrInternalServer
+		slog.Error("failed to revoke all user sessions", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetActiveSessions(ctx context.Context) (sessions []Session, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return []Session{}, apperrors.ErrInternalServer
 	}
 
+	currentSessionId, _ := ctx.Value(middleware.RequestContextSessionIdKey).(string)
+
+	sessionList, err := s.userRepository.GetActiveUserSessions(ctx, nil, userId)
+	if err != nil {
+		slog.Error("failed to get active user sessions", "error", err)
+		return []Session{}, err
+	}
+
+	sessions = make([]Session, len(sessionList))
+	for i, session := range sessionList {
+		sessions[i] = Session{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			Current:    session.Id == currentSessionId,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+
+	return sessions, nil
+}
+
+func (s *service) ValidateSession(ctx context.Context, sessionId string, userId int) (err error) {
+	session, err := s.userRepository.GetUserSessionById(ctx, nil, sessionId)
+	if err != nil {
+		slog.Error("failed to get user session", "error", err)
+		return err
+	}
+
+	if session.UserId != userId || session.RevokedAt != nil || session.ExpiresAt.Before(time.Now()) {
+		slog.Error("session is revoked, expired or belongs to another user", "sessionId", sessionId)
+		return apperrors.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// DeleteStaleSessions removes sessions that expired or were revoked more than the retention
+// period ago, so the sessions table does not grow with every login.
+func (s *service) DeleteStaleSessions(ctx context.Context) error {
+	endedBefore := time.Now().Add(-staleSessionRetention)
+	for {
+		deleted, err := s.userRepository.DeleteStaleUserSessions(ctx, nil, endedBefore, staleSessionBatchSize)
+		if err != nil {
+			slog.Error("failed to delete stale sessions", "error", err)
+			return err
+		}
+
+		if deleted > 0 {
+			slog.Info("stale sessions deleted", "count", deleted)
+		}
+		if deleted < staleSessionBatchSize || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func createAuthTokens(user repository.User, sessionId, refreshSecret string, refreshTokenExpiresAt time.Time) (AuthTokens, error) {
+	accessTokenExpiresAt := time.Now().Add(accessTokenTTL)
 	token, err := cryptokit.CreateJWTToken(jwt.MapClaims{
 		"id":    user.Id,
 		"email": user.Email,
 		"role":  user.Role,
-		"exp":   time.Now().Add(accessTokenTTL).Unix(),
+		"sid":   sessionId,
+		"exp":   accessTokenExpiresAt.Unix(),
 	})
 	if err != nil {
 		slog.Error("failed to create jwt token", "error", err)
-		return AccessToken{}, apperrors.ErrJWTCreationFailed
+		return AuthTokens{}, apperrors.ErrJWTCreationFailed
+	}
+
+	return AuthTokens{
+		AccessToken:           token,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          fmt.Sprintf("%s.%s", sessionId, refreshSecret),
+		RefreshTokenExpiresAt: refreshTokenExpiresAt,
+	}, nil
+}
+
+func parseRefreshToken(refreshToken string) (sessionId, refreshSecret string, err error) {
+	sessionId, refreshSecret, found := strings.Cut(refreshToken, ".")
+	if !found || refreshSecret == "" || uuid.Validate(sessionId) != nil {
+		return "", "", apperrors.ErrUnauthorizedAccess
 	}
 
-	return AccessToken{AccessToken: token}, nil
+	return sessionId, refreshSecret, nil
 }
@@ -0,0 +1,29 @@
+package vehicle
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
+)
+
+// authorizeVehicleOwner ensures the logged in user is the host of the vehicle and
+// returns their id. Every operation on a vehicle or one of its sub-resources
+// (images, availability, etc.) must go through this check before mutating data.
+func (s *service) authorizeVehicleOwner(ctx context.Context, tx *sql.Tx, vehicleId int) (hostId int, err error) {
+	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
+	if !ok {
+		slog.Error("failed to retrieve user id from context")
+		return 0, apperrors.ErrInternalServer
+	}
+
+	err = s.vehicleRepository.VehicleOwnershipCheck(ctx, tx, vehicleId, userId)
+	if err != nil {
+		slog.Error("vehicle ownership check failed", "vehicleId", vehicleId, "error", err)
+		return 0, err
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,211 @@
+package vehicle
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+// PauseVehicle hides the vehicle from search and stops new bookings. Existing bookings are kept.
+func (s *service) PauseVehicle(ctx context.Context, vehicleId int) error {
+	return s.setVehicleAvailability(ctx, vehicleId, false)
+}
+
+func (s *service) UnpauseVehicle(ctx context.Context, vehicleId int) error {
+	return s.setVehicleAvailability(ctx, vehicleId, true)
+}
+
+func (s *service) setVehicleAvailability(ctx context.Context, vehicleId int, available bool) error {
+	hostId, err := s.authorizeVehicleOwner(ctx, nil, vehicleId)
+	if err != nil {
+		return err
+	}
+
+	err = s.vehicleRepository.UpdateVehicleAvailability(ctx, nil, vehicleId, hostId, available)
+	if err != nil {
+		slog.Error("failed to update vehicle availability", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) CreateVehicleBlackout(ctx context.Context, vehicleId int, blackoutData VehicleBlackoutRequestBody) (blackout VehicleBlackout, err error) {
+	err = blackoutData.validate()
+	if err != nil {
+		slog.Error("vehicle blackout validation failed", "error", err)
+		return VehicleBlackout{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.vehicleRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start vehicle blackout creation", "error", err)
+		return VehicleBlackout{}, err
+	}
+
+	defer func() {
+		if txErr := s.vehicleRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	_, err = s.authorizeVehicleOwner(ctx, tx, vehicleId)
+	if err != nil {
+		return VehicleBlackout{}, err
+	}
+
+	// Bookings share this lock while they are created, so none can slip into the range
+	// between the conflict check and the insert.
+	err = s.vehicleRepository.LockVehicle(ctx, tx, vehicleId)
+	if err != nil {
+		slog.Error("failed to lock vehicle", "error", err)
+		return VehicleBlackout{}, err
+	}
+
+	err = s.vehicleRepository.BlackoutBookingConflictCheck(ctx, tx, vehicleId, blackoutData.StartsAt, blackoutData.EndsAt)
+	if err != nil {
+		slog.Error("failed to check blackout against bookings", "error", err)
+		return VehicleBlackout{}, err
+	}
+
+	createdBlackout, err := s.vehicleRepository.CreateVehicleBlackout(ctx, tx, repository.CreateVehicleBlackoutData{
+		VehicleId: vehicleId,
+		StartsAt:  blackoutData.StartsAt,
+		EndsAt:    blackoutData.EndsAt,
+		Reason:    strings.TrimSpace(blackoutData.Reason),
+	})
+	if err != nil {
+		slog.Error("failed to create vehicle blackout", "error", err)
+		return VehicleBlackout{}, err
+	}
+
+	return mapVehicleBlackoutRepoToVehicleBlackout(createdBlackout), nil
+}
+
+func (s *service) GetVehicleBlackouts(ctx context.Context, vehicleId int) ([]VehicleBlackout, error) {
+	_, err := s.authorizeVehicleOwner(ctx, nil, vehicleId)
+	if err != nil {
+		return []VehicleBlackout{}, err
+	}
+
+	blackouts, err := s.vehicleRepository.GetUpcomingVehicleBlackouts(ctx, nil, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle blackouts", "error", err)
+		return []VehicleBlackout{}, err
+	}
+
+	vehicleBlackouts := make([]VehicleBlackout, len(blackouts))
+	for i, blackout := range blackouts {
+		vehicleBlackouts[i] = mapVehicleBlackoutRepoToVehicleBlackout(blackout)
+	}
+
+	return vehicleBlackouts, nil
+}
+
+func (s *service) DeleteVehicleBlackout(ctx context.Context, vehicleId, blackoutId int) error {
+	_, err := s.authorizeVehicleOwner(ctx, nil, vehicleId)
+	if err != nil {
+		return err
+	}
+
+	err = s.vehicleRepository.DeleteVehicleBlackout(ctx, nil, vehicleId, blackoutId)
+	if err != nil {
+		slog.Error("failed to delete vehicle blackout", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetVehicleAvailability returns the busy intervals overlapping [from, to] and the free slots in
+// between. Slots are aligned to the configured granularity and follow the booking conflict check:
+// a slot is only free if booking exactly that slot would succeed.
+func (s *service) GetVehicleAvailability(ctx context.Context, vehicleId int, from, to time.Time) (VehicleAvailability, error) {
+	if !from.Before(to) || to.Sub(from) > s.availabilityCfg.MaxRange {
+		slog.Error("invalid availability range", "from", from, "to", to)
+		return VehicleAvailability{}, apperrors.ErrInvalidAvailabilityRange
+	}
+
+	granularity := s.availabilityCfg.SlotGranularity
+	if granularity <= 0 {
+		slog.Error("availability slot granularity must be positive", "granularity", granularity)
+		return VehicleAvailability{}, apperrors.ErrInternalServer
+	}
+
+	vehicle, err := s.vehicleRepository.GetVehicleById(ctx, nil, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle details", "error", err)
+		return VehicleAvailability{}, err
+	}
+
+	availability := VehicleAvailability{
+		VehicleId:   vehicleId,
+		From:        from,
+		To:          to,
+		Available:   vehicle.Available,
+		SlotMinutes: int(granularity.Minutes()),
+		Busy:        []TimeInterval{},
+		FreeSlots:   []TimeInterval{},
+	}
+
+	if !vehicle.Available {
+		availability.Busy = append(availability.Busy, TimeInterval{Start: from, End: to})
+		return availability, nil
+	}
+
+	busyIntervals, err := s.vehicleRepository.GetVehicleBusyIntervals(ctx, nil, vehicleId, from, to, s.availabilityCfg.BookingBuffer)
+	if err != nil {
+		slog.Error("failed to get vehicle busy intervals", "error", err)
+		return VehicleAvailability{}, err
+	}
+
+	availability.Busy = mergeBusyIntervals(busyIntervals)
+	availability.FreeSlots = freeSlots(availability.Busy, from, to, granularity)
+
+	return availability, nil
+}
+
+// mergeBusyIntervals expects intervals sorted by start. Intervals that overlap or touch are
+// merged, since bounds are inclusive and nothing can be booked in between.
+func mergeBusyIntervals(intervals []repository.BusyInterval) []TimeInterval {
+	merged := []TimeInterval{}
+	for _, interval := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && !interval.StartsAt.After(merged[last].End) {
+			if interval.EndsAt.After(merged[last].End) {
+				merged[last].End = interval.EndsAt.UTC()
+			}
+			continue
+		}
+		merged = append(merged, TimeInterval{Start: interval.StartsAt.UTC(), End: interval.EndsAt.UTC()})
+	}
+
+	return merged
+}
+
+func freeSlots(busy []TimeInterval, from, to time.Time, granularity time.Duration) []TimeInterval {
+	slots := []TimeInterval{}
+
+	slotStart := from.Truncate(granularity)
+	if slotStart.Before(from) {
+		slotStart = slotStart.Add(granularity)
+	}
+
+	next := 0
+	for slotEnd := slotStart.Add(granularity); !slotEnd.After(to); slotStart, slotEnd = slotEnd, slotEnd.Add(granularity) {
+		for next < len(busy) && busy[next].End.Before(slotStart) {
+			next++
+		}
+		if next < len(busy) && !busy[next].Start.After(slotEnd) {
+			continue
+		}
+		slots = append(slots, TimeInterval{Start: slotStart, End: slotEnd})
+	}
+
+	return slots
+}
@@ -0,0 +1,187 @@
+package vehicle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+var day = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func at(hour, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestMergeBusyIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []repository.BusyInterval
+		want      []TimeInterval
+	}{
+		{
+			name:      "no intervals",
+			intervals: nil,
+			want:      []TimeInterval{},
+		},
+		{
+			name: "disjoint intervals are kept",
+			intervals: []repository.BusyInterval{
+				{StartsAt: at(1, 0), EndsAt: at(2, 0)},
+				{StartsAt: at(3, 0), EndsAt: at(4, 0)},
+			},
+			want: []TimeInterval{
+				{Start: at(1, 0), End: at(2, 0)},
+				{Start: at(3, 0), End: at(4, 0)},
+			},
+		},
+		{
+			name: "overlapping intervals are merged",
+			intervals: []repository.BusyInterval{
+				{StartsAt: at(1, 0), EndsAt: at(3, 0)},
+				{StartsAt: at(2, 0), EndsAt: at(4, 0)},
+			},
+			want: []TimeInterval{{Start: at(1, 0), End: at(4, 0)}},
+		},
+		{
+			name: "touching intervals are merged",
+			intervals: []repository.BusyInterval{
+				{StartsAt: at(1, 0), EndsAt: at(2, 0)},
+				{StartsAt: at(2, 0), EndsAt: at(3, 0)},
+			},
+			want: []TimeInterval{{Start: at(1, 0), End: at(3, 0)}},
+		},
+		{
+			name: "contained interval does not shrink the merged one",
+			intervals: []repository.BusyInterval{
+				{StartsAt: at(1, 0), EndsAt: at(5, 0)},
+				{StartsAt: at(2, 0), EndsAt: at(3, 0)},
+				{StartsAt: at(4, 0), EndsAt: at(6, 0)},
+			},
+			want: []TimeInterval{{Start: at(1, 0), End: at(6, 0)}},
+		},
+		{
+			name: "times are converted to UTC",
+			intervals: []repository.BusyInterval{
+				{StartsAt: at(1, 0).In(time.FixedZone("IST", 19800)), EndsAt: at(2, 0).In(time.FixedZone("IST", 19800))},
+			},
+			want: []TimeInterval{{Start: at(1, 0), End: at(2, 0)}},
+		},
+		{
+			name: "merged end is converted to UTC",
+			intervals: []repository.BusyInterval{
+				{StartsAt: at(1, 0), EndsAt: at(2, 0)},
+				{StartsAt: at(2, 0), EndsAt: at(3, 0).In(time.FixedZone("IST", 19800))},
+			},
+			want: []TimeInterval{{Start: at(1, 0), End: at(3, 0)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeBusyIntervals(tt.intervals)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeBusyIntervals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreeSlots(t *testing.T) {
+	tests := []struct {
+		name        string
+		busy        []TimeInterval
+		from        time.Time
+		to          time.Time
+		granularity time.Duration
+		want        []TimeInterval
+	}{
+		{
+			name:        "everything is free",
+			from:        at(0, 0),
+			to:          at(3, 0),
+			granularity: time.Hour,
+			want: []TimeInterval{
+				{Start: at(0, 0), End: at(1, 0)},
+				{Start: at(1, 0), End: at(2, 0)},
+				{Start: at(2, 0), End: at(3, 0)},
+			},
+		},
+		{
+			name:        "from is rounded up to the granularity",
+			from:        at(0, 20),
+			to:          at(3, 0),
+			granularity: time.Hour,
+			want: []TimeInterval{
+				{Start: at(1, 0), End: at(2, 0)},
+				{Start: at(2, 0), End: at(3, 0)},
+			},
+		},
+		{
+			name:        "a partial slot at the end is dropped",
+			from:        at(0, 0),
+			to:          at(2, 30),
+			granularity: time.Hour,
+			want: []TimeInterval{
+				{Start: at(0, 0), End: at(1, 0)},
+				{Start: at(1, 0), End: at(2, 0)},
+			},
+		},
+		{
+			name:        "slots touching a busy interval are not free",
+			busy:        []TimeInterval{{Start: at(2, 0), End: at(3, 0)}},
+			from:        at(0, 0),
+			to:          at(6, 0),
+			granularity: time.Hour,
+			want: []TimeInterval{
+				{Start: at(0, 0), End: at(1, 0)},
+				{Start: at(4, 0), End: at(5, 0)},
+				{Start: at(5, 0), End: at(6, 0)},
+			},
+		},
+		{
+			name:        "busy interval inside a slot blocks it",
+			busy:        []TimeInterval{{Start: at(1, 15), End: at(1, 45)}},
+			from:        at(0, 0),
+			to:          at(3, 0),
+			granularity: time.Hour,
+			want: []TimeInterval{
+				{Start: at(0, 0), End: at(1, 0)},
+				{Start: at(2, 0), End: at(3, 0)},
+			},
+		},
+		{
+			name: "several busy intervals",
+			busy: []TimeInterval{
+				{Start: at(0, 30), End: at(0, 45)},
+				{Start: at(3, 10), End: at(4, 50)},
+			},
+			from:        at(0, 0),
+			to:          at(7, 0),
+			granularity: time.Hour,
+			want: []TimeInterval{
+				{Start: at(1, 0), End: at(2, 0)},
+				{Start: at(2, 0), End: at(3, 0)},
+				{Start: at(5, 0), End: at(6, 0)},
+				{Start: at(6, 0), End: at(7, 0)},
+			},
+		},
+		{
+			name:        "range shorter than the granularity",
+			from:        at(0, 0),
+			to:          at(0, 30),
+			granularity: time.Hour,
+			want:        []TimeInterval{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := freeSlots(tt.busy, tt.from, tt.to, tt.granularity)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("freeSlots = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
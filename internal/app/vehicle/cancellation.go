@@ -0,0 +1,73 @@
+package vehicle
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// Cancellation policies
+	CancellationFlexible = "FLEXIBLE"
+	CancellationModerate = "MODERATE"
+	CancellationStrict   = "STRICT"
+
+	DefaultCancellationPolicy = CancellationFlexible
+)
+
+// CancellationTier refunds RefundPercent of the booking amount when a booking is cancelled at
+// least MinHoursBeforePickup before its scheduled pickup.
+type CancellationTier struct {
+	MinHoursBeforePickup int `json:"minHoursBeforePickup"`
+	RefundPercent        int `json:"refundPercent"`
+}
+
+// CancellationPolicy lists its tiers from the longest notice to the shortest. Cancelling with
+// less notice than the last tier, or after the scheduled pickup, refunds nothing.
+type CancellationPolicy struct {
+	Name  string             `json:"name"`
+	Tiers []CancellationTier `json:"tiers"`
+}
+
+var CancellationPolicies = map[string]CancellationPolicy{
+	CancellationFlexible: {
+		Name: CancellationFlexible,
+		Tiers: []CancellationTier{
+			{MinHoursBeforePickup: 24, RefundPercent: 100},
+			{MinHoursBeforePickup: 0, RefundPercent: 50},
+		},
+	},
+	CancellationModerate: {
+		Name: CancellationModerate,
+		Tiers: []CancellationTier{
+			{MinHoursBeforePickup: 72, RefundPercent: 100},
+			{MinHoursBeforePickup: 24, RefundPercent: 50},
+		},
+	},
+	CancellationStrict: {
+		Name: CancellationStrict,
+		Tiers: []CancellationTier{
+			{MinHoursBeforePickup: 168, RefundPercent: 50},
+		},
+	},
+}
+
+func (p CancellationPolicy) RefundPercent(noticeBeforePickup time.Duration) int {
+	for _, tier := range p.Tiers {
+		if noticeBeforePickup >= time.Duration(tier.MinHoursBeforePickup)*time.Hour {
+			return tier.RefundPercent
+		}
+	}
+
+	return 0
+}
+
+// cancellationPolicy is the requested policy, matched case-insensitively, or the default one
+// when none was given.
+func (v VehicleRequestBody) cancellationPolicy() string {
+	policy := strings.ToUpper(strings.TrimSpace(v.CancellationPolicy))
+	if policy == "" {
+		return DefaultCancellationPolicy
+	}
+
+	return policy
+}
@@ -0,0 +1,40 @@
+package vehicle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCancellationPolicyRefundPercent(t *testing.T) {
+	tests := []struct {
+		policy string
+		notice time.Duration
+		want   int
+	}{
+		{policy: CancellationFlexible, notice: 48 * time.Hour, want: 100},
+		{policy: CancellationFlexible, notice: 24 * time.Hour, want: 100},
+		{policy: CancellationFlexible, notice: 24*time.Hour - time.Second, want: 50},
+		{policy: CancellationFlexible, notice: 0, want: 50},
+		{policy: CancellationFlexible, notice: -time.Minute, want: 0},
+
+		{policy: CancellationModerate, notice: 72 * time.Hour, want: 100},
+		{policy: CancellationModerate, notice: 72*time.Hour - time.Second, want: 50},
+		{policy: CancellationModerate, notice: 24 * time.Hour, want: 50},
+		{policy: CancellationModerate, notice: 24*time.Hour - time.Second, want: 0},
+		{policy: CancellationModerate, notice: -time.Hour, want: 0},
+
+		{policy: CancellationStrict, notice: 200 * time.Hour, want: 50},
+		{policy: CancellationStrict, notice: 168 * time.Hour, want: 50},
+		{policy: CancellationStrict, notice: 168*time.Hour - time.Second, want: 0},
+		{policy: CancellationStrict, notice: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+"/"+tt.notice.String(), func(t *testing.T) {
+			got := CancellationPolicies[tt.policy].RefundPercent(tt.notice)
+			if got != tt.want {
+				t.Errorf("%s.RefundPercent(%s) = %d, want %d", tt.policy, tt.notice, got, tt.want)
+			}
+		})
+	}
+}
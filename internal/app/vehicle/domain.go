@@ -3,17 +3,45 @@ package vehicle
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
 )
 
 const (
 	SignedURLExpiry = 15 * time.Minute
-	AccessURLFormat = "https://firebasestorage.googleapis.com/v0/b/wheelio-2f2fa.firebasestorage.app/o/%s?alt=media"
+	ImageObjectDir  = "vehicles/"
+
+	MaxVehicleImages = 10
+
+	MaxBlackoutReasonLength = 255
+
+	DefaultAvailabilityRange = 7 * 24 * time.Hour
+
+	DefaultSearchRadiusKm = 10
+	MaxSearchRadiusKm     = 100
+
+	MaxSearchFeatures = 10
+
+	MaxSearchQueryLength = 100
+	MaxSearchTerms       = 8
+
+	MinSuggestionPrefixLength = 2
+	MaxSuggestionPrefixLength = 50
+	DefaultSuggestionLimit    = 5
+	MaxSuggestionLimit        = 10
+
+	// Image processing status
+	ImageProcessingPending   = "PENDING"
+	ImageProcessingProcessed = "PROCESSED"
+	ImageProcessingFailed    = "FAILED"
 )
 
 var AvailableFuelType = map[string]struct{}{
@@ -28,6 +56,14 @@ var AvailableTransmissionType = map[string]struct{}{
 	"Automatic": {},
 }
 
+var AvailableVehicleSorts = map[string]struct{}{
+	repository.VehicleSortNewest:    {},
+	repository.VehicleSortPriceAsc:  {},
+	repository.VehicleSortPriceDesc: {},
+	repository.VehicleSortDistance:  {},
+	repository.VehicleSortRelevance: {},
+}
+
 type Vehicle struct {
 	Id                    int             `json:"id"`
 	Name                  string          `json:"name"`
@@ -42,20 +78,29 @@ type Vehicle struct {
 	City                  string          `json:"city"`
 	PinCode               int             `json:"pinCode"`
 	CancellationAllowed   bool            `json:"cancellationAllowed"`
+	CancellationPolicy    string          `json:"cancellationPolicy"`
 	Images                []VehicleImage  `json:"images,omitempty"`
 	Available             bool            `json:"available"`
 	HostId                int             `json:"hostId"`
 	IsDeleted             bool            `json:"isDeleted"`
+	Latitude              *float64        `json:"latitude"`
+	Longitude             *float64        `json:"longitude"`
 	CreatedAt             time.Time       `json:"createdAt"`
 	UpdatedAt             time.Time       `json:"updatedAt"`
 }
 
 type VehicleImage struct {
-	Id        int       `json:"id,omitempty"`
-	VehicleId int       `json:"-"`
-	Url       string    `json:"url"`
-	Featured  bool      `json:"featured"`
-	CreatedAt time.Time `json:"createdAt,omitempty"`
+	Id           int       `json:"id,omitempty"`
+	VehicleId    int       `json:"-"`
+	UploadId     int       `json:"uploadId,omitempty"`
+	Url          string    `json:"url"`
+	ObjectPath   string    `json:"objectPath,omitempty"`
+	ThumbnailUrl string    `json:"thumbnailUrl,omitempty"`
+	MediumUrl    string    `json:"mediumUrl,omitempty"`
+	LargeUrl     string    `json:"largeUrl,omitempty"`
+	Featured     bool      `json:"featured"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"createdAt,omitempty"`
 }
 
 type VehicleRequestBody struct {
@@ -71,43 +116,112 @@ type VehicleRequestBody struct {
 	City                  string          `json:"city"`
 	PinCode               int             `json:"pinCode"`
 	CancellationAllowed   bool            `json:"cancellationAllowed"`
+	CancellationPolicy    string          `json:"cancellationPolicy"`
+	Latitude              *float64        `json:"latitude,omitempty"`
+	Longitude             *float64        `json:"longitude,omitempty"`
 	Images                []VehicleImage  `json:"images,omitempty"`
 }
 
 type GenerateSignedURLResponseBody struct {
-	SignedUrl string `json:"signedUrl"`
-	AccessUrl string `json:"accessUrl"`
+	UploadId   int    `json:"uploadId"`
+	SignedUrl  string `json:"signedUrl"`
+	AccessUrl  string `json:"accessUrl"`
+	ObjectPath string `json:"objectPath"`
+}
+
+type AddVehicleImageRequestBody struct {
+	UploadId int  `json:"uploadId"`
+	Featured bool `json:"featured"`
+}
+
+type ReorderVehicleImagesRequestBody struct {
+	ImageIds []int `json:"imageIds"`
+}
+
+type VehicleBlackout struct {
+	Id        int       `json:"id"`
+	VehicleId int       `json:"vehicleId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type VehicleBlackoutRequestBody struct {
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	Reason   string    `json:"reason"`
+}
+
+type TimeInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type VehicleAvailability struct {
+	VehicleId   int            `json:"vehicleId"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	Available   bool           `json:"available"`
+	SlotMinutes int            `json:"slotMinutes"`
+	Busy        []TimeInterval `json:"busy"`
+	FreeSlots   []TimeInterval `json:"freeSlots"`
+}
+
+type RewriteImageURLsResult struct {
+	Updated int
+	Skipped int
+}
+
+type GeocodeVehiclesResult struct {
+	Updated int
+	Failed  int
 }
 
 type VehicleOverview struct {
-	Id               int     `json:"id"`
-	Name             string  `json:"name"`
-	FuelType         string  `json:"fuelType"`
-	SeatCount        int     `json:"seatCount"`
-	TransmissionType string  `json:"transmissionType"`
-	Image            string  `json:"image"`
-	RatePerHour      float64 `json:"ratePerHour"`
-	Address          string  `json:"address"`
-	PinCode          int     `json:"pinCode"`
+	Id               int      `json:"id"`
+	Name             string   `json:"name"`
+	FuelType         string   `json:"fuelType"`
+	SeatCount        int      `json:"seatCount"`
+	TransmissionType string   `json:"transmissionType"`
+	Image            string   `json:"image"`
+	RatePerHour      float64  `json:"ratePerHour"`
+	Address          string   `json:"address"`
+	PinCode          int      `json:"pinCode"`
+	DistanceKm       *float64 `json:"distanceKm,omitempty"`
 }
 
-type PaginationParams struct {
-	Page       int `json:"page"`
-	PageSize   int `json:"pageSize"`
-	TotalCount int `json:"totalCount"`
+type VehicleSuggestion struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 type PaginatedVehicleOverview struct {
 	Data       []VehicleOverview `json:"data"`
-	Pagination PaginationParams  `json:"pagination"`
+	Pagination pagination.Page   `json:"pagination"`
+}
+
+type VehicleSearchFilters struct {
+	FuelTypes           []string
+	TransmissionTypes   []string
+	MinSeatCount        int
+	MinRatePerHour      *float64
+	MaxRatePerHour      *float64
+	CancellationAllowed *bool
+	Features            []string
 }
 
 type GetVehiclesParams struct {
 	City             string
+	Query            string
+	Latitude         *float64
+	Longitude        *float64
+	RadiusKm         float64
+	Filters          VehicleSearchFilters
+	Sort             string
 	PickupTimestamp  time.Time
 	DropoffTimestamp time.Time
-	Page             int
-	Limit            int
+	Pagination       pagination.Request
 }
 
 func (v VehicleRequestBody) validate() error {
@@ -157,14 +271,47 @@ func (v VehicleRequestBody) validate() error {
 		validationErrors = append(validationErrors, "pin code must be a 6-digit integer")
 	}
 
+	if _, ok := CancellationPolicies[v.cancellationPolicy()]; !ok {
+		validationErrors = append(validationErrors, "cancellation policy is invalid")
+	}
+
+	if (v.Latitude == nil) != (v.Longitude == nil) {
+		validationErrors = append(validationErrors, "latitude and longitude must both be provided or both omitted")
+	} else if v.Latitude != nil && !validCoordinates(*v.Latitude, *v.Longitude) {
+		validationErrors = append(validationErrors, "latitude must be within [-90, 90] and longitude within [-180, 180]")
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
+	}
+
+	return nil
+}
+
+// validateImages applies to vehicle creation only; afterwards images are managed through the
+// vehicle image endpoints.
+func (v VehicleRequestBody) validateImages() error {
+	var validationErrors []string
+
 	if len(v.Images) == 0 {
 		validationErrors = append(validationErrors, "at least one image is required")
+	} else if len(v.Images) > MaxVehicleImages {
+		validationErrors = append(validationErrors, fmt.Sprintf("at most %d images are allowed", MaxVehicleImages))
 	} else {
 		featuredCount := 0
+		seenUploads := make(map[int]struct{}, len(v.Images))
 		for _, img := range v.Images {
 			if img.Featured {
 				featuredCount++
 			}
+			if img.UploadId <= 0 {
+				validationErrors = append(validationErrors, "each image must reference an upload id")
+				continue
+			}
+			if _, ok := seenUploads[img.UploadId]; ok {
+				validationErrors = append(validationErrors, "images must not be repeated")
+			}
+			seenUploads[img.UploadId] = struct{}{}
 		}
 		if featuredCount != 1 {
 			validationErrors = append(validationErrors, "exactly one image must have the featured flag set to true")
@@ -178,6 +325,176 @@ func (v VehicleRequestBody) validate() error {
 	return nil
 }
 
+func (p GetVehiclesParams) validateLocation() error {
+	if (p.Latitude == nil) != (p.Longitude == nil) {
+		return fmt.Errorf("lat and lng must both be provided or both omitted")
+	}
+
+	if p.Latitude == nil {
+		if strings.TrimSpace(p.City) == "" && strings.TrimSpace(p.Query) == "" {
+			return fmt.Errorf("city, lat and lng, or q is required")
+		}
+		return nil
+	}
+
+	if !validCoordinates(*p.Latitude, *p.Longitude) {
+		return fmt.Errorf("lat must be within [-90, 90] and lng within [-180, 180]")
+	}
+
+	if !isFinite(p.RadiusKm) || p.RadiusKm <= 0 || p.RadiusKm > MaxSearchRadiusKm {
+		return fmt.Errorf("radiusKm must be greater than 0 and at most %d", MaxSearchRadiusKm)
+	}
+
+	return nil
+}
+
+func (f VehicleSearchFilters) validate() error {
+	var validationErrors []string
+
+	for _, fuelType := range f.FuelTypes {
+		if _, ok := AvailableFuelType[fuelType]; !ok {
+			validationErrors = append(validationErrors, fmt.Sprintf("fuel type %q is invalid", fuelType))
+		}
+	}
+
+	for _, transmissionType := range f.TransmissionTypes {
+		if _, ok := AvailableTransmissionType[transmissionType]; !ok {
+			validationErrors = append(validationErrors, fmt.Sprintf("transmission type %q is invalid", transmissionType))
+		}
+	}
+
+	if f.MinSeatCount < 0 {
+		validationErrors = append(validationErrors, "minSeats cannot be negative")
+	}
+
+	if f.MinRatePerHour != nil && (!isFinite(*f.MinRatePerHour) || *f.MinRatePerHour < 0) {
+		validationErrors = append(validationErrors, "minPrice must be a non-negative number")
+	}
+
+	if f.MaxRatePerHour != nil && (!isFinite(*f.MaxRatePerHour) || *f.MaxRatePerHour < 0) {
+		validationErrors = append(validationErrors, "maxPrice must be a non-negative number")
+	}
+
+	if f.MinRatePerHour != nil && f.MaxRatePerHour != nil && *f.MinRatePerHour > *f.MaxRatePerHour {
+		validationErrors = append(validationErrors, "minPrice cannot be greater than maxPrice")
+	}
+
+	if len(f.Features) > MaxSearchFeatures {
+		validationErrors = append(validationErrors, fmt.Sprintf("at most %d features can be requested", MaxSearchFeatures))
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
+	}
+
+	return nil
+}
+
+func (p GetVehiclesParams) validateSort() error {
+	if _, ok := AvailableVehicleSorts[p.Sort]; !ok {
+		return fmt.Errorf("sort %q is invalid", p.Sort)
+	}
+
+	if p.Sort == repository.VehicleSortDistance && p.Latitude == nil {
+		return fmt.Errorf("sorting by distance requires lat and lng")
+	}
+
+	if p.Sort == repository.VehicleSortRelevance && strings.TrimSpace(p.Query) == "" {
+		return fmt.Errorf("sorting by relevance requires q")
+	}
+
+	return nil
+}
+
+func (p GetVehiclesParams) validateQuery() error {
+	if len(p.Query) > MaxSearchQueryLength {
+		return fmt.Errorf("q must be at most %d characters", MaxSearchQueryLength)
+	}
+
+	if strings.TrimSpace(p.Query) != "" && len(searchTerms(p.Query)) == 0 {
+		return fmt.Errorf("q must contain at least one letter or digit")
+	}
+
+	return nil
+}
+
+// searchTerms splits a search query into lowercase words of letters and digits, dropping
+// punctuation so nothing the user types can change the meaning of the text search query.
+// Combining marks, such as Devanagari vowel signs, are part of the word they follow.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if !slices.Contains(terms, word) {
+			terms = append(terms, word)
+		}
+		if len(terms) == MaxSearchTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+func validCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// isFinite rejects NaN, which passes every range check because all comparisons with it are false.
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+func (r ReorderVehicleImagesRequestBody) validate(currentImages []repository.VehicleImage) error {
+	if len(r.ImageIds) != len(currentImages) {
+		return fmt.Errorf("validation failed: all %d images must be listed exactly once", len(currentImages))
+	}
+
+	remaining := make(map[int]struct{}, len(currentImages))
+	for _, image := range currentImages {
+		remaining[image.Id] = struct{}{}
+	}
+	for _, imageId := range r.ImageIds {
+		if _, ok := remaining[imageId]; !ok {
+			return fmt.Errorf("validation failed: image %d is unknown or repeated", imageId)
+		}
+		delete(remaining, imageId)
+	}
+
+	return nil
+}
+
+func (b VehicleBlackoutRequestBody) validate() error {
+	var validationErrors []string
+
+	if b.StartsAt.IsZero() {
+		validationErrors = append(validationErrors, "startsAt is required")
+	}
+
+	if b.EndsAt.IsZero() {
+		validationErrors = append(validationErrors, "endsAt is required")
+	} else if b.EndsAt.Before(time.Now()) {
+		validationErrors = append(validationErrors, "endsAt must not be in past")
+	}
+
+	if !b.StartsAt.IsZero() && !b.EndsAt.IsZero() && !b.StartsAt.Before(b.EndsAt) {
+		validationErrors = append(validationErrors, "startsAt must be before endsAt")
+	}
+
+	if len(b.Reason) > MaxBlackoutReasonLength {
+		validationErrors = append(validationErrors, fmt.Sprintf("reason must be at most %d characters", MaxBlackoutReasonLength))
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("validation failed: %s", strings.Join(validationErrors, "; "))
+	}
+
+	return nil
+}
+
 func mapVehicleRequestBodyToCreateUserRequestBodyRepo(vehicleRequestBody VehicleRequestBody) repository.CreateVehicleRequestBody {
 	mappedVehicle := repository.CreateVehicleRequestBody{
 		Name:                  vehicleRequestBody.Name,
@@ -192,6 +509,9 @@ func mapVehicleRequestBodyToCreateUserRequestBodyRepo(vehicleRequestBody Vehicle
 		City:                  vehicleRequestBody.City,
 		PinCode:               vehicleRequestBody.PinCode,
 		CancellationAllowed:   vehicleRequestBody.CancellationAllowed,
+		CancellationPolicy:    vehicleRequestBody.cancellationPolicy(),
+		Latitude:              vehicleRequestBody.Latitude,
+		Longitude:             vehicleRequestBody.Longitude,
 	}
 
 	return mappedVehicle
@@ -211,6 +531,9 @@ func mapVehicleRequestBodyToEditUserRequestBodyRepo(vehicleRequestBody VehicleRe
 		City:                  vehicleRequestBody.City,
 		PinCode:               vehicleRequestBody.PinCode,
 		CancellationAllowed:   vehicleRequestBody.CancellationAllowed,
+		CancellationPolicy:    vehicleRequestBody.cancellationPolicy(),
+		Latitude:              vehicleRequestBody.Latitude,
+		Longitude:             vehicleRequestBody.Longitude,
 	}
 
 	return mappedVehicle
@@ -219,7 +542,7 @@ func mapVehicleRequestBodyToEditUserRequestBodyRepo(vehicleRequestBody VehicleRe
 func mapVehicleRepoAndVehicleImageRepoToVehicle(vehicle repository.Vehicle, images []repository.VehicleImage) Vehicle {
 	convertedImages := make([]VehicleImage, len(images))
 	for i, img := range images {
-		convertedImages[i] = VehicleImage(img)
+		convertedImages[i] = mapVehicleImageRepoToVehicleImage(img)
 	}
 
 	mappedVehicle := Vehicle{
@@ -236,10 +559,13 @@ func mapVehicleRepoAndVehicleImageRepoToVehicle(vehicle repository.Vehicle, imag
 		City:                  vehicle.City,
 		PinCode:               vehicle.PinCode,
 		CancellationAllowed:   vehicle.CancellationAllowed,
+		CancellationPolicy:    vehicle.CancellationPolicy,
 		Images:                convertedImages,
 		Available:             vehicle.Available,
 		HostId:                vehicle.HostId,
 		IsDeleted:             vehicle.IsDeleted,
+		Latitude:              vehicle.Latitude,
+		Longitude:             vehicle.Longitude,
 		CreatedAt:             vehicle.CreatedAt,
 		UpdatedAt:             vehicle.UpdatedAt,
 	}
@@ -260,6 +586,43 @@ func parseQueryParamToInt(r *http.Request, param string, defaultValue int) (int,
 	return value, nil
 }
 
+func parseQueryParamToFloat(r *http.Request, param string) (*float64, error) {
+	query := r.URL.Query().Get(param)
+	if query == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(query, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func parseQueryParamToList(r *http.Request, param string) []string {
+	var values []string
+	for _, value := range strings.Split(r.URL.Query().Get(param), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func parseQueryParamToBool(r *http.Request, param string) (*bool, error) {
+	query := r.URL.Query().Get(param)
+	if query == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(query)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
 func parsePickupDropoffTimeStamp(r *http.Request) (time.Time, time.Time, error) {
 	pickupQuery := r.URL.Query().Get("pickup")
 	dropoffQuery := r.URL.Query().Get("dropoff")
@@ -296,3 +659,70 @@ func parsePickupDropoffTimeStamp(r *http.Request) (time.Time, time.Time, error)
 
 	return pickup, dropoff, nil
 }
+
+func parseAvailabilityRange(r *http.Request) (time.Time, time.Time, error) {
+	fromQuery := r.URL.Query().Get("from")
+	toQuery := r.URL.Query().Get("to")
+
+	if (fromQuery == "" && toQuery != "") || (fromQuery != "" && toQuery == "") {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid query parameters: from and to must both be provided or both omitted")
+	}
+
+	from := time.Now().UTC()
+	to := from.Add(DefaultAvailabilityRange)
+	if fromQuery == "" {
+		return from, to, nil
+	}
+
+	from, err := time.Parse(time.RFC3339Nano, fromQuery)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse from time: %w", err)
+	}
+
+	to, err = time.Parse(time.RFC3339Nano, toQuery)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse to time: %w", err)
+	}
+
+	return from.UTC(), to.UTC(), nil
+}
+
+func mapVehicleImageRepoToVehicleImage(image repository.VehicleImage) VehicleImage {
+	mappedImage := VehicleImage{
+		Id:        image.Id,
+		VehicleId: image.VehicleId,
+		Url:       image.Url,
+		Featured:  image.Featured,
+		Position:  image.Position,
+		CreatedAt: image.CreatedAt,
+	}
+	if image.ObjectPath != nil {
+		mappedImage.ObjectPath = *image.ObjectPath
+	}
+	if image.UploadId != nil {
+		mappedImage.UploadId = *image.UploadId
+	}
+	// Variant fields hold object paths until the service resolves them to access URLs.
+	if image.ThumbnailPath != nil {
+		mappedImage.ThumbnailUrl = *image.ThumbnailPath
+	}
+	if image.MediumPath != nil {
+		mappedImage.MediumUrl = *image.MediumPath
+	}
+	if image.LargePath != nil {
+		mappedImage.LargeUrl = *image.LargePath
+	}
+
+	return mappedImage
+}
+
+func mapVehicleBlackoutRepoToVehicleBlackout(blackout repository.VehicleBlackout) VehicleBlackout {
+	return VehicleBlackout{
+		Id:        blackout.Id,
+		VehicleId: blackout.VehicleId,
+		StartsAt:  blackout.StartsAt,
+		EndsAt:    blackout.EndsAt,
+		Reason:    blackout.Reason,
+		CreatedAt: blackout.CreatedAt,
+	}
+}
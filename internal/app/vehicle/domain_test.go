@@ -0,0 +1,37 @@
+package vehicle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty", query: "", want: []string{}},
+		{name: "only punctuation", query: " !&|:*() ", want: []string{}},
+		{name: "lowercased words", query: "Honda City", want: []string{"honda", "city"}},
+		{name: "punctuation splits words", query: "honda-city,pune", want: []string{"honda", "city", "pune"}},
+		{name: "tsquery operators are dropped", query: "honda & !city | suv:* <->", want: []string{"honda", "city", "suv"}},
+		{name: "digits are kept", query: "seat 7 XUV700", want: []string{"seat", "7", "xuv700"}},
+		{name: "duplicates are dropped", query: "city City CITY pune", want: []string{"city", "pune"}},
+		{name: "unicode letters are kept", query: "पुणे कार", want: []string{"पुणे", "कार"}},
+		{
+			name:  "at most MaxSearchTerms terms",
+			query: "a b c d e f g h i j",
+			want:  []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchTerms(tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
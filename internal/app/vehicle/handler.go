@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/response"
 )
 
@@ -93,7 +94,7 @@ func GenerateSignedVehicleImageUploadURL(vehicleService Service) http.HandlerFun
 		ctx := r.Context()
 		mimetype := r.URL.Query().Get("mimetype")
 
-		signedUrl, accessUrl, err := vehicleService.GenerateSignedVehicleImageUploadURL(ctx, mimetype)
+		signedUpload, err := vehicleService.GenerateSignedVehicleImageUploadURL(ctx, mimetype)
 		if err != nil {
 			slog.Error("failed to generate signed url for vehicle image upload", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -101,11 +102,7 @@ func GenerateSignedVehicleImageUploadURL(vehicleService Service) http.HandlerFun
 			return
 		}
 
-		signedUrlResponse := GenerateSignedURLResponseBody{
-			SignedUrl: signedUrl,
-			AccessUrl: accessUrl,
-		}
-		response.WriteJson(w, http.StatusOK, "signed url generated successfully", signedUrlResponse)
+		response.WriteJson(w, http.StatusOK, "signed url generated successfully", signedUpload)
 	}
 }
 
@@ -132,27 +129,97 @@ func GetVehicleById(vehicleService Service) http.HandlerFunc {
 	}
 }
 
-func GetVehicles(vehicleService Service) http.HandlerFunc {
+func GetVehicleAvailability(vehicleService Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
 
-		page, err := parseQueryParamToInt(r, "page", 1)
+		from, to, err := parseAvailabilityRange(r)
 		if err != nil {
-			slog.Error("failed to parse page number to int", "error", err)
+			slog.Error("failed to parse availability range", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
 
-		limit, err := parseQueryParamToInt(r, "limit", 10)
+		availability, err := vehicleService.GetVehicleAvailability(ctx, parsedVehicleId, from, to)
 		if err != nil {
-			slog.Error("failed to parse page limit to int", "error", err)
+			slog.Error("failed to fetch vehicle availability", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle availability fetched successfully", availability)
+	}
+}
+
+func GetVehicles(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		pageRequest, err := pagination.ParseRequest(r)
+		if err != nil {
+			slog.Error("failed to parse pagination parameters", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
 
 		city := r.URL.Query().Get("city")
-		if city == "" {
-			slog.Error("no city provided in query")
+
+		latitude, err := parseQueryParamToFloat(r, "lat")
+		if err != nil {
+			slog.Error("failed to parse latitude to float", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		longitude, err := parseQueryParamToFloat(r, "lng")
+		if err != nil {
+			slog.Error("failed to parse longitude to float", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		radiusKm, err := parseQueryParamToFloat(r, "radiusKm")
+		if err != nil {
+			slog.Error("failed to parse search radius to float", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+		if radiusKm == nil {
+			defaultRadiusKm := float64(DefaultSearchRadiusKm)
+			radiusKm = &defaultRadiusKm
+		}
+
+		minSeats, err := parseQueryParamToInt(r, "minSeats", 0)
+		if err != nil {
+			slog.Error("failed to parse minimum seat count to int", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		minPrice, err := parseQueryParamToFloat(r, "minPrice")
+		if err != nil {
+			slog.Error("failed to parse minimum price to float", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		maxPrice, err := parseQueryParamToFloat(r, "maxPrice")
+		if err != nil {
+			slog.Error("failed to parse maximum price to float", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+
+		cancellationAllowed, err := parseQueryParamToBool(r, "cancellationAllowed")
+		if err != nil {
+			slog.Error("failed to parse cancellation allowed to bool", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
@@ -164,12 +231,27 @@ func GetVehicles(vehicleService Service) http.HandlerFunc {
 			return
 		}
 
+		filters := VehicleSearchFilters{
+			FuelTypes:           parseQueryParamToList(r, "fuelType"),
+			TransmissionTypes:   parseQueryParamToList(r, "transmissionType"),
+			MinSeatCount:        minSeats,
+			MinRatePerHour:      minPrice,
+			MaxRatePerHour:      maxPrice,
+			CancellationAllowed: cancellationAllowed,
+			Features:            parseQueryParamToList(r, "features"),
+		}
+
 		params := GetVehiclesParams{
 			City:             city,
+			Query:            r.URL.Query().Get("q"),
+			Latitude:         latitude,
+			Longitude:        longitude,
+			RadiusKm:         *radiusKm,
+			Filters:          filters,
+			Sort:             r.URL.Query().Get("sort"),
 			PickupTimestamp:  pickup,
 			DropoffTimestamp: dropoff,
-			Page:             page,
-			Limit:            limit,
+			Pagination:       pageRequest,
 		}
 		vehicles, err := vehicleService.GetVehicles(ctx, params)
 		if err != nil {
@@ -183,25 +265,41 @@ func GetVehicles(vehicleService Service) http.HandlerFunc {
 	}
 }
 
-func GetVehiclesForHost(vehicleService Service) http.HandlerFunc {
+func GetVehicleSuggestions(vehicleService Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		page, err := parseQueryParamToInt(r, "page", 1)
+		limit, err := parseQueryParamToInt(r, "limit", DefaultSuggestionLimit)
 		if err != nil {
-			slog.Error("failed to parse page number to int", "error", err)
+			slog.Error("failed to parse suggestion limit to int", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
 
-		limit, err := parseQueryParamToInt(r, "limit", 10)
+		suggestions, err := vehicleService.GetVehicleSuggestions(ctx, r.URL.Query().Get("q"), limit)
 		if err != nil {
-			slog.Error("failed to parse page limit to int", "error", err)
+			slog.Error("failed to fetch vehicle suggestions", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle suggestions fetched successfully", suggestions)
+	}
+}
+
+func GetVehiclesForHost(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		pageRequest, err := pagination.ParseRequest(r)
+		if err != nil {
+			slog.Error("failed to parse pagination parameters", "error", err)
 			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
 			return
 		}
 
-		vehicles, err := vehicleService.GetVehiclesForHost(ctx, int(page), int(limit))
+		vehicles, err := vehicleService.GetVehiclesForHost(ctx, pageRequest)
 		if err != nil {
 			slog.Error("failed to fetch vehicles", "error", err)
 			status, errorMessage := apperrors.MapError(err)
@@ -212,3 +310,258 @@ func GetVehiclesForHost(vehicleService Service) http.HandlerFunc {
 		response.WriteJson(w, http.StatusOK, "vehicles fetched successfully", vehicles)
 	}
 }
+
+func AddVehicleImage(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		var requestBody AddVehicleImageRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		vehicleImage, err := vehicleService.AddVehicleImage(ctx, parsedVehicleId, requestBody)
+		if err != nil {
+			slog.Error("failed to add vehicle image", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle image added successfully", vehicleImage)
+	}
+}
+
+func DeleteVehicleImage(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		parsedImageId, err := strconv.Atoi(r.PathValue("imageId"))
+		if err != nil {
+			slog.Error("invalid vehicle image id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle image id", nil)
+			return
+		}
+
+		err = vehicleService.DeleteVehicleImage(ctx, parsedVehicleId, parsedImageId)
+		if err != nil {
+			slog.Error("failed to delete vehicle image", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle image deleted successfully", nil)
+	}
+}
+
+func ReorderVehicleImages(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		var requestBody ReorderVehicleImagesRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		vehicleImages, err := vehicleService.ReorderVehicleImages(ctx, parsedVehicleId, requestBody)
+		if err != nil {
+			slog.Error("failed to reorder vehicle images", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle images reordered successfully", vehicleImages)
+	}
+}
+
+func SetFeaturedVehicleImage(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		parsedImageId, err := strconv.Atoi(r.PathValue("imageId"))
+		if err != nil {
+			slog.Error("invalid vehicle image id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle image id", nil)
+			return
+		}
+
+		vehicleImages, err := vehicleService.SetFeaturedVehicleImage(ctx, parsedVehicleId, parsedImageId)
+		if err != nil {
+			slog.Error("failed to set featured vehicle image", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "featured vehicle image updated successfully", vehicleImages)
+	}
+}
+
+func PauseVehicle(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		err = vehicleService.PauseVehicle(ctx, parsedVehicleId)
+		if err != nil {
+			slog.Error("failed to pause vehicle", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle paused successfully", nil)
+	}
+}
+
+func UnpauseVehicle(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		err = vehicleService.UnpauseVehicle(ctx, parsedVehicleId)
+		if err != nil {
+			slog.Error("failed to unpause vehicle", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle unpaused successfully", nil)
+	}
+}
+
+func CreateVehicleBlackout(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		var requestBody VehicleBlackoutRequestBody
+		err = json.NewDecoder(r.Body).Decode(&requestBody)
+		if err != nil {
+			slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+			return
+		}
+
+		blackout, err := vehicleService.CreateVehicleBlackout(ctx, parsedVehicleId, requestBody)
+		if err != nil {
+			slog.Error("failed to create vehicle blackout", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle blackout created successfully", blackout)
+	}
+}
+
+func GetVehicleBlackouts(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		blackouts, err := vehicleService.GetVehicleBlackouts(ctx, parsedVehicleId)
+		if err != nil {
+			slog.Error("failed to fetch vehicle blackouts", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle blackouts fetched successfully", blackouts)
+	}
+}
+
+func DeleteVehicleBlackout(vehicleService Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		parsedVehicleId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			slog.Error("invalid vehicle id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle id", nil)
+			return
+		}
+
+		parsedBlackoutId, err := strconv.Atoi(r.PathValue("blackoutId"))
+		if err != nil {
+			slog.Error("invalid vehicle blackout id", "error", err)
+			response.WriteJson(w, http.StatusBadRequest, "invalid vehicle blackout id", nil)
+			return
+		}
+
+		err = vehicleService.DeleteVehicleBlackout(ctx, parsedVehicleId, parsedBlackoutId)
+		if err != nil {
+			slog.Error("failed to delete vehicle blackout", "error", err)
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, "vehicle blackout deleted successfully", nil)
+	}
+}
+
+func GetCancellationPolicies() http.HandlerFunc {
+	policies := []CancellationPolicy{
+		CancellationPolicies[CancellationFlexible],
+		CancellationPolicies[CancellationModerate],
+		CancellationPolicies[CancellationStrict],
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, "cancellation policies fetched successfully", policies)
+	}
+}
@@ -0,0 +1,253 @@
+package vehicle
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+func (s *service) AddVehicleImage(ctx context.Context, vehicleId int, imageData AddVehicleImageRequestBody) (vehicleImage VehicleImage, err error) {
+	if imageData.UploadId <= 0 {
+		slog.Error("invalid upload id provided", "uploadId", imageData.UploadId)
+		return VehicleImage{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.vehicleRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start vehicle image creation", "error", err)
+		return VehicleImage{}, err
+	}
+
+	defer func() {
+		if txErr := s.vehicleRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	currentImages, err := s.lockVehicleImages(ctx, tx, vehicleId)
+	if err != nil {
+		return VehicleImage{}, err
+	}
+
+	if len(currentImages) >= MaxVehicleImages {
+		slog.Error("vehicle image limit reached", "vehicleId", vehicleId)
+		return VehicleImage{}, apperrors.ErrVehicleImageLimit
+	}
+
+	uploads, err := s.uploadService.LinkImageUploads(ctx, tx, []int{imageData.UploadId})
+	if err != nil {
+		slog.Error("failed to link image upload", "error", err)
+		return VehicleImage{}, err
+	}
+
+	createdVehicleImage, err := s.createVehicleImage(ctx, tx, vehicleId, uploads[imageData.UploadId], false, len(currentImages))
+	if err != nil {
+		return VehicleImage{}, err
+	}
+
+	if imageData.Featured || len(currentImages) == 0 {
+		err = s.vehicleRepository.SetFeaturedVehicleImage(ctx, tx, vehicleId, createdVehicleImage.Id)
+		if err != nil {
+			slog.Error("failed to set featured vehicle image", "error", err)
+			return VehicleImage{}, err
+		}
+		createdVehicleImage.Featured = true
+	}
+
+	vehicleImage = mapVehicleImageRepoToVehicleImage(createdVehicleImage)
+	err = s.resolveImageURL(ctx, &vehicleImage)
+	if err != nil {
+		return VehicleImage{}, err
+	}
+
+	return vehicleImage, nil
+}
+
+// DeleteVehicleImage removes the image and then its stored objects. Object deletion happens
+// after the commit and is best effort: the upload garbage collector retries anything left over.
+func (s *service) DeleteVehicleImage(ctx context.Context, vehicleId, imageId int) error {
+	removedImage, err := s.removeVehicleImage(ctx, vehicleId, imageId)
+	if err != nil {
+		return err
+	}
+
+	for _, objectPath := range []*string{removedImage.ObjectPath, removedImage.ThumbnailPath, removedImage.MediumPath, removedImage.LargePath} {
+		if objectPath == nil {
+			continue
+		}
+		err = s.storageService.DeleteObject(ctx, *objectPath)
+		if err != nil {
+			slog.Error("failed to delete vehicle image object", "imageId", imageId, "objectPath", *objectPath, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) removeVehicleImage(ctx context.Context, vehicleId, imageId int) (removedImage repository.VehicleImage, err error) {
+	tx, err := s.vehicleRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start vehicle image deletion", "error", err)
+		return repository.VehicleImage{}, err
+	}
+
+	defer func() {
+		if txErr := s.vehicleRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	currentImages, err := s.lockVehicleImages(ctx, tx, vehicleId)
+	if err != nil {
+		return repository.VehicleImage{}, err
+	}
+
+	removedImage, err = s.vehicleRepository.GetVehicleImageById(ctx, tx, vehicleId, imageId)
+	if err != nil {
+		slog.Error("failed to get vehicle image", "error", err)
+		return repository.VehicleImage{}, err
+	}
+
+	if len(currentImages) == 1 {
+		slog.Error("cannot delete the only image of a vehicle", "vehicleId", vehicleId)
+		return repository.VehicleImage{}, apperrors.ErrLastVehicleImage
+	}
+
+	err = s.vehicleRepository.DeleteVehicleImage(ctx, tx, vehicleId, imageId)
+	if err != nil {
+		slog.Error("failed to delete vehicle image", "error", err)
+		return repository.VehicleImage{}, err
+	}
+
+	remainingImageIds := make([]int, 0, len(currentImages)-1)
+	for _, image := range currentImages {
+		if image.Id != imageId {
+			remainingImageIds = append(remainingImageIds, image.Id)
+		}
+	}
+
+	err = s.vehicleRepository.UpdateVehicleImagePositions(ctx, tx, vehicleId, remainingImageIds)
+	if err != nil {
+		slog.Error("failed to update vehicle image positions", "error", err)
+		return repository.VehicleImage{}, err
+	}
+
+	if removedImage.Featured {
+		err = s.vehicleRepository.SetFeaturedVehicleImage(ctx, tx, vehicleId, remainingImageIds[0])
+		if err != nil {
+			slog.Error("failed to set featured vehicle image", "error", err)
+			return repository.VehicleImage{}, err
+		}
+	}
+
+	return removedImage, nil
+}
+
+func (s *service) ReorderVehicleImages(ctx context.Context, vehicleId int, orderData ReorderVehicleImagesRequestBody) (vehicleImages []VehicleImage, err error) {
+	tx, err := s.vehicleRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start vehicle image reordering", "error", err)
+		return []VehicleImage{}, err
+	}
+
+	defer func() {
+		if txErr := s.vehicleRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	currentImages, err := s.lockVehicleImages(ctx, tx, vehicleId)
+	if err != nil {
+		return []VehicleImage{}, err
+	}
+
+	err = orderData.validate(currentImages)
+	if err != nil {
+		slog.Error("vehicle image order validation failed", "error", err)
+		return []VehicleImage{}, apperrors.ErrInvalidRequestBody
+	}
+
+	err = s.vehicleRepository.UpdateVehicleImagePositions(ctx, tx, vehicleId, orderData.ImageIds)
+	if err != nil {
+		slog.Error("failed to update vehicle image positions", "error", err)
+		return []VehicleImage{}, err
+	}
+
+	return s.getVehicleImages(ctx, tx, vehicleId)
+}
+
+func (s *service) SetFeaturedVehicleImage(ctx context.Context, vehicleId, imageId int) (vehicleImages []VehicleImage, err error) {
+	tx, err := s.vehicleRepository.BeginTx(ctx)
+	if err != nil {
+		slog.Error("failed to start featured vehicle image update", "error", err)
+		return []VehicleImage{}, err
+	}
+
+	defer func() {
+		if txErr := s.vehicleRepository.HandleTransaction(ctx, tx, err); txErr != nil {
+			slog.Error("failed to handle transaction", "error", txErr)
+			err = txErr
+		}
+	}()
+
+	_, err = s.lockVehicleImages(ctx, tx, vehicleId)
+	if err != nil {
+		return []VehicleImage{}, err
+	}
+
+	err = s.vehicleRepository.SetFeaturedVehicleImage(ctx, tx, vehicleId, imageId)
+	if err != nil {
+		slog.Error("failed to set featured vehicle image", "error", err)
+		return []VehicleImage{}, err
+	}
+
+	return s.getVehicleImages(ctx, tx, vehicleId)
+}
+
+// lockVehicleImages checks ownership and locks the vehicle so that concurrent image changes
+// cannot exceed the image limit or interleave position updates.
+func (s *service) lockVehicleImages(ctx context.Context, tx *sql.Tx, vehicleId int) ([]repository.VehicleImage, error) {
+	_, err := s.authorizeVehicleOwner(ctx, tx, vehicleId)
+	if err != nil {
+		return []repository.VehicleImage{}, err
+	}
+
+	err = s.vehicleRepository.LockVehicle(ctx, tx, vehicleId)
+	if err != nil {
+		slog.Error("failed to lock vehicle", "error", err)
+		return []repository.VehicleImage{}, err
+	}
+
+	currentImages, err := s.vehicleRepository.GetVehicleImagesByVehicleId(ctx, tx, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle images", "error", err)
+		return []repository.VehicleImage{}, err
+	}
+
+	return currentImages, nil
+}
+
+func (s *service) getVehicleImages(ctx context.Context, tx *sql.Tx, vehicleId int) ([]VehicleImage, error) {
+	images, err := s.vehicleRepository.GetVehicleImagesByVehicleId(ctx, tx, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle images", "error", err)
+		return []VehicleImage{}, err
+	}
+
+	vehicleImages := make([]VehicleImage, len(images))
+	for i, image := range images {
+		vehicleImages[i] = mapVehicleImageRepoToVehicleImage(image)
+		err = s.resolveImageURL(ctx, &vehicleImages[i])
+		if err != nil {
+			return []VehicleImage{}, err
+		}
+	}
+
+	return vehicleImages, nil
+}
@@ -0,0 +1,92 @@
+package vehicle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/geocoding"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+const geocodeVehiclesBatchSize = 50
+
+// locateVehicle returns the coordinates given by the host, otherwise the geocoded address.
+// Geocoding is best effort: a vehicle that cannot be located is still listed, but it is only
+// found by city until coordinates are set. The lookup is bounded by the request timeout so a
+// slow or busy geocoder cannot hold up the request; the geocode-vehicles command fills in
+// vehicles that were saved without coordinates.
+func (s *service) locateVehicle(ctx context.Context, vehicleData VehicleRequestBody) (*float64, *float64) {
+	if vehicleData.Latitude != nil {
+		return vehicleData.Latitude, vehicleData.Longitude
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.geocodingCfg.RequestTimeout)
+	defer cancel()
+
+	coordinates, err := s.geocodingService.Geocode(ctx, geocoding.Address{
+		Line:    vehicleData.Address,
+		City:    vehicleData.City,
+		State:   vehicleData.State,
+		PinCode: vehicleData.PinCode,
+	})
+	if err != nil {
+		slog.Warn("failed to geocode vehicle address", "city", vehicleData.City, "pinCode", vehicleData.PinCode, "error", err)
+		return nil, nil
+	}
+
+	return &coordinates.Latitude, &coordinates.Longitude
+}
+
+func sameAddress(vehicle repository.Vehicle, vehicleData VehicleRequestBody) bool {
+	return strings.EqualFold(strings.TrimSpace(vehicle.Address), strings.TrimSpace(vehicleData.Address)) &&
+		strings.EqualFold(strings.TrimSpace(vehicle.City), strings.TrimSpace(vehicleData.City)) &&
+		strings.EqualFold(strings.TrimSpace(vehicle.State), strings.TrimSpace(vehicleData.State)) &&
+		vehicle.PinCode == vehicleData.PinCode
+}
+
+// GeocodeVehicles fills in coordinates for vehicles listed before they were stored, or whose
+// address could not be geocoded when it was saved.
+func (s *service) GeocodeVehicles(ctx context.Context) (GeocodeVehiclesResult, error) {
+	var result GeocodeVehiclesResult
+
+	lastId := 0
+	for {
+		vehicles, err := s.vehicleRepository.GetVehiclesWithoutCoordinates(ctx, nil, lastId, geocodeVehiclesBatchSize)
+		if err != nil {
+			slog.Error("failed to get vehicles without coordinates", "error", err)
+			return result, err
+		}
+
+		for _, vehicle := range vehicles {
+			lastId = vehicle.Id
+			coordinates, err := s.geocodingService.Geocode(ctx, geocoding.Address{
+				Line:    vehicle.Address,
+				City:    vehicle.City,
+				State:   vehicle.State,
+				PinCode: vehicle.PinCode,
+			})
+			if err != nil {
+				if !errors.Is(err, apperrors.ErrLocationNotFound) {
+					return result, err
+				}
+				slog.Warn("no location found for vehicle address", "vehicleId", vehicle.Id)
+				result.Failed++
+				continue
+			}
+
+			err = s.vehicleRepository.UpdateVehicleCoordinates(ctx, nil, vehicle.Id, coordinates.Latitude, coordinates.Longitude)
+			if err != nil {
+				slog.Error("failed to update vehicle coordinates", "vehicleId", vehicle.Id, "error", err)
+				return result, err
+			}
+			result.Updated++
+		}
+
+		if len(vehicles) < geocodeVehiclesBatchSize {
+			return result, nil
+		}
+	}
+}
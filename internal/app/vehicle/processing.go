@@ -0,0 +1,113 @@
+package vehicle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/imaging"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/worker"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
+)
+
+// ProcessPendingImages generates the resized variants of newly linked images and strips the
+// metadata of the original. Failures are retried with a fixed delay until the
+// attempts run out; the original image keeps being served.
+func (s *service) ProcessPendingImages(ctx context.Context) error {
+	vehicleImages, err := s.vehicleRepository.ClaimPendingVehicleImages(ctx, nil, s.cfg.BatchSize, s.cfg.LeaseDuration)
+	if err != nil {
+		slog.Error("failed to claim pending vehicle images", "error", err)
+		return err
+	}
+
+	for _, vehicleImage := range vehicleImages {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		variants, processErr := s.processImage(ctx, *vehicleImage.ObjectPath)
+		if processErr == nil {
+			err = s.vehicleRepository.MarkVehicleImageProcessed(ctx, nil, vehicleImage.Id, variants)
+			if err != nil {
+				slog.Error("failed to mark vehicle image as processed", "imageId", vehicleImage.Id, "error", err)
+			}
+			continue
+		}
+
+		status := ImageProcessingPending
+		// Images that cannot be decoded will never succeed, so there is no point retrying them.
+		if vehicleImage.ProcessingAttempts >= s.cfg.MaxAttempts || errors.Is(processErr, imaging.ErrUnsupportedImage) {
+			status = ImageProcessingFailed
+		}
+		slog.Error("failed to process vehicle image", "imageId", vehicleImage.Id, "attempt", vehicleImage.ProcessingAttempts, "status", status, "error", processErr)
+
+		err = s.vehicleRepository.MarkVehicleImageProcessingFailed(ctx, nil, vehicleImage.Id, status, time.Now().Add(s.cfg.RetryDelay), processErr.Error())
+		if err != nil {
+			slog.Error("failed to record vehicle image processing failure", "imageId", vehicleImage.Id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) StartWorker(ctx context.Context) {
+	worker.RunPeriodically(ctx, "image-processing", s.cfg.PollInterval, s.ProcessPendingImages)
+}
+
+func (s *service) processImage(ctx context.Context, objectPath string) (repository.VehicleImageVariants, error) {
+	object, err := s.storageService.GetObject(ctx, objectPath)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrObjectNotFound) {
+			return repository.VehicleImageVariants{}, imaging.ErrUnsupportedImage
+		}
+		return repository.VehicleImageVariants{}, err
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return repository.VehicleImageVariants{}, err
+	}
+
+	img, format, err := imaging.Decode(data)
+	if err != nil {
+		return repository.VehicleImageVariants{}, err
+	}
+
+	variantPaths := make(map[string]string, len(imaging.Variants))
+	for _, variant := range imaging.Variants {
+		var encoded bytes.Buffer
+		err = imaging.EncodeJPEG(&encoded, imaging.Resize(img, variant.MaxDimension), s.cfg.JPEGQuality)
+		if err != nil {
+			return repository.VehicleImageVariants{}, err
+		}
+
+		variantPath := imaging.VariantPath(objectPath, variant)
+		err = s.storageService.PutObject(ctx, variantPath, imaging.VariantContentType, &encoded)
+		if err != nil {
+			return repository.VehicleImageVariants{}, err
+		}
+		variantPaths[variant.Name] = variantPath
+	}
+
+	// The original is only replaced once every variant is stored. Stripping leaves the pixels
+	// untouched, so a retry after a failed attempt stores the same bytes again.
+	original, err := imaging.StripMetadata(data, format)
+	if err != nil {
+		return repository.VehicleImageVariants{}, err
+	}
+	err = s.storageService.PutObject(ctx, objectPath, "image/"+format, bytes.NewReader(original))
+	if err != nil {
+		return repository.VehicleImageVariants{}, err
+	}
+
+	return repository.VehicleImageVariants{
+		ThumbnailPath: variantPaths[imaging.Thumbnail.Name],
+		MediumPath:    variantPaths[imaging.Medium.Name],
+		LargePath:     variantPaths[imaging.Large.Name],
+	}, nil
+}
@@ -2,37 +2,69 @@ package vehicle
 
 import (
 	"context"
-	"errors"
+	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
-	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/firebase"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/geocoding"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/storage"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/app/upload"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/config"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/middleware"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/repository"
 	"github.com/google/uuid"
 )
 
 type service struct {
 	vehicleRepository repository.VehicleRepository
-	firebaseService   firebase.Service
+	storageService    storage.Service
+	uploadService     upload.Service
+	geocodingService  geocoding.Service
+	cfg               config.ImageProcessing
+	availabilityCfg   config.Availability
+	geocodingCfg      config.Geocoding
 }
 
 type Service interface {
 	CreateVehicle(ctx context.Context, vehicleData VehicleRequestBody) (Vehicle, error)
 	UpdateVehicle(ctx context.Context, vehicleData VehicleRequestBody, vehicleId int) (Vehicle, error)
 	SoftDeleteVehicle(ctx context.Context, vehicleId int) (err error)
-	GenerateSignedVehicleImageUploadURL(ctx context.Context, mimetype string) (signedUrl, accessUrl string, err error)
+	GenerateSignedVehicleImageUploadURL(ctx context.Context, mimetype string) (signedUpload GenerateSignedURLResponseBody, err error)
+	ImageAccessURL(ctx context.Context, imageRef string) (string, error)
+	RewriteImageURLs(ctx context.Context, fromBaseURL string, dryRun bool) (RewriteImageURLsResult, error)
+	GeocodeVehicles(ctx context.Context) (GeocodeVehiclesResult, error)
+	AddVehicleImage(ctx context.Context, vehicleId int, imageData AddVehicleImageRequestBody) (vehicleImage VehicleImage, err error)
+	DeleteVehicleImage(ctx context.Context, vehicleId, imageId int) (err error)
+	ReorderVehicleImages(ctx context.Context, vehicleId int, orderData ReorderVehicleImagesRequestBody) (vehicleImages []VehicleImage, err error)
+	SetFeaturedVehicleImage(ctx context.Context, vehicleId, imageId int) (vehicleImages []VehicleImage, err error)
+	PauseVehicle(ctx context.Context, vehicleId int) (err error)
+	UnpauseVehicle(ctx context.Context, vehicleId int) (err error)
+	CreateVehicleBlackout(ctx context.Context, vehicleId int, blackoutData VehicleBlackoutRequestBody) (blackout VehicleBlackout, err error)
+	GetVehicleBlackouts(ctx context.Context, vehicleId int) (blackouts []VehicleBlackout, err error)
+	DeleteVehicleBlackout(ctx context.Context, vehicleId, blackoutId int) (err error)
+	GetVehicleAvailability(ctx context.Context, vehicleId int, from, to time.Time) (availability VehicleAvailability, err error)
+	ProcessPendingImages(ctx context.Context) (err error)
+	StartWorker(ctx context.Context)
 	GetVehicleById(ctx context.Context, vehicleId int) (vehicle Vehicle, err error)
+	GetVehicleRatePerHour(ctx context.Context, vehicleId int) (ratePerHour float64, err error)
 	GetVehicles(ctx context.Context, params GetVehiclesParams) (vehicles PaginatedVehicleOverview, err error)
-	GetVehiclesForHost(ctx context.Context, page, limit int) (vehicles PaginatedVehicleOverview, err error)
+	GetVehiclesForHost(ctx context.Context, pageRequest pagination.Request) (vehicles PaginatedVehicleOverview, err error)
+	GetVehicleSuggestions(ctx context.Context, prefix string, limit int) (suggestions []VehicleSuggestion, err error)
 }
 
-func NewService(vehicleRepository repository.VehicleRepository, firebaseService firebase.Service) Service {
+func NewService(vehicleRepository repository.VehicleRepository, storageService storage.Service, uploadService upload.Service, geocodingService geocoding.Service, cfg config.ImageProcessing, availabilityCfg config.Availability, geocodingCfg config.Geocoding) Service {
 	return &service{
 		vehicleRepository: vehicleRepository,
-		firebaseService:   firebaseService,
+		storageService:    storageService,
+		uploadService:     uploadService,
+		geocodingService:  geocodingService,
+		cfg:               cfg,
+		availabilityCfg:   availabilityCfg,
+		geocodingCfg:      geocodingCfg,
 	}
 }
 
@@ -49,6 +81,16 @@ func (s *service) CreateVehicle(ctx context.Context, vehicleData VehicleRequestB
 		return Vehicle{}, apperrors.ErrInvalidRequestBody
 	}
 
+	err = vehicleData.validateImages()
+	if err != nil {
+		slog.Error("vehicle images validation failed", "error", err)
+		return Vehicle{}, apperrors.ErrInvalidRequestBody
+	}
+
+	createVehicleData := mapVehicleRequestBodyToCreateUserRequestBodyRepo(vehicleData)
+	createVehicleData.HostId = userId
+	createVehicleData.Latitude, createVehicleData.Longitude = s.locateVehicle(ctx, vehicleData)
+
 	tx, err := s.vehicleRepository.BeginTx(ctx)
 	if err != nil {
 		slog.Error("failed to start user creation", "error", err)
@@ -62,33 +104,18 @@ func (s *service) CreateVehicle(ctx context.Context, vehicleData VehicleRequestB
 		}
 	}()
 
-	createVehicleData := mapVehicleRequestBodyToCreateUserRequestBodyRepo(vehicleData)
-	createVehicleData.HostId = userId
 	vehicle, err := s.vehicleRepository.CreateVehicle(ctx, tx, createVehicleData)
 	if err != nil {
 		slog.Error("failed to create new vehicle", "error", err)
 		return Vehicle{}, err
 	}
 
-	var vehicleImages []repository.VehicleImage
-	for _, vehicleImage := range vehicleData.Images {
-		vehicleImageData := repository.CreateVehicleImageData{
-			VehicleId: vehicle.Id,
-			Url:       vehicleImage.Url,
-			Featured:  vehicleImage.Featured,
-		}
-		createdVehicleImage, err := s.vehicleRepository.CreateVehicleImage(ctx, tx, vehicleImageData)
-		if err != nil {
-			slog.Error("failed to link image with vehicle", "error", err)
-			if errors.Is(err, apperrors.ErrInvalidImageToLink) {
-				return Vehicle{}, apperrors.ErrInvalidRequestBody
-			}
-			return Vehicle{}, err
-		}
-		vehicleImages = append(vehicleImages, createdVehicleImage)
+	vehicleImages, err := s.linkVehicleImages(ctx, tx, vehicle.Id, vehicleData.Images)
+	if err != nil {
+		return Vehicle{}, err
 	}
 
-	return mapVehicleRepoAndVehicleImageRepoToVehicle(vehicle, vehicleImages), nil
+	return s.resolveImageURLs(ctx, mapVehicleRepoAndVehicleImageRepoToVehicle(vehicle, vehicleImages))
 }
 
 func (s *service) UpdateVehicle(ctx context.Context, vehicleData VehicleRequestBody, vehicleId int) (newVehicle Vehicle, err error) {
@@ -98,6 +125,35 @@ func (s *service) UpdateVehicle(ctx context.Context, vehicleData VehicleRequestB
 		return Vehicle{}, apperrors.ErrInvalidRequestBody
 	}
 
+	// Images are managed through their own endpoints; silently dropping them here would make
+	// clients believe the update went through.
+	if vehicleData.Images != nil {
+		slog.Error("vehicle update includes images")
+		return Vehicle{}, apperrors.ErrVehicleImagesNotEditable
+	}
+
+	hostId, err := s.authorizeVehicleOwner(ctx, nil, vehicleId)
+	if err != nil {
+		return Vehicle{}, err
+	}
+
+	currentVehicle, err := s.vehicleRepository.GetVehicleById(ctx, nil, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle details", "error", err)
+		return Vehicle{}, err
+	}
+
+	// Geocoding waits on a rate limited API, so it is done before the transaction is opened.
+	// The update itself is scoped to the host, which keeps the ownership check valid.
+	editVehicleData := mapVehicleRequestBodyToEditUserRequestBodyRepo(vehicleData)
+	editVehicleData.Id = vehicleId
+	editVehicleData.HostId = hostId
+	if vehicleData.Latitude == nil && sameAddress(currentVehicle, vehicleData) {
+		editVehicleData.Latitude, editVehicleData.Longitude = currentVehicle.Latitude, currentVehicle.Longitude
+	} else {
+		editVehicleData.Latitude, editVehicleData.Longitude = s.locateVehicle(ctx, vehicleData)
+	}
+
 	tx, err := s.vehicleRepository.BeginTx(ctx)
 	if err != nil {
 		slog.Error("failed to start user updating", "error", err)
@@ -111,43 +167,28 @@ func (s *service) UpdateVehicle(ctx context.Context, vehicleData VehicleRequestB
 		}
 	}()
 
-	editVehicleData := mapVehicleRequestBodyToEditUserRequestBodyRepo(vehicleData)
-	editVehicleData.Id = vehicleId
 	vehicle, err := s.vehicleRepository.UpdateVehicle(ctx, tx, editVehicleData)
 	if err != nil {
 		slog.Error("failed to update vehicle", "error", err)
 		return Vehicle{}, err
 	}
 
-	err = s.vehicleRepository.DeleteAllImagesForVehicle(ctx, tx, vehicleId)
+	vehicleImages, err := s.vehicleRepository.GetVehicleImagesByVehicleId(ctx, tx, vehicleId)
 	if err != nil {
-		slog.Error("failed to delete images for vehicle", "error", err)
+		slog.Error("failed to get vehicle images", "error", err)
 		return Vehicle{}, err
 	}
 
-	var vehicleImages []repository.VehicleImage
-	for _, vehicleImage := range vehicleData.Images {
-		vehicleImageData := repository.CreateVehicleImageData{
-			VehicleId: vehicle.Id,
-			Url:       vehicleImage.Url,
-			Featured:  vehicleImage.Featured,
-		}
-		createdVehicleImage, err := s.vehicleRepository.CreateVehicleImage(ctx, tx, vehicleImageData)
-		if err != nil {
-			slog.Error("failed to link image with vehicle", "error", err)
-			if errors.Is(err, apperrors.ErrInvalidImageToLink) {
-				return Vehicle{}, apperrors.ErrInvalidRequestBody
-			}
-			return Vehicle{}, err
-		}
-		vehicleImages = append(vehicleImages, createdVehicleImage)
-	}
-
-	return mapVehicleRepoAndVehicleImageRepoToVehicle(vehicle, vehicleImages), nil
+	return s.resolveImageURLs(ctx, mapVehicleRepoAndVehicleImageRepoToVehicle(vehicle, vehicleImages))
 }
 
 func (s *service) SoftDeleteVehicle(ctx context.Context, vehicleId int) (err error) {
-	err = s.vehicleRepository.SoftDeleteVehicle(ctx, nil, vehicleId)
+	hostId, err := s.authorizeVehicleOwner(ctx, nil, vehicleId)
+	if err != nil {
+		return err
+	}
+
+	err = s.vehicleRepository.SoftDeleteVehicle(ctx, nil, vehicleId, hostId)
 	if err != nil {
 		slog.Error("failed to soft delete vehicle", "error", err)
 		return err
@@ -156,11 +197,12 @@ func (s *service) SoftDeleteVehicle(ctx context.Context, vehicleId int) (err err
 	return nil
 }
 
-func (s *service) GenerateSignedVehicleImageUploadURL(ctx context.Context, mimetype string) (signedUrl, accessUrl string, err error) {
+func (s *service) GenerateSignedVehicleImageUploadURL(ctx context.Context, mimetype string) (signedUpload GenerateSignedURLResponseBody, err error) {
 	timestamp := time.Now().UnixNano()
 	randomStr := uuid.New().String()
 
-	objectPath := fmt.Sprintf("vehicles/%d-%s",
+	objectPath := fmt.Sprintf("%s%d-%s",
+		ImageObjectDir,
 		timestamp,
 		randomStr,
 	)
@@ -169,15 +211,40 @@ func (s *service) GenerateSignedVehicleImageUploadURL(ctx context.Context, mimet
 		mimetype = "image/jpeg"
 	}
 
-	signedUrl, err = s.firebaseService.GenerateSignedURL(ctx, objectPath, mimetype, SignedURLExpiry)
+	imageUpload, err := s.uploadService.CreateImageUpload(ctx, objectPath, mimetype)
+	if err != nil {
+		slog.Error("failed to create vehicle image upload", "error", err)
+		return GenerateSignedURLResponseBody{}, err
+	}
+
+	signedUrl, err := s.storageService.GenerateSignedUploadURL(ctx, objectPath, mimetype, SignedURLExpiry)
 	if err != nil {
 		slog.Error("failed to generate signed url for vehicle image upload", "error", err)
-		return "", "", err
+		return GenerateSignedURLResponseBody{}, err
 	}
 
-	accessUrl = fmt.Sprintf(AccessURLFormat, fmt.Sprintf("vehicles%%2F%d-%s", timestamp, randomStr))
+	accessUrl, err := s.storageService.AccessURL(ctx, objectPath)
+	if err != nil {
+		slog.Error("failed to generate access url for vehicle image", "error", err)
+		return GenerateSignedURLResponseBody{}, err
+	}
+
+	return GenerateSignedURLResponseBody{
+		UploadId:   imageUpload.Id,
+		SignedUrl:  signedUrl,
+		AccessUrl:  accessUrl,
+		ObjectPath: objectPath,
+	}, nil
+}
+
+func (s *service) ImageAccessURL(ctx context.Context, imageRef string) (string, error) {
+	accessUrl, err := s.storageService.AccessURL(ctx, imageRef)
+	if err != nil {
+		slog.Error("failed to generate access url for vehicle image", "imageRef", imageRef, "error", err)
+		return "", err
+	}
 
-	return signedUrl, accessUrl, nil
+	return accessUrl, nil
 }
 
 func (s *service) GetVehicleById(ctx context.Context, vehicleId int) (vehicle Vehicle, err error) {
@@ -193,7 +260,19 @@ func (s *service) GetVehicleById(ctx context.Context, vehicleId int) (vehicle Ve
 		return Vehicle{}, err
 	}
 
-	return mapVehicleRepoAndVehicleImageRepoToVehicle(vehicleDetails, vehicleImages), nil
+	return s.resolveImageURLs(ctx, mapVehicleRepoAndVehicleImageRepoToVehicle(vehicleDetails, vehicleImages))
+}
+
+// GetVehicleRatePerHour returns the hourly rate of a vehicle, including soft-deleted ones, to
+// price changes to their existing bookings.
+func (s *service) GetVehicleRatePerHour(ctx context.Context, vehicleId int) (float64, error) {
+	ratePerHour, err := s.vehicleRepository.GetVehicleRatePerHour(ctx, nil, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle rate per hour", "error", err)
+		return 0, err
+	}
+
+	return ratePerHour, nil
 }
 
 func (s *service) GetVehicles(ctx context.Context, params GetVehiclesParams) (vehicles PaginatedVehicleOverview, err error) {
@@ -202,26 +281,60 @@ func (s *service) GetVehicles(ctx context.Context, params GetVehiclesParams) (ve
 		return PaginatedVehicleOverview{}, apperrors.ErrInvalidPickupDropoff
 	}
 
-	if params.Page <= 0 {
-		slog.Error("invalid page number provided", "page", params.Page)
-		return PaginatedVehicleOverview{}, apperrors.ErrInvalidPagination
+	err = params.validateLocation()
+	if err != nil {
+		slog.Error("invalid vehicle search location", "error", err)
+		return PaginatedVehicleOverview{}, apperrors.ErrInvalidQueryParams
 	}
 
-	if params.Limit <= 0 {
-		slog.Error("invalid limit value provided", "limit", params.Limit)
-		return PaginatedVehicleOverview{}, apperrors.ErrInvalidPagination
+	err = params.validateQuery()
+	if err != nil {
+		slog.Error("invalid vehicle search query", "error", err)
+		return PaginatedVehicleOverview{}, apperrors.ErrInvalidQueryParams
 	}
 
-	offset := params.Limit * (params.Page - 1)
+	if params.Sort == "" {
+		switch {
+		case strings.TrimSpace(params.Query) != "":
+			params.Sort = repository.VehicleSortRelevance
+		case params.Latitude != nil:
+			params.Sort = repository.VehicleSortDistance
+		default:
+			params.Sort = repository.VehicleSortNewest
+		}
+	}
+
+	err = params.validateSort()
+	if err != nil {
+		slog.Error("invalid vehicle search sort", "error", err)
+		return PaginatedVehicleOverview{}, apperrors.ErrInvalidQueryParams
+	}
+
+	err = params.Filters.validate()
+	if err != nil {
+		slog.Error("vehicle search filters validation failed", "error", err)
+		return PaginatedVehicleOverview{}, apperrors.ErrInvalidQueryParams
+	}
+
+	pageParams, err := params.Pagination.Params(params.Sort)
+	if err != nil {
+		return PaginatedVehicleOverview{}, err
+	}
 
 	repoParams := repository.GetVehiclesParams{
 		City:             params.City,
+		SearchTerms:      searchTerms(params.Query),
 		PickupTimestamp:  params.PickupTimestamp,
 		DropoffTimestamp: params.DropoffTimestamp,
-		Offset:           offset,
-		Limit:            params.Limit,
+		Page:             pageParams,
+		BookingBuffer:    s.availabilityCfg.BookingBuffer,
+		Filters:          repository.VehicleSearchFilters(params.Filters),
+		Sort:             params.Sort,
+	}
+	if params.Latitude != nil {
+		repoParams.Near = &repository.GeoRadius{Latitude: *params.Latitude, Longitude: *params.Longitude, RadiusKm: params.RadiusKm}
 	}
-	vehicleList, totalVehicles, err := s.vehicleRepository.GetVehicles(ctx, nil, repoParams)
+	vehicleList, pageInfo, err := s.vehicleRepository.GetVehicles(ctx, nil, repoParams)
 	if err != nil {
 		slog.Error("failed to get vehicle list", "error", err)
 		return PaginatedVehicleOverview{}, err
@@ -230,42 +343,35 @@ func (s *service) GetVehicles(ctx context.Context, params GetVehiclesParams) (ve
 	vehicleData := make([]VehicleOverview, len(vehicleList))
 	for i, v := range vehicleList {
 		vehicleData[i] = VehicleOverview(v)
+		vehicleData[i].Image, err = s.ImageAccessURL(ctx, v.Image)
+		if err != nil {
+			return PaginatedVehicleOverview{}, err
+		}
 	}
 
 	return PaginatedVehicleOverview{
-		Data: vehicleData,
-		Pagination: PaginationParams{
-			Page:       params.Page,
-			PageSize:   params.Limit,
-			TotalCount: totalVehicles,
-		}}, nil
+		Data:       vehicleData,
+		Pagination: pagination.NewPage(params.Pagination, params.Sort, pageInfo),
+	}, nil
 }
 
-func (s *service) GetVehiclesForHost(ctx context.Context, page, limit int) (vehicles PaginatedVehicleOverview, err error) {
+func (s *service) GetVehiclesForHost(ctx context.Context, pageRequest pagination.Request) (vehicles PaginatedVehicleOverview, err error) {
 	userId, ok := ctx.Value(middleware.RequestContextUserIdKey).(int)
 	if !ok {
 		slog.Error("failed to retrieve user id from context")
 		return PaginatedVehicleOverview{}, apperrors.ErrInternalServer
 	}
 
-	if page <= 0 {
-		slog.Error("invalid page number provided", "page", page)
-		return PaginatedVehicleOverview{}, apperrors.ErrInvalidPagination
-	}
-
-	if limit <= 0 {
-		slog.Error("invalid limit value provided", "limit", limit)
-		return PaginatedVehicleOverview{}, apperrors.ErrInvalidPagination
+	pageParams, err := pageRequest.Params(repository.VehicleSortNewest)
+	if err != nil {
+		return PaginatedVehicleOverview{}, err
 	}
 
-	offset := limit * (page - 1)
-
 	repoParams := repository.GetVehiclesForHostParams{
 		HostId: userId,
-		Offset: offset,
-		Limit:  limit,
+		Page:   pageParams,
 	}
-	vehicleList, totalVehicles, err := s.vehicleRepository.GetVehiclesForHost(ctx, nil, repoParams)
+	vehicleList, pageInfo, err := s.vehicleRepository.GetVehiclesForHost(ctx, nil, repoParams)
 	if err != nil {
 		slog.Error("failed to get vehicle list for host", "error", err)
 		return PaginatedVehicleOverview{}, err
@@ -274,13 +380,159 @@ func (s *service) GetVehiclesForHost(ctx context.Context, page, limit int) (vehi
 	vehicleData := make([]VehicleOverview, len(vehicleList))
 	for i, v := range vehicleList {
 		vehicleData[i] = VehicleOverview(v)
+		vehicleData[i].Image, err = s.ImageAccessURL(ctx, v.Image)
+		if err != nil {
+			return PaginatedVehicleOverview{}, err
+		}
 	}
 
 	return PaginatedVehicleOverview{
-		Data: vehicleData,
-		Pagination: PaginationParams{
-			Page:       page,
-			PageSize:   limit,
-			TotalCount: totalVehicles,
-		}}, nil
+		Data:       vehicleData,
+		Pagination: pagination.NewPage(pageRequest, repository.VehicleSortNewest, pageInfo),
+	}, nil
+}
+
+func (s *service) GetVehicleSuggestions(ctx context.Context, prefix string, limit int) (suggestions []VehicleSuggestion, err error) {
+	prefix = strings.Join(strings.Fields(prefix), " ")
+	if len(prefix) < MinSuggestionPrefixLength || len(prefix) > MaxSuggestionPrefixLength {
+		slog.Error("invalid suggestion prefix length", "length", len(prefix))
+		return []VehicleSuggestion{}, apperrors.ErrInvalidQueryParams
+	}
+
+	if limit <= 0 || limit > MaxSuggestionLimit {
+		slog.Error("invalid suggestion limit provided", "limit", limit)
+		return []VehicleSuggestion{}, apperrors.ErrInvalidQueryParams
+	}
+
+	suggestionList, err := s.vehicleRepository.GetVehicleSuggestions(ctx, nil, prefix, limit)
+	if err != nil {
+		slog.Error("failed to get vehicle suggestions", "error", err)
+		return []VehicleSuggestion{}, err
+	}
+
+	suggestions = make([]VehicleSuggestion, len(suggestionList))
+	for i, suggestion := range suggestionList {
+		suggestions[i] = VehicleSuggestion{Type: suggestion.Kind, Value: suggestion.Value}
+	}
+
+	return suggestions, nil
+}
+
+// RewriteImageURLs points every stored image URL at the currently configured storage location.
+// Images without a recorded object path are matched against fromBaseURL or the legacy Firebase
+// download URL format; images that match neither are skipped and left untouched.
+func (s *service) RewriteImageURLs(ctx context.Context, fromBaseURL string, dryRun bool) (RewriteImageURLsResult, error) {
+	vehicleImages, err := s.vehicleRepository.GetAllVehicleImages(ctx, nil)
+	if err != nil {
+		slog.Error("failed to get vehicle images", "error", err)
+		return RewriteImageURLsResult{}, err
+	}
+
+	var result RewriteImageURLsResult
+	for _, vehicleImage := range vehicleImages {
+		var objectPath string
+		if vehicleImage.ObjectPath != nil {
+			objectPath = *vehicleImage.ObjectPath
+		} else {
+			var ok bool
+			objectPath, ok = storage.ObjectPathFromURL(vehicleImage.Url, fromBaseURL)
+			if !ok {
+				slog.Warn("skipping vehicle image with unrecognised url", "imageId", vehicleImage.Id, "url", vehicleImage.Url)
+				result.Skipped++
+				continue
+			}
+		}
+
+		url := s.storageService.PublicURL(objectPath)
+		if vehicleImage.ObjectPath != nil && url == vehicleImage.Url {
+			continue
+		}
+
+		slog.Info("rewriting vehicle image url", "imageId", vehicleImage.Id, "from", vehicleImage.Url, "to", url, "dryRun", dryRun)
+		result.Updated++
+		if dryRun {
+			continue
+		}
+
+		err = s.vehicleRepository.UpdateVehicleImageLocation(ctx, nil, vehicleImage.Id, url, objectPath)
+		if err != nil {
+			slog.Error("failed to rewrite vehicle image url", "imageId", vehicleImage.Id, "error", err)
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// linkVehicleImages attaches verified pending uploads to a new vehicle in request order.
+func (s *service) linkVehicleImages(ctx context.Context, tx *sql.Tx, vehicleId int, images []VehicleImage) ([]repository.VehicleImage, error) {
+	uploadIds := make([]int, len(images))
+	for i, image := range images {
+		uploadIds[i] = image.UploadId
+	}
+
+	uploads, err := s.uploadService.LinkImageUploads(ctx, tx, uploadIds)
+	if err != nil {
+		slog.Error("failed to link image uploads", "error", err)
+		return []repository.VehicleImage{}, err
+	}
+
+	var vehicleImages []repository.VehicleImage
+	for i, image := range images {
+		createdVehicleImage, err := s.createVehicleImage(ctx, tx, vehicleId, uploads[image.UploadId], image.Featured, i)
+		if err != nil {
+			return []repository.VehicleImage{}, err
+		}
+		vehicleImages = append(vehicleImages, createdVehicleImage)
+	}
+
+	return vehicleImages, nil
+}
+
+func (s *service) createVehicleImage(ctx context.Context, tx *sql.Tx, vehicleId int, imageUpload upload.Upload, featured bool, position int) (repository.VehicleImage, error) {
+	vehicleImageData := repository.CreateVehicleImageData{
+		VehicleId:        vehicleId,
+		Url:              s.storageService.PublicURL(imageUpload.ObjectPath),
+		Featured:         featured,
+		ObjectPath:       &imageUpload.ObjectPath,
+		UploadId:         &imageUpload.Id,
+		ProcessingStatus: ImageProcessingPending,
+		Position:         position,
+	}
+	createdVehicleImage, err := s.vehicleRepository.CreateVehicleImage(ctx, tx, vehicleImageData)
+	if err != nil {
+		slog.Error("failed to link image with vehicle", "error", err)
+		return repository.VehicleImage{}, err
+	}
+
+	return createdVehicleImage, nil
+}
+
+func (s *service) resolveImageURLs(ctx context.Context, vehicle Vehicle) (Vehicle, error) {
+	for i := range vehicle.Images {
+		err := s.resolveImageURL(ctx, &vehicle.Images[i])
+		if err != nil {
+			return Vehicle{}, err
+		}
+	}
+
+	return vehicle, nil
+}
+
+func (s *service) resolveImageURL(ctx context.Context, image *VehicleImage) error {
+	imageRef := image.Url
+	if image.ObjectPath != "" {
+		imageRef = image.ObjectPath
+	}
+
+	var err error
+	for _, url := range []*string{&imageRef, &image.ThumbnailUrl, &image.MediumUrl, &image.LargeUrl} {
+		*url, err = s.ImageAccessURL(ctx, *url)
+		if err != nil {
+			return err
+		}
+	}
+	image.Url = imageRef
+
+	return nil
 }
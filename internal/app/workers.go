@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// StartWorkers launches the background workers and returns a function that blocks until
+// all of them have stopped after ctx is cancelled.
+func StartWorkers(ctx context.Context, deps Dependencies) (wait func()) {
+	var wg sync.WaitGroup
+
+	workers := []func(ctx context.Context){
+		deps.OutboxService.StartWorker,
+		deps.UploadService.StartWorker,
+		deps.VehicleService.StartWorker,
+		deps.SchedulerService.StartWorker,
+	}
+	for _, run := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(ctx)
+		}()
+	}
+
+	return wg.Wait
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"errors"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -20,23 +22,146 @@ type Database struct {
 }
 
 type EmailService struct {
-	ApiKey    string `yaml:"api_key" required:"true"`
+	Driver    string `yaml:"driver" env-default:"sendgrid"`
+	ApiKey    string `yaml:"api_key"`
 	FromName  string `yaml:"from_name" required:"true"`
 	FromEmail string `yaml:"from_email" required:"true"`
+	// Sends are aborted after this long; it must stay below the outbox lease duration.
+	SendTimeout time.Duration `yaml:"send_timeout" env-default:"20s"`
+	SMTP        SMTP          `yaml:"smtp"`
+	File        EmailFile     `yaml:"file"`
 }
 
-type FirebaseService struct {
-	BucketName          string `yaml:"bucket_name" required:"true"`
-	CredentialsFile string `yaml:"credentials_file" required:"true"`
+type SMTP struct {
+	Host     string `yaml:"host" env-default:"localhost"`
+	Port     int    `yaml:"port" env-default:"1025"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type EmailFile struct {
+	Directory string `yaml:"directory" env-default:"./tmp/emails"`
+}
+
+type Outbox struct {
+	PollInterval  time.Duration `yaml:"poll_interval" env-default:"5s"`
+	BatchSize     int           `yaml:"batch_size" env-default:"20"`
+	MaxAttempts   int           `yaml:"max_attempts" env-default:"8"`
+	BaseBackoff   time.Duration `yaml:"base_backoff" env-default:"30s"`
+	MaxBackoff    time.Duration `yaml:"max_backoff" env-default:"1h"`
+	LeaseDuration time.Duration `yaml:"lease_duration" env-default:"2m"`
+}
+
+type Uploads struct {
+	MaxImageBytes int64         `yaml:"max_image_bytes" env-default:"10485760"`
+	PendingTTL    time.Duration `yaml:"pending_ttl" env-default:"24h"`
+	GCInterval    time.Duration `yaml:"gc_interval" env-default:"1h"`
+	GCBatchSize   int           `yaml:"gc_batch_size" env-default:"100"`
+}
+
+type ImageProcessing struct {
+	PollInterval  time.Duration `yaml:"poll_interval" env-default:"10s"`
+	BatchSize     int           `yaml:"batch_size" env-default:"5"`
+	MaxAttempts   int           `yaml:"max_attempts" env-default:"3"`
+	RetryDelay    time.Duration `yaml:"retry_delay" env-default:"1m"`
+	LeaseDuration time.Duration `yaml:"lease_duration" env-default:"5m"`
+	JPEGQuality   int           `yaml:"jpeg_quality" env-default:"82"`
+}
+
+type Availability struct {
+	BookingBuffer   time.Duration `yaml:"booking_buffer" env-default:"0s"`
+	SlotGranularity time.Duration `yaml:"slot_granularity" env-default:"1h"`
+	MaxRange        time.Duration `yaml:"max_range" env-default:"2160h"`
+}
+
+// Scheduler runs the periodic booking jobs. The lease must outlast a job run; a replica that
+// stops renewing it hands its jobs over to another replica once it expires.
+type Scheduler struct {
+	PollInterval       time.Duration `yaml:"poll_interval" env-default:"1m"`
+	LeaseDuration      time.Duration `yaml:"lease_duration" env-default:"5m"`
+	BatchSize          int           `yaml:"batch_size" env-default:"50"`
+	NoShowGracePeriod  time.Duration `yaml:"no_show_grace_period" env-default:"2h"`
+	CancelNoShows      bool          `yaml:"cancel_no_shows" env-default:"true"`
+	OverdueGracePeriod time.Duration `yaml:"overdue_grace_period" env-default:"15m"`
+	// How long before the scheduled pickup and dropoff seekers are reminded.
+	PickupReminders  []time.Duration `yaml:"pickup_reminders" env-default:"24h,1h"`
+	DropoffReminders []time.Duration `yaml:"dropoff_reminders" env-default:"1h"`
+}
+
+// Otp secures the pickup and return OTPs, which are stored as an HMAC keyed with secret.
+// Changing the secret invalidates every outstanding OTP.
+type Otp struct {
+	Secret         string        `yaml:"secret"`
+	MaxAttempts    int           `yaml:"max_attempts" env-default:"5"`
+	ResendCooldown time.Duration `yaml:"resend_cooldown" env-default:"1m"`
+	// How many times an OTP can be replaced, which bounds the guesses at
+	// (MaxResends+1)*MaxAttempts per booking and purpose.
+	MaxResends int `yaml:"max_resends" env-default:"3"`
+}
+
+type Geocoding struct {
+	Driver  string        `yaml:"driver" env-default:"offline"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+	// How long vehicle create and update requests wait for a location, including the wait
+	// for a turn under the request rate limit. The geocode-vehicles command is not bound by it.
+	RequestTimeout time.Duration      `yaml:"request_timeout" env-default:"2s"`
+	Nominatim      NominatimGeocoding `yaml:"nominatim"`
+}
+
+type NominatimGeocoding struct {
+	BaseURL      string `yaml:"base_url" env-default:"https://nominatim.openstreetmap.org"`
+	UserAgent    string `yaml:"user_agent" env-default:"Wheelio-Backend"`
+	CountryCodes string `yaml:"country_codes" env-default:"in"`
+}
+
+type StorageService struct {
+	Driver           string          `yaml:"driver" env-default:"firebase"`
+	PublicBaseURL    string          `yaml:"public_base_url"`
+	SignedAccessURLs bool            `yaml:"signed_access_urls"`
+	AccessURLExpiry  time.Duration   `yaml:"access_url_expiry" env-default:"1h"`
+	Firebase         FirebaseStorage `yaml:"firebase"`
+	S3               S3Storage       `yaml:"s3"`
+	Local            LocalStorage    `yaml:"local"`
+}
+
+type FirebaseStorage struct {
+	BucketName      string `yaml:"bucket_name"`
+	CredentialsFile string `yaml:"credentials_file"`
+}
+
+type S3Storage struct {
+	Endpoint        string `yaml:"endpoint" env-default:"https://s3.amazonaws.com"`
+	Region          string `yaml:"region" env-default:"us-east-1"`
+	Bucket          string `yaml:"bucket"`
+	AccessKeyId     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	UsePathStyle    bool   `yaml:"use_path_style"`
+}
+
+type LocalStorage struct {
+	Directory      string `yaml:"directory" env-default:"./tmp/storage"`
+	PublicBaseURL  string `yaml:"public_base_url" env-default:"http://localhost:8080"`
+	SigningSecret  string `yaml:"signing_secret"`
+	MaxUploadBytes int64  `yaml:"max_upload_bytes" env-default:"10485760"`
 }
 
 type Config struct {
-	HTTPServer      HTTPServer      `yaml:"http_server"`
-	Database        Database        `yaml:"database"`
-	EmailService    EmailService    `yaml:"email_service"`
-	JWTSecret       string          `yaml:"jwt_secret"`
-	ClientURL       string          `yaml:"client_url"`
-	FirebaseService FirebaseService `yaml:"firebase_service"`
+	HTTPServer     HTTPServer     `yaml:"http_server"`
+	Database       Database       `yaml:"database"`
+	EmailService   EmailService   `yaml:"email_service"`
+	Outbox         Outbox         `yaml:"outbox"`
+	JWTSecret      string         `yaml:"jwt_secret"`
+	ClientURL      string         `yaml:"client_url"`
+	StorageService StorageService `yaml:"storage_service"`
+	// Deprecated: FirebaseService is the pre-storage_service location of the Firebase
+	// settings. It is only read as a fallback for storage_service.firebase.
+	FirebaseService FirebaseStorage `yaml:"firebase_service"`
+	Uploads         Uploads         `yaml:"uploads"`
+	ImageProcessing ImageProcessing `yaml:"image_processing"`
+	Availability    Availability    `yaml:"availability"`
+	Geocoding       Geocoding       `yaml:"geocoding"`
+	Scheduler       Scheduler       `yaml:"scheduler"`
+	Otp             Otp             `yaml:"otp"`
 }
 
 var cfg Config
@@ -56,9 +181,22 @@ func MustLoad() (Config, error) {
 		return Config{}, err
 	}
 
+	applyDeprecatedFirebaseService(&cfg)
+
 	return cfg, nil
 }
 
+func applyDeprecatedFirebaseService(cfg *Config) {
+	if cfg.FirebaseService == (FirebaseStorage{}) {
+		return
+	}
+
+	slog.Warn("firebase_service is deprecated, move it to storage_service.firebase")
+	if cfg.StorageService.Firebase == (FirebaseStorage{}) {
+		cfg.StorageService.Firebase = cfg.FirebaseService
+	}
+}
+
 func GetConfig() Config {
 	return cfg
 }
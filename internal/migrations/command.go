@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+const commandUsage = "usage: migrate [up | down [steps] | status]"
+
+func RunCommand(ctx context.Context, db *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(commandUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		return Up(ctx, db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid number of steps: %w", err)
+			}
+		}
+		return Down(ctx, db, steps)
+	case "status":
+		statuses, err := Status(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				slog.Info("migration applied", "version", status.Version, "name", status.Name, "appliedAt", status.AppliedAt)
+			} else {
+				slog.Info("migration pending", "version", status.Version, "name", status.Name)
+			}
+		}
+		return nil
+	default:
+		return errors.New(commandUsage)
+	}
+}
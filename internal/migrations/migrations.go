@@ -0,0 +1,282 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+// Arbitrary key used with pg_advisory_lock so that only one process migrates at a time.
+const migrationLockKey = 727001
+
+const (
+	createSchemaMigrationsTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	getAppliedMigrationsQuery = "SELECT version, applied_at FROM schema_migrations ORDER BY version"
+
+	insertSchemaMigrationQuery = "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)"
+
+	deleteSchemaMigrationQuery = "DELETE FROM schema_migrations WHERE version=$1"
+
+	acquireMigrationLockQuery = "SELECT pg_advisory_lock($1)"
+
+	releaseMigrationLockQuery = "SELECT pg_advisory_unlock($1)"
+)
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsByVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrationsByVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrationsByVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("conflicting names for migration version %d", version)
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func Up(ctx context.Context, db *sql.DB) (err error) {
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+
+	conn, applied, err := prepare(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer release(ctx, conn)
+
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		slog.Info("applying migration", "version", migration.Version, "name", migration.Name)
+		err = apply(ctx, conn, migration.Up, insertSchemaMigrationQuery, migration.Version, migration.Name)
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func Down(ctx context.Context, db *sql.DB, steps int) (err error) {
+	if steps <= 0 {
+		return errors.New("number of migrations to roll back must be positive")
+	}
+
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+
+	conn, applied, err := prepare(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer release(ctx, conn)
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		slog.Info("rolling back migration", "version", migration.Version, "name", migration.Name)
+		err = apply(ctx, conn, migration.Down, deleteSchemaMigrationQuery, migration.Version)
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		steps--
+	}
+
+	return nil
+}
+
+func Status(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, applied, err := prepare(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer release(ctx, conn)
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+func prepare(ctx context.Context, db *sql.DB) (*sql.Conn, map[int]time.Time, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = conn.ExecContext(ctx, acquireMigrationLockQuery, migrationLockKey)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	_, err = conn.ExecContext(ctx, createSchemaMigrationsTableQuery)
+	if err != nil {
+		release(ctx, conn)
+		return nil, nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := getAppliedMigrations(ctx, conn)
+	if err != nil {
+		release(ctx, conn)
+		return nil, nil, err
+	}
+
+	return conn, applied, nil
+}
+
+func getAppliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, getAppliedMigrationsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func release(ctx context.Context, conn *sql.Conn) {
+	_, err := conn.ExecContext(ctx, releaseMigrationLockQuery, migrationLockKey)
+	if err != nil {
+		slog.Warn("failed to release migration lock", "error", err)
+	}
+	conn.Close()
+}
+
+func apply(ctx context.Context, conn *sql.Conn, script, bookkeepingQuery string, args ...any) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.Error("failed to roll back migration transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, bookkeepingQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS otp_tokens;
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS vehicle_images;
+DROP TABLE IF EXISTS vehicles;
+DROP TABLE IF EXISTS verification_tokens;
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS set_updated_at();
@@ -0,0 +1,118 @@
+CREATE OR REPLACE FUNCTION set_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+	NEW.updated_at = CURRENT_TIMESTAMP;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE users (
+	id           SERIAL PRIMARY KEY,
+	name         VARCHAR(255) NOT NULL,
+	email        VARCHAR(255) NOT NULL UNIQUE,
+	phone_number VARCHAR(20) NOT NULL,
+	password     TEXT NOT NULL,
+	role         VARCHAR(20) NOT NULL CHECK (role IN ('HOST', 'SEEKER')),
+	is_verified  BOOLEAN NOT NULL DEFAULT false,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER users_set_updated_at
+BEFORE UPDATE ON users
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE verification_tokens (
+	id         SERIAL PRIMARY KEY,
+	user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	token      TEXT NOT NULL UNIQUE,
+	type       VARCHAR(30) NOT NULL CHECK (type IN ('EMAIL_VERIFICATION', 'PASSWORD_RESET')),
+	expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX verification_tokens_user_id_idx ON verification_tokens (user_id);
+
+CREATE TABLE vehicles (
+	id                        SERIAL PRIMARY KEY,
+	name                      VARCHAR(255) NOT NULL,
+	fuel_type                 VARCHAR(20) NOT NULL,
+	seat_count                INTEGER NOT NULL CHECK (seat_count > 0),
+	transmission_type         VARCHAR(20) NOT NULL,
+	features                  JSONB,
+	rate_per_hour             NUMERIC(10, 2) NOT NULL CHECK (rate_per_hour >= 0),
+	overdue_fee_rate_per_hour NUMERIC(10, 2) NOT NULL CHECK (overdue_fee_rate_per_hour >= 0),
+	address                   TEXT NOT NULL,
+	state                     VARCHAR(100) NOT NULL,
+	city                      VARCHAR(100) NOT NULL,
+	pin_code                  INTEGER NOT NULL,
+	cancellation_allowed      BOOLEAN NOT NULL DEFAULT false,
+	available                 BOOLEAN NOT NULL DEFAULT true,
+	host_id                   INTEGER NOT NULL REFERENCES users (id),
+	is_deleted                BOOLEAN NOT NULL DEFAULT false,
+	created_at                TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at                TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX vehicles_host_id_idx ON vehicles (host_id);
+CREATE INDEX vehicles_city_idx ON vehicles (lower(city)) WHERE is_deleted = false;
+
+CREATE TRIGGER vehicles_set_updated_at
+BEFORE UPDATE ON vehicles
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE vehicle_images (
+	id         SERIAL PRIMARY KEY,
+	vehicle_id INTEGER NOT NULL REFERENCES vehicles (id) ON DELETE CASCADE,
+	url        TEXT NOT NULL,
+	featured   BOOLEAN NOT NULL DEFAULT false,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX vehicle_images_vehicle_id_idx ON vehicle_images (vehicle_id);
+
+CREATE TABLE bookings (
+	id                        SERIAL PRIMARY KEY,
+	vehicle_id                INTEGER NOT NULL REFERENCES vehicles (id),
+	host_id                   INTEGER NOT NULL REFERENCES users (id),
+	seeker_id                 INTEGER NOT NULL REFERENCES users (id),
+	status                    VARCHAR(20) NOT NULL CHECK (status IN ('SCHEDULED', 'CHECKED_OUT', 'RETURNED', 'CANCELLED')),
+	pickup_location           TEXT NOT NULL,
+	dropoff_location          TEXT NOT NULL,
+	booking_amount            NUMERIC(12, 2) NOT NULL,
+	overdue_fee_rate_per_hour NUMERIC(10, 2) NOT NULL,
+	cancellation_allowed      BOOLEAN NOT NULL DEFAULT false,
+	actual_pickup_time        TIMESTAMPTZ,
+	actual_dropoff_time       TIMESTAMPTZ,
+	scheduled_pickup_time     TIMESTAMPTZ NOT NULL,
+	scheduled_dropoff_time    TIMESTAMPTZ NOT NULL,
+	created_at                TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at                TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK (scheduled_pickup_time <= scheduled_dropoff_time)
+);
+
+CREATE INDEX bookings_vehicle_id_idx ON bookings (vehicle_id);
+CREATE INDEX bookings_host_id_idx ON bookings (host_id);
+CREATE INDEX bookings_seeker_id_idx ON bookings (seeker_id);
+
+CREATE TRIGGER bookings_set_updated_at
+BEFORE UPDATE ON bookings
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE otp_tokens (
+	id         SERIAL PRIMARY KEY,
+	booking_id INTEGER NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+	otp        VARCHAR(6) NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX otp_tokens_otp_idx ON otp_tokens (otp);
+
+CREATE TABLE invoices (
+	id              SERIAL PRIMARY KEY,
+	booking_id      INTEGER NOT NULL UNIQUE REFERENCES bookings (id) ON DELETE CASCADE,
+	booking_amount  NUMERIC(12, 2) NOT NULL,
+	additional_fees NUMERIC(12, 2) NOT NULL DEFAULT 0,
+	tax             NUMERIC(12, 2) NOT NULL DEFAULT 0,
+	tax_rate        NUMERIC(5, 4) NOT NULL DEFAULT 0,
+	total_amount    NUMERIC(12, 2) NOT NULL
+);
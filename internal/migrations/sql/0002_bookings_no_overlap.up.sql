@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Active bookings of a vehicle must never overlap. Bounds are inclusive to match
+-- the overlap semantics of the booking conflict check.
+ALTER TABLE bookings
+ADD CONSTRAINT bookings_no_overlap
+EXCLUDE USING gist (
+	vehicle_id WITH =,
+	tstzrange(scheduled_pickup_time, scheduled_dropoff_time, '[]') WITH &&
+) WHERE (status IN ('SCHEDULED', 'CHECKED_OUT'));
@@ -0,0 +1,16 @@
+CREATE TABLE booking_status_history (
+	id          SERIAL PRIMARY KEY,
+	booking_id  INTEGER NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+	from_status VARCHAR(20),
+	to_status   VARCHAR(20) NOT NULL,
+	actor_id    INTEGER REFERENCES users (id),
+	actor_role  VARCHAR(20) NOT NULL CHECK (actor_role IN ('HOST', 'SEEKER', 'SYSTEM')),
+	reason      TEXT NOT NULL DEFAULT '',
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_status_history_booking_id_idx ON booking_status_history (booking_id, created_at);
+
+INSERT INTO booking_status_history (booking_id, from_status, to_status, actor_role, reason, created_at)
+SELECT id, NULL, status, 'SYSTEM', 'recorded when status history was introduced', updated_at
+FROM bookings;
@@ -0,0 +1,13 @@
+CREATE TABLE user_sessions (
+	id                 UUID PRIMARY KEY,
+	user_id            INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	refresh_token_hash TEXT NOT NULL,
+	user_agent         TEXT NOT NULL DEFAULT '',
+	ip_address         TEXT NOT NULL DEFAULT '',
+	expires_at         TIMESTAMPTZ NOT NULL,
+	revoked_at         TIMESTAMPTZ,
+	last_used_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX user_sessions_user_id_idx ON user_sessions (user_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS email_outbox;
+
+UPDATE users SET role = 'SEEKER' WHERE role = 'ADMIN';
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('HOST', 'SEEKER'));
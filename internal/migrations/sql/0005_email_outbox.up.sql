@@ -0,0 +1,25 @@
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('HOST', 'SEEKER', 'ADMIN'));
+
+CREATE TABLE email_outbox (
+	id                 SERIAL PRIMARY KEY,
+	to_name            TEXT NOT NULL,
+	to_email           TEXT NOT NULL,
+	subject            TEXT NOT NULL,
+	plain_text_content TEXT NOT NULL,
+	status             VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'DEAD')),
+	attempts           INTEGER NOT NULL DEFAULT 0,
+	max_attempts       INTEGER NOT NULL,
+	next_attempt_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_error         TEXT NOT NULL DEFAULT '',
+	sent_at            TIMESTAMPTZ,
+	created_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX email_outbox_due_idx ON email_outbox (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX email_outbox_status_idx ON email_outbox (status, created_at);
+
+CREATE TRIGGER email_outbox_set_updated_at
+BEFORE UPDATE ON email_outbox
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,3 @@
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS html_content;
+
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_language;
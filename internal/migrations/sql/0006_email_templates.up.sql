@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN preferred_language VARCHAR(10) NOT NULL DEFAULT 'en';
+
+ALTER TABLE email_outbox ADD COLUMN html_content TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE vehicle_images DROP COLUMN IF EXISTS object_path;
@@ -0,0 +1,20 @@
+ALTER TABLE vehicle_images ADD COLUMN object_path TEXT;
+
+-- Images uploaded so far point at Firebase download URLs, whose last path segment is the escaped object path.
+-- Every percent escape is decoded byte by byte so that multi-byte UTF-8 names come out whole.
+UPDATE vehicle_images
+SET object_path = (
+	SELECT convert_from(string_agg(
+		CASE
+			WHEN token[1] ~ '^%[0-9A-Fa-f]{2}$' THEN decode(substr(token[1], 2), 'hex')
+			ELSE convert_to(token[1], 'UTF8')
+		END,
+		''::bytea ORDER BY ordinal
+	), 'UTF8')
+	FROM regexp_matches(
+		substring(url FROM '^https://firebasestorage\.googleapis\.com/v0/b/[^/]+/o/([^?]+)'),
+		'%[0-9A-Fa-f]{2}|[^%]+|%',
+		'g'
+	) WITH ORDINALITY AS tokens(token, ordinal)
+)
+WHERE url ~ '^https://firebasestorage\.googleapis\.com/v0/b/[^/]+/o/';
@@ -0,0 +1,3 @@
+ALTER TABLE vehicle_images DROP COLUMN IF EXISTS upload_id;
+
+DROP TABLE IF EXISTS uploads;
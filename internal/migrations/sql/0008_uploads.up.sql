@@ -0,0 +1,16 @@
+CREATE TABLE uploads (
+	id           SERIAL PRIMARY KEY,
+	user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	object_path  TEXT NOT NULL UNIQUE,
+	content_type VARCHAR(100) NOT NULL,
+	status       VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'LINKED')),
+	expires_at   TIMESTAMPTZ NOT NULL,
+	linked_at    TIMESTAMPTZ,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX uploads_pending_expires_at_idx ON uploads (expires_at) WHERE status = 'PENDING';
+
+ALTER TABLE vehicle_images ADD COLUMN upload_id INTEGER REFERENCES uploads (id) ON DELETE SET NULL;
+
+CREATE INDEX vehicle_images_upload_id_idx ON vehicle_images (upload_id);
@@ -0,0 +1,8 @@
+ALTER TABLE vehicle_images
+	DROP COLUMN IF EXISTS thumbnail_path,
+	DROP COLUMN IF EXISTS medium_path,
+	DROP COLUMN IF EXISTS large_path,
+	DROP COLUMN IF EXISTS processing_status,
+	DROP COLUMN IF EXISTS processing_attempts,
+	DROP COLUMN IF EXISTS processing_next_attempt_at,
+	DROP COLUMN IF EXISTS processing_error;
@@ -0,0 +1,11 @@
+ALTER TABLE vehicle_images
+	ADD COLUMN thumbnail_path TEXT,
+	ADD COLUMN medium_path TEXT,
+	ADD COLUMN large_path TEXT,
+	ADD COLUMN processing_status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (processing_status IN ('PENDING', 'PROCESSED', 'FAILED')),
+	ADD COLUMN processing_attempts INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN processing_next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	ADD COLUMN processing_error TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX vehicle_images_processing_idx ON vehicle_images (processing_next_attempt_at)
+WHERE processing_status = 'PENDING' AND object_path IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS vehicle_images_one_featured_idx;
+
+ALTER TABLE vehicle_images DROP CONSTRAINT IF EXISTS vehicle_images_vehicle_id_position_key;
+
+ALTER TABLE vehicle_images DROP COLUMN IF EXISTS position;
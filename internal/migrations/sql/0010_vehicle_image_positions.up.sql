@@ -0,0 +1,20 @@
+ALTER TABLE vehicle_images ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+WITH ordered AS (
+	SELECT id, ROW_NUMBER() OVER (PARTITION BY vehicle_id ORDER BY featured DESC, id) - 1 AS position
+	FROM vehicle_images
+)
+UPDATE vehicle_images vi
+SET position = ordered.position
+FROM ordered
+WHERE vi.id = ordered.id;
+
+-- Featured images were ordered first, so this keeps one featured image per vehicle and
+-- promotes the first image of vehicles that had none.
+UPDATE vehicle_images SET featured = (position = 0);
+
+-- Reordering swaps positions, so uniqueness is only checked when the transaction commits.
+ALTER TABLE vehicle_images
+ADD CONSTRAINT vehicle_images_vehicle_id_position_key UNIQUE (vehicle_id, position) DEFERRABLE INITIALLY DEFERRED;
+
+CREATE UNIQUE INDEX vehicle_images_one_featured_idx ON vehicle_images (vehicle_id) WHERE featured;
@@ -0,0 +1,11 @@
+CREATE TABLE vehicle_blackouts (
+	id         SERIAL PRIMARY KEY,
+	vehicle_id INTEGER NOT NULL REFERENCES vehicles (id) ON DELETE CASCADE,
+	starts_at  TIMESTAMPTZ NOT NULL,
+	ends_at    TIMESTAMPTZ NOT NULL,
+	reason     TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK (starts_at < ends_at)
+);
+
+CREATE INDEX vehicle_blackouts_vehicle_id_ends_at_idx ON vehicle_blackouts (vehicle_id, ends_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS vehicles_coordinates_idx;
+
+ALTER TABLE vehicles
+DROP CONSTRAINT IF EXISTS vehicles_coordinates_pair,
+DROP COLUMN IF EXISTS latitude,
+DROP COLUMN IF EXISTS longitude;
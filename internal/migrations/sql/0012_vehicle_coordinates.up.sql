@@ -0,0 +1,7 @@
+ALTER TABLE vehicles
+ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+ADD CONSTRAINT vehicles_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- Radius searches narrow candidates with a bounding box on these columns before computing distances.
+CREATE INDEX vehicles_coordinates_idx ON vehicles (latitude, longitude) WHERE is_deleted = false AND latitude IS NOT NULL;
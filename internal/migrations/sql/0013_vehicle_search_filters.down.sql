@@ -0,0 +1 @@
+DROP INDEX IF EXISTS vehicles_features_idx;
@@ -0,0 +1 @@
+CREATE INDEX vehicles_features_idx ON vehicles USING GIN (features);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS vehicles_lower_city_idx;
+DROP INDEX IF EXISTS vehicles_lower_name_idx;
+DROP INDEX IF EXISTS vehicles_search_vector_idx;
+
+ALTER TABLE vehicles DROP COLUMN IF EXISTS search_vector;
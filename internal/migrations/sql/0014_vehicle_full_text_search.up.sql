@@ -0,0 +1,13 @@
+-- The simple configuration skips stemming, which keeps prefix matches on vehicle names and
+-- cities predictable. Feature lists contribute their names, feature objects their keys.
+ALTER TABLE vehicles
+ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+	setweight(jsonb_to_tsvector('simple', coalesce(features, '{}'::jsonb), '["string", "key"]'), 'B') ||
+	setweight(to_tsvector('simple', coalesce(city, '') || ' ' || coalesce(address, '')), 'C')
+) STORED;
+
+CREATE INDEX vehicles_search_vector_idx ON vehicles USING GIN (search_vector);
+
+CREATE INDEX vehicles_lower_name_idx ON vehicles (lower(name) text_pattern_ops) WHERE is_deleted = false;
+CREATE INDEX vehicles_lower_city_idx ON vehicles (lower(city) text_pattern_ops) WHERE is_deleted = false;
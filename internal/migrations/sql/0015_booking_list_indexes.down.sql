@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS bookings_host_pickup_idx;
+DROP INDEX IF EXISTS bookings_seeker_pickup_idx;
+DROP INDEX IF EXISTS bookings_host_created_at_idx;
+DROP INDEX IF EXISTS bookings_seeker_created_at_idx;
@@ -0,0 +1,6 @@
+-- Booking lists are always scoped to a seeker or a host and are either ordered by creation or
+-- filtered and ordered by the scheduled times.
+CREATE INDEX bookings_seeker_created_at_idx ON bookings (seeker_id, created_at, id);
+CREATE INDEX bookings_host_created_at_idx ON bookings (host_id, created_at, id);
+CREATE INDEX bookings_seeker_pickup_idx ON bookings (seeker_id, scheduled_pickup_time);
+CREATE INDEX bookings_host_pickup_idx ON bookings (host_id, scheduled_pickup_time);
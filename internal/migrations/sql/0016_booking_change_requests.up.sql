@@ -0,0 +1,25 @@
+-- Extensions wait for the host to decide on them; reschedules are applied right away and are
+-- stored as already approved. Both keep the schedule and amount from before and after.
+CREATE TABLE booking_change_requests (
+	id                    SERIAL PRIMARY KEY,
+	booking_id            INTEGER NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+	type                  VARCHAR(20) NOT NULL CHECK (type IN ('EXTENSION', 'RESCHEDULE')),
+	status                VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'DECLINED')),
+	previous_pickup_time  TIMESTAMPTZ NOT NULL,
+	previous_dropoff_time TIMESTAMPTZ NOT NULL,
+	previous_amount       NUMERIC(12, 2) NOT NULL,
+	new_pickup_time       TIMESTAMPTZ NOT NULL,
+	new_dropoff_time      TIMESTAMPTZ NOT NULL,
+	new_amount            NUMERIC(12, 2) NOT NULL,
+	requested_by          INTEGER NOT NULL REFERENCES users (id),
+	decided_by            INTEGER REFERENCES users (id),
+	reason                TEXT NOT NULL DEFAULT '',
+	created_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	decided_at            TIMESTAMPTZ,
+	CHECK (new_pickup_time < new_dropoff_time)
+);
+
+CREATE INDEX booking_change_requests_booking_id_idx ON booking_change_requests (booking_id, created_at);
+
+-- A booking has at most one change waiting for the host.
+CREATE UNIQUE INDEX booking_change_requests_pending_idx ON booking_change_requests (booking_id) WHERE status = 'PENDING';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS booking_cancellations;
+ALTER TABLE bookings DROP COLUMN IF EXISTS cancellation_policy;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS cancellation_policy;
@@ -0,0 +1,25 @@
+ALTER TABLE vehicles
+ADD COLUMN cancellation_policy VARCHAR(20) NOT NULL DEFAULT 'FLEXIBLE' CHECK (cancellation_policy IN ('FLEXIBLE', 'MODERATE', 'STRICT'));
+
+-- Like cancellation_allowed, the policy is copied onto the booking so that later changes to
+-- the vehicle do not affect bookings that already exist.
+ALTER TABLE bookings
+ADD COLUMN cancellation_policy VARCHAR(20) NOT NULL DEFAULT 'FLEXIBLE' CHECK (cancellation_policy IN ('FLEXIBLE', 'MODERATE', 'STRICT'));
+
+CREATE TABLE booking_cancellations (
+	id                  SERIAL PRIMARY KEY,
+	booking_id          INTEGER NOT NULL UNIQUE REFERENCES bookings (id) ON DELETE CASCADE,
+	host_id             INTEGER NOT NULL REFERENCES users (id),
+	cancelled_by        INTEGER REFERENCES users (id),
+	initiated_by        VARCHAR(20) NOT NULL CHECK (initiated_by IN ('HOST', 'SEEKER', 'SYSTEM')),
+	policy              VARCHAR(20) NOT NULL,
+	hours_before_pickup NUMERIC(10, 2) NOT NULL,
+	booking_amount      NUMERIC(12, 2) NOT NULL,
+	refund_percent      INTEGER NOT NULL CHECK (refund_percent BETWEEN 0 AND 100),
+	refund_amount       NUMERIC(12, 2) NOT NULL,
+	cancellation_fee    NUMERIC(12, 2) NOT NULL,
+	created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Host-initiated cancellations are looked up per host to decide on penalties.
+CREATE INDEX booking_cancellations_host_idx ON booking_cancellations (host_id, created_at, id) WHERE initiated_by = 'HOST';
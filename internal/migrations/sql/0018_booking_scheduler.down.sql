@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS bookings_overdue_candidates_idx;
+DROP INDEX IF EXISTS bookings_no_show_candidates_idx;
+ALTER TABLE bookings DROP COLUMN IF EXISTS overdue_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS no_show_at;
+DROP TABLE IF EXISTS job_leases;
@@ -0,0 +1,14 @@
+-- A lease lets one server replica at a time run a scheduled job. The holder renews it on
+-- every run; other replicas take over once it has expired.
+CREATE TABLE job_leases (
+	name         VARCHAR(100) PRIMARY KEY,
+	owner        VARCHAR(255) NOT NULL,
+	leased_until TIMESTAMPTZ NOT NULL
+);
+
+-- Set once by the scheduler, so that every booking is handled and notified about only once.
+ALTER TABLE bookings ADD COLUMN no_show_at TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN overdue_at TIMESTAMPTZ;
+
+CREATE INDEX bookings_no_show_candidates_idx ON bookings (scheduled_pickup_time) WHERE status = 'SCHEDULED' AND no_show_at IS NULL;
+CREATE INDEX bookings_overdue_candidates_idx ON bookings (scheduled_dropoff_time) WHERE status = 'CHECKED_OUT' AND overdue_at IS NULL;
@@ -0,0 +1,14 @@
+-- Reminders are created with the booking and moved with every schedule change. Reminders that
+-- no longer apply are cancelled rather than deleted, so the history of what was sent is kept.
+CREATE TABLE booking_reminders (
+	id         SERIAL PRIMARY KEY,
+	booking_id INTEGER NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+	kind       VARCHAR(20) NOT NULL CHECK (kind IN ('PICKUP', 'DROPOFF')),
+	remind_at  TIMESTAMPTZ NOT NULL,
+	status     VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'CANCELLED')),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_reminders_booking_id_idx ON booking_reminders (booking_id) WHERE status = 'PENDING';
+CREATE INDEX booking_reminders_due_idx ON booking_reminders (remind_at) WHERE status = 'PENDING';
@@ -0,0 +1,9 @@
+DELETE FROM otp_tokens;
+DROP INDEX IF EXISTS otp_tokens_booking_purpose_idx;
+ALTER TABLE otp_tokens DROP COLUMN IF EXISTS created_at;
+ALTER TABLE otp_tokens DROP COLUMN IF EXISTS resends;
+ALTER TABLE otp_tokens DROP COLUMN IF EXISTS attempts;
+ALTER TABLE otp_tokens DROP COLUMN IF EXISTS otp_hash;
+ALTER TABLE otp_tokens DROP COLUMN IF EXISTS purpose;
+ALTER TABLE otp_tokens ADD COLUMN otp VARCHAR(6) NOT NULL;
+CREATE INDEX otp_tokens_otp_idx ON otp_tokens (otp);
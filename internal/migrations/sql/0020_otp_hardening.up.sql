@@ -0,0 +1,16 @@
+-- Plain text OTPs cannot be hashed here, as the key only exists in the server configuration.
+-- Outstanding OTPs are dropped instead; participants can get new ones with the resend endpoint.
+DELETE FROM otp_tokens;
+
+DROP INDEX otp_tokens_otp_idx;
+ALTER TABLE otp_tokens DROP COLUMN otp;
+
+ALTER TABLE otp_tokens
+ADD COLUMN purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('PICKUP', 'RETURN')),
+ADD COLUMN otp_hash VARCHAR(64) NOT NULL,
+ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN resends INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- A booking has one OTP per purpose; issuing a new one replaces it.
+CREATE UNIQUE INDEX otp_tokens_booking_purpose_idx ON otp_tokens (booking_id, purpose);
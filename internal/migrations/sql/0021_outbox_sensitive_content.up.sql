@@ -0,0 +1,2 @@
+-- Sensitive emails, such as OTPs, have their content cleared once they are delivered.
+ALTER TABLE email_outbox ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT false;
@@ -24,35 +24,67 @@ var (
 	ErrJWTCreationFailed   = errors.New("failed to create jwt token")
 	ErrTokenCreationFailed = errors.New("failed to create verification token")
 
-	ErrEmailSendFailed = errors.New("failed to send email")
+	ErrEmailSendFailed       = errors.New("failed to send email")
+	ErrEmailTemplateNotFound = errors.New("email template not found")
 
-	ErrInvalidImageToLink   = errors.New("no image found to link")
-	ErrVehicleNotFound      = errors.New("vehicle not found")
-	ErrInvalidPickupDropoff = errors.New("pickup timestamp cannot be after dropoff timestamp")
-	ErrInvalidPagination    = errors.New("page and limit must be greater than zero")
+	ErrObjectNotFound           = errors.New("storage object not found")
+	ErrInvalidSignedURL         = errors.New("invalid or expired signed url")
+	ErrObjectTooLarge           = errors.New("storage object exceeds the maximum allowed size")
+	ErrInvalidImageToLink       = errors.New("no image found to link")
+	ErrUnsupportedImageType     = errors.New("image must be a jpeg, png or webp file")
+	ErrVehicleNotFound          = errors.New("vehicle not found")
+	ErrVehicleImageNotFound     = errors.New("vehicle image not found")
+	ErrVehicleImageLimit        = errors.New("vehicle already has the maximum number of images")
+	ErrLastVehicleImage         = errors.New("a vehicle must keep at least one image")
+	ErrVehicleImagesNotEditable = errors.New("images cannot be changed when updating a vehicle, use the /vehicles/{id}/images endpoints")
+	ErrVehicleBlackoutNotFound  = errors.New("vehicle blackout not found")
+	ErrBlackoutBookingConflict  = errors.New("blackout overlaps an existing booking")
+	ErrVehicleUnavailable       = errors.New("vehicle is currently not accepting bookings")
+	ErrInvalidPickupDropoff     = errors.New("pickup timestamp cannot be after dropoff timestamp")
+	ErrInvalidPagination        = errors.New("page must be greater than zero and limit between 1 and 100")
+	ErrInvalidCursor            = errors.New("pagination cursor is invalid")
+	ErrLocationNotFound         = errors.New("location could not be found")
+	ErrInvalidAvailabilityRange = errors.New("availability range is invalid or too long")
 
 	ErrBookingConflict               = errors.New("booking slot is not available for the selected time range")
 	ErrInvalidOtp                    = errors.New("invalid opt")
 	ErrOptTokenNotFound              = errors.New("opt token not found")
+	ErrOtpLocked                     = errors.New("too many failed otp attempts, request a new otp")
+	ErrOtpResendCooldown             = errors.New("otp was sent recently, try again later")
+	ErrOtpResendLimit                = errors.New("no more otps can be sent for this booking")
 	ErrBookingNotFound               = errors.New("booking not found")
 	ErrBookingCancelled              = errors.New("cannot perform operations on cancelled booking")
 	ErrBookingCancellationNotAllowed = errors.New("cancellation is not allowed for this booking")
+	ErrBookingCancellationNotFound   = errors.New("booking has not been cancelled")
+	ErrInvalidBookingTransition      = errors.New("booking status change is not allowed")
+	ErrBookingNotChangeable          = errors.New("booking schedule can no longer be changed")
+	ErrBookingChangeNotFound         = errors.New("booking change request not found")
+	ErrBookingChangePending          = errors.New("booking already has a pending change request")
+	ErrBookingChangeDecided          = errors.New("booking change request has already been decided")
+
+	ErrOutboxEmailNotFound = errors.New("no dead outbox email found with the given id")
 )
 
 func MapError(err error) (statusCode int, errMessage string) {
 	switch err {
-	case ErrInvalidRequestBody, ErrInvalidQueryParams, ErrInvalidPickupDropoff, ErrInvalidPagination, ErrOptTokenNotFound, ErrBookingNotFound:
+	case ErrInvalidRequestBody, ErrInvalidQueryParams, ErrInvalidPickupDropoff, ErrInvalidPagination, ErrInvalidCursor, ErrInvalidAvailabilityRange, ErrOptTokenNotFound, ErrBookingNotFound, ErrInvalidImageToLink, ErrVehicleImagesNotEditable:
 		return http.StatusBadRequest, err.Error()
 	case ErrUnauthorizedAccess:
 		return http.StatusUnauthorized, err.Error()
-	case ErrAccessForbidden, ErrActionForbidden, ErrBookingCancellationNotAllowed:
+	case ErrAccessForbidden, ErrActionForbidden, ErrBookingCancellationNotAllowed, ErrInvalidSignedURL:
 		return http.StatusForbidden, err.Error()
-	case ErrUserNotFound, ErrVehicleNotFound:
+	case ErrUserNotFound, ErrVehicleNotFound, ErrVehicleImageNotFound, ErrVehicleBlackoutNotFound, ErrOutboxEmailNotFound, ErrEmailTemplateNotFound, ErrObjectNotFound, ErrBookingChangeNotFound, ErrBookingCancellationNotFound:
 		return http.StatusNotFound, err.Error()
-	case ErrEmailAlreadyRegistered, ErrUserNotVerified, ErrBookingConflict, ErrInvalidOtp, ErrBookingCancelled:
+	case ErrEmailAlreadyRegistered, ErrUserNotVerified, ErrBookingConflict, ErrInvalidOtp, ErrBookingCancelled, ErrInvalidBookingTransition, ErrVehicleImageLimit, ErrLastVehicleImage, ErrBlackoutBookingConflict, ErrVehicleUnavailable, ErrBookingNotChangeable, ErrBookingChangePending, ErrBookingChangeDecided:
 		return http.StatusConflict, err.Error()
 	case ErrInvalidToken, ErrInvalidLoginCredentials:
 		return http.StatusUnprocessableEntity, err.Error()
+	case ErrOtpLocked, ErrOtpResendCooldown, ErrOtpResendLimit:
+		return http.StatusTooManyRequests, err.Error()
+	case ErrObjectTooLarge:
+		return http.StatusRequestEntityTooLarge, err.Error()
+	case ErrUnsupportedImageType:
+		return http.StatusUnsupportedMediaType, err.Error()
 	default:
 		return http.StatusInternalServerError, ErrInternalServer.Error()
 	}
@@ -1,7 +1,9 @@
 package cryptokit
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"math/big"
 	"strconv"
@@ -37,6 +39,11 @@ func GenerateSecureToken(length int) (string, error) {
 	return token, nil
 }
 
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 func CreateJWTToken(data jwt.MapClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, data)
 
@@ -85,3 +92,16 @@ func GenerateOTP() (string, error) {
 	}
 	return otp, nil
 }
+
+// HashOTP keys the hash with secret, as the few possible OTPs could otherwise be hashed and
+// compared by anyone reading the database. Binding it to scope keeps a hash from matching
+// the same code issued for another booking or purpose.
+func HashOTP(secret, scope, otp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(scope + ":" + otp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func CheckOTPHash(secret, scope, otp, hash string) bool {
+	return hmac.Equal([]byte(HashOTP(secret, scope, otp)), []byte(hash))
+}
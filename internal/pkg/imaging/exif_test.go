@@ -0,0 +1,121 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+type ifdEntry struct {
+	tag   uint16
+	value uint16
+}
+
+// tiffBlock builds a TIFF header followed by IFD0 holding SHORT entries.
+func tiffBlock(order binary.AppendByteOrder, entries ...ifdEntry) []byte {
+	block := []byte("II")
+	if order == binary.BigEndian {
+		block = []byte("MM")
+	}
+	block = order.AppendUint16(block, 42)
+	block = order.AppendUint32(block, 8)
+
+	block = order.AppendUint16(block, uint16(len(entries)))
+	for _, entry := range entries {
+		block = order.AppendUint16(block, entry.tag)
+		block = order.AppendUint16(block, 3)
+		block = order.AppendUint32(block, 1)
+		block = order.AppendUint16(block, entry.value)
+		block = append(block, 0, 0)
+	}
+
+	return order.AppendUint32(block, 0)
+}
+
+func segment(marker byte, payload []byte) []byte {
+	header := []byte{0xff, marker}
+	header = binary.BigEndian.AppendUint16(header, uint16(len(payload)+2))
+	return append(header, payload...)
+}
+
+func jpegOf(segments ...[]byte) []byte {
+	data := []byte{0xff, 0xd8}
+	for _, s := range segments {
+		data = append(data, s...)
+	}
+
+	return data
+}
+
+func exifSegment(tiff []byte) []byte {
+	return segment(0xe1, append([]byte("Exif\x00\x00"), tiff...))
+}
+
+func TestExifOrientation(t *testing.T) {
+	jfif := segment(0xe0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+	startOfScan := segment(0xda, []byte{0x01, 0x01, 0x00, 0x00, 0x3f, 0x00})
+	truncatedIFD := tiffBlock(binary.LittleEndian, ifdEntry{tag: exifOrientationTag, value: 6})
+	truncatedIFD = truncatedIFD[:len(truncatedIFD)-10]
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "empty", data: nil, want: 1},
+		{name: "not a jpeg", data: []byte("\x89PNG\r\n\x1a\n"), want: 1},
+		{name: "no exif", data: jpegOf(jfif, startOfScan), want: 1},
+		{
+			name: "little endian",
+			data: jpegOf(exifSegment(tiffBlock(binary.LittleEndian, ifdEntry{tag: exifOrientationTag, value: 6}))),
+			want: 6,
+		},
+		{
+			name: "big endian",
+			data: jpegOf(exifSegment(tiffBlock(binary.BigEndian, ifdEntry{tag: exifOrientationTag, value: 8}))),
+			want: 8,
+		},
+		{
+			name: "after other segments and entries",
+			data: jpegOf(jfif, exifSegment(tiffBlock(binary.LittleEndian,
+				ifdEntry{tag: 0x010f, value: 1},
+				ifdEntry{tag: exifOrientationTag, value: 3},
+			))),
+			want: 3,
+		},
+		{
+			name: "no orientation entry",
+			data: jpegOf(exifSegment(tiffBlock(binary.BigEndian, ifdEntry{tag: 0x010f, value: 6}))),
+			want: 1,
+		},
+		{
+			name: "exif after start of scan",
+			data: jpegOf(startOfScan, exifSegment(tiffBlock(binary.LittleEndian, ifdEntry{tag: exifOrientationTag, value: 6}))),
+			want: 1,
+		},
+		{
+			name: "xmp instead of exif",
+			data: jpegOf(segment(0xe1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>"))),
+			want: 1,
+		},
+		{
+			name: "unknown byte order",
+			data: jpegOf(exifSegment(append([]byte("XX"), tiffBlock(binary.LittleEndian, ifdEntry{tag: exifOrientationTag, value: 6})[2:]...))),
+			want: 1,
+		},
+		{name: "truncated ifd", data: jpegOf(exifSegment(truncatedIFD)), want: 1},
+		{
+			name: "segment longer than the data",
+			data: jpegOf(exifSegment(tiffBlock(binary.LittleEndian, ifdEntry{tag: exifOrientationTag, value: 6})))[:20],
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exifOrientation(tt.data)
+			if got != tt.want {
+				t.Errorf("exifOrientation = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
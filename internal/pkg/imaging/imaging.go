@@ -0,0 +1,164 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	VariantContentType = "image/jpeg"
+
+	// Decoding allocates width*height*4 bytes, so oversized images are rejected from their header.
+	maxSourcePixels = 50_000_000
+)
+
+type Variant struct {
+	Name         string
+	MaxDimension int
+}
+
+var (
+	Thumbnail = Variant{Name: "thumbnail", MaxDimension: 320}
+	Medium    = Variant{Name: "medium", MaxDimension: 800}
+	Large     = Variant{Name: "large", MaxDimension: 1600}
+
+	Variants = []Variant{Thumbnail, Medium, Large}
+)
+
+var ErrUnsupportedImage = errors.New("unsupported or oversized image")
+
+// VariantPath derives the object path a variant of objectPath is stored under.
+func VariantPath(objectPath string, variant Variant) string {
+	return objectPath + "_" + variant.Name + ".jpg"
+}
+
+// Decode reads a JPEG, PNG or WebP image, applies its EXIF orientation and flattens transparency
+// onto white. It also returns the format name. Metadata is not carried over, so anything
+// encoded from the result has no EXIF.
+func Decode(data []byte) (*image.NRGBA, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, "", fmt.Errorf("%w: %dx%d", ErrUnsupportedImage, config.Width, config.Height)
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	img := flatten(src)
+	if format == "jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+
+	return img, format, nil
+}
+
+// Resize scales img down so that neither side exceeds maxDimension, averaging the source
+// pixels covered by each destination pixel. Images that already fit are returned as is.
+func Resize(img *image.NRGBA, maxDimension int) *image.NRGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width <= maxDimension && height <= maxDimension {
+		return img
+	}
+
+	dstWidth, dstHeight := maxDimension, height*maxDimension/width
+	if height > width {
+		dstWidth, dstHeight = width*maxDimension/height, maxDimension
+	}
+	dstWidth, dstHeight = max(dstWidth, 1), max(dstHeight, 1)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		srcY0 := y * height / dstHeight
+		srcY1 := max((y+1)*height/dstHeight, srcY0+1)
+		for x := 0; x < dstWidth; x++ {
+			srcX0 := x * width / dstWidth
+			srcX1 := max((x+1)*width/dstWidth, srcX0+1)
+
+			var r, g, b, count uint64
+			for srcY := srcY0; srcY < srcY1; srcY++ {
+				offset := srcY*img.Stride + srcX0*4
+				for srcX := srcX0; srcX < srcX1; srcX++ {
+					r += uint64(img.Pix[offset])
+					g += uint64(img.Pix[offset+1])
+					b += uint64(img.Pix[offset+2])
+					count++
+					offset += 4
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+
+	return dst
+}
+
+func EncodeJPEG(w io.Writer, img image.Image, quality int) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+func flatten(src image.Image) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+
+	return dst
+}
+
+// orient applies one of the eight EXIF orientations so the pixels are stored upright.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2:
+				srcX, srcY = width-1-x, y
+			case 3:
+				srcX, srcY = width-1-x, height-1-y
+			case 4:
+				srcX, srcY = x, height-1-y
+			case 5:
+				srcX, srcY = y, x
+			case 6:
+				srcX, srcY = y, height-1-x
+			case 7:
+				srcX, srcY = width-1-y, height-1-x
+			case 8:
+				srcX, srcY = width-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], img.Pix[srcY*img.Stride+srcX*4:srcY*img.Stride+srcX*4+4])
+		}
+	}
+
+	return dst
+}
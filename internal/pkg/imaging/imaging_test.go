@@ -0,0 +1,52 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// grid builds an image with one row per string, where every byte becomes a gray pixel, so that
+// where each source pixel ends up can be read back.
+func grid(rows ...string) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x := 0; x < len(row); x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: row[x], G: row[x], B: row[x], A: 0xff})
+		}
+	}
+
+	return img
+}
+
+func TestOrient(t *testing.T) {
+	source := []string{
+		"abc",
+		"def",
+	}
+
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{orientation: 0, want: []string{"abc", "def"}},
+		{orientation: 1, want: []string{"abc", "def"}},
+		{orientation: 2, want: []string{"cba", "fed"}},
+		{orientation: 3, want: []string{"fed", "cba"}},
+		{orientation: 4, want: []string{"def", "abc"}},
+		{orientation: 5, want: []string{"ad", "be", "cf"}},
+		{orientation: 6, want: []string{"da", "eb", "fc"}},
+		{orientation: 7, want: []string{"fc", "eb", "da"}},
+		{orientation: 8, want: []string{"cf", "be", "ad"}},
+		{orientation: 9, want: []string{"abc", "def"}},
+	}
+
+	for _, tt := range tests {
+		got := orient(grid(source...), tt.orientation)
+		want := grid(tt.want...)
+		if got.Bounds() != want.Bounds() || !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("orient(%d) = %v, want %v", tt.orientation, got.Pix, want.Pix)
+		}
+	}
+}
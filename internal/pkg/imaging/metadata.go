@@ -0,0 +1,174 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// VP8X feature flags announcing EXIF and XMP chunks
+	webpExifFlag = 0x08
+	webpXMPFlag  = 0x04
+
+	pngSignature = "\x89PNG\r\n\x1a\n"
+)
+
+// PNG chunks carrying text, EXIF or timestamps; none of them affect how the image is drawn.
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+// StripMetadata removes the EXIF, XMP and text metadata of an image in the given format
+// ("jpeg", "png" or "webp") without re-encoding its pixels, so stripping an already stripped
+// image returns it unchanged.
+func StripMetadata(data []byte, format string) ([]byte, error) {
+	switch format {
+	case "jpeg":
+		return stripJPEGMetadata(data)
+	case "png":
+		return stripPNGMetadata(data)
+	case "webp":
+		return StripWebPMetadata(data)
+	default:
+		return nil, fmt.Errorf("%w: cannot strip metadata of %q", ErrUnsupportedImage, format)
+	}
+}
+
+// stripJPEGMetadata drops the APP1 (EXIF and XMP) segments in front of the scan data. A
+// non-upright EXIF orientation is kept in a minimal EXIF segment of its own, since the
+// original is served with its pixels as stored.
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, fmt.Errorf("%w: not a jpeg image", ErrUnsupportedImage)
+	}
+
+	orientation := exifOrientation(data)
+	stripped := make([]byte, 2, len(data))
+	copy(stripped, data[:2])
+	offset := 2
+	for {
+		if len(data)-offset < 4 || data[offset] != 0xff {
+			return nil, fmt.Errorf("%w: malformed jpeg segment", ErrUnsupportedImage)
+		}
+		marker := data[offset+1]
+		// Fill bytes may pad the gap before a marker.
+		if marker == 0xff {
+			offset++
+			continue
+		}
+		// Start of scan: everything from here on is entropy-coded image data.
+		if marker == 0xda {
+			return append(stripped, data[offset:]...), nil
+		}
+		segmentLength := int(binary.BigEndian.Uint16(data[offset+2:]))
+		segmentEnd := offset + 2 + segmentLength
+		if segmentLength < 2 || segmentEnd > len(data) {
+			return nil, fmt.Errorf("%w: truncated jpeg segment", ErrUnsupportedImage)
+		}
+
+		if marker != 0xe1 {
+			stripped = append(stripped, data[offset:segmentEnd]...)
+		} else if orientation > 1 {
+			stripped = append(stripped, orientationSegment(orientation)...)
+			orientation = 1
+		}
+		offset = segmentEnd
+	}
+}
+
+// orientationSegment builds an APP1 EXIF segment whose IFD0 only holds the orientation tag.
+func orientationSegment(orientation int) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, uint16(orientation))
+	tiff = append(tiff, 0, 0)
+	tiff = binary.BigEndian.AppendUint32(tiff, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, 0xe1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+
+	return append(segment, payload...)
+}
+
+// stripPNGMetadata drops the text, EXIF and timestamp chunks of a PNG image. Chunk checksums
+// only cover the chunk itself, so the remaining chunks are copied as they are.
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, fmt.Errorf("%w: not a png image", ErrUnsupportedImage)
+	}
+
+	stripped := make([]byte, len(pngSignature), len(data))
+	copy(stripped, pngSignature)
+	for offset := len(pngSignature); offset < len(data); {
+		if len(data)-offset < 12 {
+			return nil, fmt.Errorf("%w: truncated png chunk header", ErrUnsupportedImage)
+		}
+		size := int(binary.BigEndian.Uint32(data[offset : offset+4]))
+		if size > len(data)-offset-12 {
+			return nil, fmt.Errorf("%w: truncated png chunk", ErrUnsupportedImage)
+		}
+		end := offset + 12 + size
+
+		chunkType := string(data[offset+4 : offset+8])
+		if !pngMetadataChunks[chunkType] {
+			stripped = append(stripped, data[offset:end]...)
+		}
+		if chunkType == "IEND" {
+			break
+		}
+		offset = end
+	}
+
+	return stripped, nil
+}
+
+// IsWebP reports whether data starts with a WebP RIFF header.
+func IsWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// StripWebPMetadata removes the EXIF and XMP chunks of a WebP image without re-encoding it.
+func StripWebPMetadata(data []byte) ([]byte, error) {
+	if !IsWebP(data) {
+		return nil, fmt.Errorf("%w: not a webp image", ErrUnsupportedImage)
+	}
+
+	stripped := make([]byte, 12, len(data))
+	copy(stripped, data[:12])
+	for offset := 12; offset < len(data); {
+		if len(data)-offset < 8 {
+			return nil, fmt.Errorf("%w: truncated webp chunk header", ErrUnsupportedImage)
+		}
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		if size > len(data)-offset-8 {
+			return nil, fmt.Errorf("%w: truncated webp chunk", ErrUnsupportedImage)
+		}
+		// Chunks are padded to an even size; a missing final pad byte is tolerated.
+		end := min(offset+8+size+size%2, len(data))
+
+		switch string(data[offset : offset+4]) {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[offset:end]...)
+			if size > 0 {
+				chunk[8] &^= webpExifFlag | webpXMPFlag
+			}
+			stripped = append(stripped, chunk...)
+		default:
+			stripped = append(stripped, data[offset:end]...)
+		}
+		offset = end
+	}
+	binary.LittleEndian.PutUint32(stripped[4:8], uint32(len(stripped)-8))
+
+	return stripped, nil
+}
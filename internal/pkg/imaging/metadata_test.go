@@ -0,0 +1,152 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+func pngChunk(chunkType string, payload []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(payload)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func webpChunk(chunkType string, payload []byte) []byte {
+	chunk := append([]byte(chunkType), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+
+	return chunk
+}
+
+func webpOf(chunks ...[]byte) []byte {
+	body := []byte("WEBP")
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+
+	return append(append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...), body...)
+}
+
+func TestStripMetadata(t *testing.T) {
+	jfif := segment(0xe0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+	quantization := segment(0xdb, bytes.Repeat([]byte{0x01}, 65))
+	scan := append(segment(0xda, []byte{0x01, 0x01, 0x00, 0x00, 0x3f, 0x00}), 0x12, 0x34, 0xff, 0xd9)
+	xmp := segment(0xe1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>"))
+	gps := exifSegment(tiffBlock(binary.LittleEndian, ifdEntry{tag: 0x8825, value: 26}))
+	rotated := exifSegment(tiffBlock(binary.LittleEndian,
+		ifdEntry{tag: 0x010f, value: 1},
+		ifdEntry{tag: exifOrientationTag, value: 6},
+	))
+
+	header := pngChunk("IHDR", []byte{0, 0, 0, 1, 0, 0, 0, 1, 8, 2, 0, 0, 0})
+	pixels := pngChunk("IDAT", []byte{0x78, 0x9c, 0x62, 0x60, 0x00, 0x00})
+	end := pngChunk("IEND", nil)
+
+	vp8 := webpChunk("VP8 ", []byte{0x01, 0x02, 0x03})
+
+	tests := []struct {
+		name   string
+		format string
+		data   []byte
+		want   []byte
+	}{
+		{
+			name:   "jpeg without metadata",
+			format: "jpeg",
+			data:   jpegOf(jfif, quantization, scan),
+			want:   jpegOf(jfif, quantization, scan),
+		},
+		{
+			name:   "jpeg exif and xmp",
+			format: "jpeg",
+			data:   jpegOf(jfif, gps, xmp, quantization, scan),
+			want:   jpegOf(jfif, quantization, scan),
+		},
+		{
+			name:   "jpeg keeps only the orientation",
+			format: "jpeg",
+			data:   jpegOf(jfif, rotated, xmp, quantization, scan),
+			want:   jpegOf(jfif, orientationSegment(6), quantization, scan),
+		},
+		{
+			name:   "jpeg with fill bytes",
+			format: "jpeg",
+			data:   jpegOf(append([]byte{0xff}, gps...), quantization, scan),
+			want:   jpegOf(quantization, scan),
+		},
+		{
+			name:   "png text and exif",
+			format: "png",
+			data: bytes.Join([][]byte{
+				[]byte(pngSignature), header,
+				pngChunk("tEXt", []byte("Author\x00someone")),
+				pngChunk("eXIf", []byte("MM\x00\x2a")),
+				pixels,
+				pngChunk("tIME", []byte{0x07, 0xea, 1, 1, 0, 0, 0}),
+				end,
+			}, nil),
+			want: bytes.Join([][]byte{[]byte(pngSignature), header, pixels, end}, nil),
+		},
+		{
+			name:   "webp exif and xmp",
+			format: "webp",
+			data: webpOf(
+				webpChunk("VP8X", []byte{webpExifFlag | webpXMPFlag, 0, 0, 0, 0, 0, 0, 0, 0, 0}),
+				vp8,
+				webpChunk("EXIF", []byte("MM\x00\x2a\x00")),
+				webpChunk("XMP ", []byte("<x:xmpmeta/>")),
+			),
+			want: webpOf(webpChunk("VP8X", make([]byte, 10)), vp8),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StripMetadata(tt.data, tt.format)
+			if err != nil {
+				t.Fatalf("StripMetadata: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("StripMetadata = %x, want %x", got, tt.want)
+			}
+
+			again, err := StripMetadata(got, tt.format)
+			if err != nil {
+				t.Fatalf("StripMetadata of stripped image: %v", err)
+			}
+			if !bytes.Equal(again, got) {
+				t.Errorf("stripping twice = %x, want %x", again, got)
+			}
+		})
+	}
+}
+
+func TestStripMetadataRejectsMalformedImages(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   []byte
+	}{
+		{name: "jpeg signature missing", format: "jpeg", data: []byte("\x89PNG\r\n\x1a\n")},
+		{name: "jpeg without scan", format: "jpeg", data: jpegOf(segment(0xe0, []byte("JFIF\x00")))},
+		{name: "jpeg truncated segment", format: "jpeg", data: jpegOf(segment(0xe1, []byte("Exif\x00\x00")))[:6]},
+		{name: "png truncated chunk", format: "png", data: append([]byte(pngSignature), pngChunk("IHDR", make([]byte, 13))[:10]...)},
+		{name: "webp signature missing", format: "webp", data: []byte("RIFF")},
+		{name: "unknown format", format: "gif", data: []byte("GIF89a")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := StripMetadata(tt.data, tt.format)
+			if err == nil {
+				t.Fatal("StripMetadata succeeded, want an error")
+			}
+		})
+	}
+}
@@ -18,6 +18,11 @@ type RequestContextKey string
 
 var RequestContextUserIdKey RequestContextKey = "userId"
 var RequestContextRoleKey RequestContextKey = "role"
+var RequestContextSessionIdKey RequestContextKey = "sessionId"
+
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, sessionId string, userId int) error
+}
 
 func ChainMiddleware(h http.HandlerFunc, m ...Middleware) http.HandlerFunc {
 	wrapped := h
@@ -29,43 +34,60 @@ func ChainMiddleware(h http.HandlerFunc, m ...Middleware) http.HandlerFunc {
 	return wrapped
 }
 
-func AuthenticationMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		bearerToken := r.Header.Get("Authorization")
+func AuthenticationMiddleware(sessionValidator SessionValidator) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			bearerToken := r.Header.Get("Authorization")
 
-		if bearerToken == "" {
-			slog.Error("no authentication token provided in request")
-			response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
-			return
-		}
+			if bearerToken == "" {
+				slog.Error("no authentication token provided in request")
+				response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
+				return
+			}
 
-		token := strings.Split(bearerToken, " ")[1]
-		data, err := cryptokit.VerifyJWTToken(token)
-		if err != nil {
-			slog.Error("invalid or expired jwt token", "error", err)
-			response.WriteJson(w, http.StatusUnauthorized, err.Error(), nil)
-			return
-		}
+			token := strings.Split(bearerToken, " ")[1]
+			data, err := cryptokit.VerifyJWTToken(token)
+			if err != nil {
+				slog.Error("invalid or expired jwt token", "error", err)
+				response.WriteJson(w, http.StatusUnauthorized, err.Error(), nil)
+				return
+			}
 
-		userId, ok := data["id"].(float64)
-		if !ok {
-			slog.Error("user id missing or invalid in token", "token", token)
-			response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
-			return
-		}
+			userId, ok := data["id"].(float64)
+			if !ok {
+				slog.Error("user id missing or invalid in token", "token", token)
+				response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
+				return
+			}
 
-		role, ok := data["role"].(string)
-		if !ok {
-			slog.Error("role missing or invalid in token", "token", token)
-			response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
-			return
-		}
+			role, ok := data["role"].(string)
+			if !ok {
+				slog.Error("role missing or invalid in token", "token", token)
+				response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
+				return
+			}
+
+			sessionId, ok := data["sid"].(string)
+			if !ok {
+				slog.Error("session id missing or invalid in token", "token", token)
+				response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
+				return
+			}
+
+			err = sessionValidator.ValidateSession(r.Context(), sessionId, int(userId))
+			if err != nil {
+				slog.Error("session validation failed", "error", err)
+				response.WriteJson(w, http.StatusUnauthorized, apperrors.ErrUnauthorizedAccess.Error(), nil)
+				return
+			}
 
-		ctx := context.WithValue(r.Context(), RequestContextUserIdKey, int(userId))
-		ctx = context.WithValue(ctx, RequestContextRoleKey, role)
-		r = r.WithContext(ctx)
+			ctx := context.WithValue(r.Context(), RequestContextUserIdKey, int(userId))
+			ctx = context.WithValue(ctx, RequestContextRoleKey, role)
+			ctx = context.WithValue(ctx, RequestContextSessionIdKey, sessionId)
+			r = r.WithContext(ctx)
 
-		next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r)
+		}
 	}
 }
 
@@ -0,0 +1,164 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// Request is the pagination part of a list request. Clients either ask for a page number or
+// pass back a nextCursor or prevCursor from a previous response.
+type Request struct {
+	Page      int
+	Limit     int
+	Cursor    string
+	SkipTotal bool
+}
+
+// Cursor marks the row a page starts after (or, going backward, before) by the values of
+// that row's sort key. Cursors only apply to the sort order they were issued for.
+type Cursor struct {
+	Sort     string          `json:"s"`
+	Key      json.RawMessage `json:"k"`
+	Backward bool            `json:"b,omitempty"`
+}
+
+// Params is what a repository needs to fetch one page. Offset is only used without a cursor.
+type Params struct {
+	Offset    int
+	Limit     int
+	Cursor    *Cursor
+	SkipTotal bool
+}
+
+// Info describes the page a repository returned. Keys are the sort keys of the first and
+// last row, from which the cursors to the neighbouring pages are built.
+type Info struct {
+	TotalCount *int
+	FirstKey   json.RawMessage
+	LastKey    json.RawMessage
+	HasNext    bool
+	HasPrev    bool
+}
+
+type Page struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize"`
+	TotalCount *int   `json:"totalCount,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+// ParseRequest reads the page, limit, cursor and skipTotal query parameters.
+func ParseRequest(r *http.Request) (Request, error) {
+	query := r.URL.Query()
+	request := Request{Page: 1, Limit: DefaultLimit, Cursor: query.Get("cursor")}
+
+	var err error
+	if value := query.Get("page"); value != "" {
+		if request.Cursor != "" {
+			return Request{}, fmt.Errorf("page and cursor cannot be combined")
+		}
+		request.Page, err = strconv.Atoi(value)
+		if err != nil {
+			return Request{}, err
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		request.Limit, err = strconv.Atoi(value)
+		if err != nil {
+			return Request{}, err
+		}
+	}
+
+	if value := query.Get("skipTotal"); value != "" {
+		request.SkipTotal, err = strconv.ParseBool(value)
+		if err != nil {
+			return Request{}, err
+		}
+	}
+
+	return request, nil
+}
+
+// Params validates the request for a list sorted by sort.
+func (r Request) Params(sort string) (Params, error) {
+	if r.Limit <= 0 || r.Limit > MaxLimit {
+		slog.Error("invalid limit value provided", "limit", r.Limit)
+		return Params{}, apperrors.ErrInvalidPagination
+	}
+
+	params := Params{Limit: r.Limit, SkipTotal: r.SkipTotal}
+	if r.Cursor == "" {
+		if r.Page <= 0 {
+			slog.Error("invalid page number provided", "page", r.Page)
+			return Params{}, apperrors.ErrInvalidPagination
+		}
+		params.Offset = r.Limit * (r.Page - 1)
+		return params, nil
+	}
+
+	cursor, err := decodeCursor(r.Cursor)
+	if err != nil {
+		slog.Error("failed to decode pagination cursor", "error", err)
+		return Params{}, apperrors.ErrInvalidCursor
+	}
+	if cursor.Sort != sort {
+		slog.Error("pagination cursor was issued for another sort order", "cursorSort", cursor.Sort, "sort", sort)
+		return Params{}, apperrors.ErrInvalidCursor
+	}
+	params.Cursor = &cursor
+
+	return params, nil
+}
+
+// NewPage builds the pagination block of a list response.
+func NewPage(request Request, sort string, info Info) Page {
+	page := Page{PageSize: request.Limit, TotalCount: info.TotalCount}
+	if request.Cursor == "" {
+		page.Page = request.Page
+	}
+
+	if info.HasNext && info.LastKey != nil {
+		page.NextCursor = encodeCursor(Cursor{Sort: sort, Key: info.LastKey})
+	}
+	if info.HasPrev && info.FirstKey != nil {
+		page.PrevCursor = encodeCursor(Cursor{Sort: sort, Key: info.FirstKey, Backward: true})
+	}
+
+	return page
+}
+
+func encodeCursor(cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeCursor(value string) (Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	var cursor Cursor
+	err = json.Unmarshal(payload, &cursor)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if len(cursor.Key) == 0 {
+		return Cursor{}, fmt.Errorf("cursor has no key")
+	}
+
+	return cursor, nil
+}
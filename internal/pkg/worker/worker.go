@@ -0,0 +1,28 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RunPeriodically runs job immediately and then on every tick of interval until ctx is cancelled.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slog.Info("background worker started", "worker", name, "interval", interval)
+	for {
+		err := job(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("background worker run failed", "worker", name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("background worker stopped", "worker", name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,9 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation    pq.ErrorCode = "23505"
+	pqExclusionViolation pq.ErrorCode = "23P01"
 )
 
 type BaseRepository struct {
@@ -80,3 +87,10 @@ func (b *BaseRepository) initiateQueryExecuter(tx *sql.Tx) QueryExecuter {
 
 	return b.DB
 }
+
+// isConstraintViolation reports whether err was raised by the named constraint, or unique index,
+// rejecting a row with the given SQLSTATE code.
+func isConstraintViolation(err error, code pq.ErrorCode, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code && pqErr.Constraint == constraint
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+	"github.com/lib/pq"
 )
 
 type bookingRepository struct {
@@ -17,18 +19,40 @@ type bookingRepository struct {
 type BookingRepository interface {
 	RepositoryTransaction
 	CreateBooking(ctx context.Context, tx *sql.Tx, bookingData CreateBookingRequestBody) (Booking, error)
-	VehicleBookingConflictCheck(ctx context.Context, tx *sql.Tx, vehicleId int, scheduledPickupTimestamp, scheduledDropoffTimestamp time.Time) error
-	CreateOtpToken(ctx context.Context, tx *sql.Tx, tokenData OtpToken) error
-	GetOtpToken(ctx context.Context, tx *sql.Tx, otp string) (OtpToken, error)
+	VehicleBookingConflictCheck(ctx context.Context, tx *sql.Tx, vehicleId int, scheduledPickupTimestamp, scheduledDropoffTimestamp time.Time, bookingBuffer time.Duration, excludeBookingId int) error
+	CreateOtpToken(ctx context.Context, tx *sql.Tx, tokenData OtpToken, maxResends int) error
+	GetOtpToken(ctx context.Context, tx *sql.Tx, bookingId int, purpose string) (OtpToken, error)
+	RecordOtpAttempt(ctx context.Context, tx *sql.Tx, otpTokenId, maxAttempts int) (bool, error)
 	DeleteOtpTokenById(ctx context.Context, tx *sql.Tx, otpTokenId int) error
-	UpdateBookingStatus(ctx context.Context, tx *sql.Tx, bookingId int, status string) error
+	UpdateBookingStatus(ctx context.Context, tx *sql.Tx, bookingId int, fromStatus, toStatus string) error
+	CreateBookingStatusHistory(ctx context.Context, tx *sql.Tx, historyData BookingStatusHistory) error
+	GetBookingStatusHistory(ctx context.Context, tx *sql.Tx, bookingId int) ([]BookingStatusHistory, error)
 	UpdateActualPickupTime(ctx context.Context, tx *sql.Tx, bookingId int) error
 	UpdateActualDropoffTime(ctx context.Context, tx *sql.Tx, bookingId int) error
 	GetBookingById(ctx context.Context, tx *sql.Tx, bookingId int) (Booking, error)
 	CreateInvoice(ctx context.Context, tx *sql.Tx, invoiceData Invoice) (Invoice, error)
-	GetSeekerBookings(ctx context.Context, tx *sql.Tx, params GetSeekerBookingsParams) ([]BookingData, int, error)
-	GetHostBookings(ctx context.Context, tx *sql.Tx, params GetHostBookingsParams) ([]BookingData, int, error)
+	GetSeekerBookings(ctx context.Context, tx *sql.Tx, params GetSeekerBookingsParams) ([]BookingData, pagination.Info, error)
+	GetHostBookings(ctx context.Context, tx *sql.Tx, params GetHostBookingsParams) ([]BookingData, pagination.Info, error)
 	GetBookingDetailsById(ctx context.Context, tx *sql.Tx, bookingId int) (BookingDetails, error)
+	UpdateBookingSchedule(ctx context.Context, tx *sql.Tx, scheduleData BookingScheduleUpdate) error
+	UpdateOtpTokenExpiry(ctx context.Context, tx *sql.Tx, bookingId int, fromExpiry, toExpiry time.Time) error
+	CreateBookingChangeRequest(ctx context.Context, tx *sql.Tx, requestData CreateBookingChangeRequestData) (BookingChangeRequest, error)
+	GetBookingChangeRequestById(ctx context.Context, tx *sql.Tx, requestId int) (BookingChangeRequest, error)
+	GetBookingChangeRequests(ctx context.Context, tx *sql.Tx, bookingId int) ([]BookingChangeRequest, error)
+	DecideBookingChangeRequest(ctx context.Context, tx *sql.Tx, requestId int, status string, decidedBy int, reason string) error
+	DeclinePendingBookingChangeRequests(ctx context.Context, tx *sql.Tx, bookingId int, reason string) error
+	CreateBookingCancellation(ctx context.Context, tx *sql.Tx, cancellationData CreateBookingCancellationData) error
+	GetBookingCancellation(ctx context.Context, tx *sql.Tx, bookingId int) (BookingCancellation, error)
+	GetHostCancellations(ctx context.Context, tx *sql.Tx, params GetHostCancellationsParams) ([]BookingCancellation, pagination.Info, error)
+	GetNoShowBookings(ctx context.Context, tx *sql.Tx, pickupBefore time.Time, limit int) ([]Booking, error)
+	GetOverdueBookings(ctx context.Context, tx *sql.Tx, dropoffBefore time.Time, limit int) ([]Booking, error)
+	FlagBookingNoShow(ctx context.Context, tx *sql.Tx, bookingId int) (bool, error)
+	FlagBookingOverdue(ctx context.Context, tx *sql.Tx, bookingId int) (bool, error)
+	GetBookingVehicleName(ctx context.Context, tx *sql.Tx, bookingId int) (string, error)
+	CreateBookingReminder(ctx context.Context, tx *sql.Tx, reminderData CreateBookingReminderData) error
+	CancelBookingReminders(ctx context.Context, tx *sql.Tx, bookingId int, kinds []string) error
+	GetDueBookingReminders(ctx context.Context, tx *sql.Tx, dueBefore time.Time, limit int) ([]BookingReminder, error)
+	UpdateBookingReminderStatus(ctx context.Context, tx *sql.Tx, reminderId int, status string) (bool, error)
 }
 
 func NewBookingRepository(db *sql.DB) BookingRepository {
@@ -50,36 +74,81 @@ const (
 		overdue_fee_rate_per_hour,
 		cancellation_allowed,
 		scheduled_pickup_time,
-		scheduled_dropoff_time
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		scheduled_dropoff_time,
+		cancellation_policy
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	RETURNING *;`
 
+	// Ranges overlap when each starts no later than the other ends, bounds included. Active
+	// bookings are widened by the turnaround buffer ($4 seconds) on both sides. A booking
+	// being changed is excluded ($5) so that it does not conflict with itself.
 	vehicleBookingConflictCheckQuery = `
 	SELECT 1
 	FROM bookings
 	WHERE
 		vehicle_id=$1 AND
+		id<>$5 AND
 		status NOT IN ('RETURNED', 'CANCELLED') AND
-		(
-			(scheduled_pickup_time <= $2 AND $2 <= scheduled_dropoff_time) OR
-			(scheduled_pickup_time <= $3 AND $3 <= scheduled_dropoff_time) OR
-			($2 <= scheduled_pickup_time AND scheduled_dropoff_time <= $3) 
-		)
+		scheduled_pickup_time - make_interval(secs => $4) <= $3 AND
+		$2 <= scheduled_dropoff_time + make_interval(secs => $4)
+	UNION ALL
+	SELECT 1
+	FROM vehicle_blackouts
+	WHERE
+		vehicle_id=$1 AND
+		starts_at <= $3 AND $2 <= ends_at
 	LIMIT 1;`
 
+	// Sharing the vehicle row lock keeps the vehicle from being paused or blacked out
+	// while a booking for it is being created or rescheduled.
+	lockBookableVehicleQuery = "SELECT available, is_deleted FROM vehicles WHERE id=$1 FOR SHARE"
+
+	// The no-overlap constraint does not know about the booking buffer, so with a buffer
+	// bookings for the same vehicle are serialized on its row instead.
+	lockBookableVehicleExclusiveQuery = "SELECT available, is_deleted FROM vehicles WHERE id=$1 FOR UPDATE"
+
+	// Issuing an OTP replaces the booking's previous one for the same purpose, along with its
+	// attempts. Replacements are counted and stop at $5, so resending cannot lift the attempt
+	// limit indefinitely.
 	createOtpTokenQuery = `
 	INSERT INTO otp_tokens (
 		booking_id,
-		otp,
+		purpose,
+		otp_hash,
 		expires_at
-	) VALUES ($1, $2, $3)
-	RETURNING *;`
+	) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (booking_id, purpose) DO UPDATE
+	SET otp_hash = EXCLUDED.otp_hash,
+		expires_at = EXCLUDED.expires_at,
+		attempts = 0,
+		resends = otp_tokens.resends + 1,
+		created_at = CURRENT_TIMESTAMP
+	WHERE otp_tokens.resends < $5;`
+
+	getOtpTokenQuery = `
+	SELECT id, booking_id, purpose, otp_hash, attempts, expires_at, created_at
+	FROM otp_tokens
+	WHERE booking_id=$1 AND purpose=$2;`
 
-	getOtpTokenQuery = "SELECT * FROM otp_tokens WHERE otp=$1;"
+	// Attempts are counted before the OTP is compared, so that concurrent guesses cannot get
+	// past the limit.
+	recordOtpAttemptQuery = "UPDATE otp_tokens SET attempts = attempts + 1 WHERE id=$1 AND attempts < $2;"
 
 	deleteOtpTokenByIdQuery = "DELETE FROM otp_tokens WHERE id=$1;"
 
-	updateBookingStatusQuery = "UPDATE bookings SET status=$1 WHERE id=$2;"
+	updateBookingStatusQuery = "UPDATE bookings SET status=$1 WHERE id=$2 AND status=$3;"
+
+	createBookingStatusHistoryQuery = `
+	INSERT INTO booking_status_history (
+		booking_id,
+		from_status,
+		to_status,
+		actor_id,
+		actor_role,
+		reason
+	) VALUES ($1, $2, $3, $4, $5, $6);`
+
+	getBookingStatusHistoryQuery = "SELECT * FROM booking_status_history WHERE booking_id=$1 ORDER BY created_at, id;"
 
 	getBookingById = "SELECT * FROM bookings WHERE id=$1"
 
@@ -104,8 +173,7 @@ const (
 	SET actual_dropoff_time = CURRENT_TIMESTAMP
 	WHERE id = $1;`
 
-	getSeekerBookingsQuery = `
-	SELECT 
+	bookingDataColumns = `
 		b.id,
 		b.status,
 		b.pickup_location,
@@ -115,54 +183,21 @@ const (
 		b.cancellation_allowed,
 		b.scheduled_pickup_time,
 		b.scheduled_dropoff_time,
+		b.no_show_at,
+		b.overdue_at,
 		v.name AS vehicleName,
 		v.seat_count AS vehicleSeatCount,
 		v.fuel_type AS vehicleFuelType,
 		v.transmission_type AS vehicleTransmissionType,
 		COALESCE((
-			SELECT vi.url
+			SELECT COALESCE(vi.thumbnail_path, vi.object_path, vi.url)
 			FROM vehicle_images vi
 			WHERE vi.vehicle_id = v.id
 			AND vi.featured = true
 			LIMIT 1
-		), '') AS vehicleImage,
-		COUNT(*) OVER() AS total_count
-	FROM bookings b
-	JOIN vehicles v ON b.vehicle_id = v.id
-	WHERE b.seeker_id=$1
-	ORDER BY b.created_at DESC
-	OFFSET $2
-	LIMIT $3;`
+		), '') AS vehicleImage`
 
-	getHostBookingsQuery = `
-	SELECT 
-		b.id,
-		b.status,
-		b.pickup_location,
-		b.dropoff_location,
-		b.booking_amount,
-		b.overdue_fee_rate_per_hour,
-		b.cancellation_allowed,
-		b.scheduled_pickup_time,
-		b.scheduled_dropoff_time,
-		v.name AS vehicleName,
-		v.seat_count AS vehicleSeatCount,
-		v.fuel_type AS vehicleFuelType,
-		v.transmission_type AS vehicleTransmissionType,
-		COALESCE((
-			SELECT vi.url
-			FROM vehicle_images vi
-			WHERE vi.vehicle_id = v.id
-			AND vi.featured = true
-			LIMIT 1
-		), '') AS vehicleImage,
-		COUNT(*) OVER() AS total_count
-	FROM bookings b
-	JOIN vehicles v ON b.vehicle_id = v.id
-	WHERE b.host_id=$1
-	ORDER BY b.created_at DESC
-	OFFSET $2
-	LIMIT $3;`
+	bookingDataFrom = "bookings b JOIN vehicles v ON b.vehicle_id = v.id"
 
 	getBookingDetailsByIdQuery = `
 	SELECT 
@@ -173,10 +208,13 @@ const (
 		b.booking_amount,
 		b.overdue_fee_rate_per_hour,
 		b.cancellation_allowed,
+		b.cancellation_policy,
 		b.actual_pickup_time,
 		b.actual_dropoff_time,
 		b.scheduled_pickup_time,
 		b.scheduled_dropoff_time,
+		b.no_show_at,
+		b.overdue_at,
 		h.id AS host_id,
 		h.name AS host_name,
 		h.email AS host_email,
@@ -191,7 +229,7 @@ const (
 		v.seat_count,
 		v.transmission_type,
 		COALESCE((
-			SELECT vi.url
+			SELECT COALESCE(vi.medium_path, vi.object_path, vi.url)
 			FROM vehicle_images vi
 			WHERE vi.vehicle_id = v.id
 			AND vi.featured = true
@@ -208,6 +246,123 @@ const (
 	JOIN vehicles v ON b.vehicle_id = v.id
 	LEFT JOIN invoices i ON i.booking_id = b.id
 	WHERE b.id = $1;`
+
+	updateBookingScheduleQuery = `
+	UPDATE bookings
+	SET scheduled_pickup_time = $2, scheduled_dropoff_time = $3, booking_amount = $4
+	WHERE
+		id = $1 AND
+		status IN ('SCHEDULED', 'CHECKED_OUT') AND
+		scheduled_pickup_time = $5 AND
+		scheduled_dropoff_time = $6;`
+
+	updateOtpTokenExpiryQuery = "UPDATE otp_tokens SET expires_at=$3 WHERE booking_id=$1 AND purpose='PICKUP' AND expires_at=$2;"
+
+	createBookingChangeRequestQuery = `
+	INSERT INTO booking_change_requests (
+		booking_id,
+		type,
+		status,
+		previous_pickup_time,
+		previous_dropoff_time,
+		previous_amount,
+		new_pickup_time,
+		new_dropoff_time,
+		new_amount,
+		requested_by,
+		decided_by,
+		reason,
+		decided_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CASE WHEN $3 = 'PENDING' THEN NULL ELSE CURRENT_TIMESTAMP END)
+	RETURNING *;`
+
+	getBookingChangeRequestByIdQuery = "SELECT * FROM booking_change_requests WHERE id=$1;"
+
+	getBookingChangeRequestsQuery = "SELECT * FROM booking_change_requests WHERE booking_id=$1 ORDER BY created_at, id;"
+
+	decideBookingChangeRequestQuery = `
+	UPDATE booking_change_requests
+	SET status = $2, decided_by = $3, reason = $4, decided_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND status = 'PENDING';`
+
+	// Pending requests closed by the system are left without decided_by.
+	declinePendingBookingChangeRequestsQuery = `
+	UPDATE booking_change_requests
+	SET status = 'DECLINED', reason = $2, decided_at = CURRENT_TIMESTAMP
+	WHERE booking_id = $1 AND status = 'PENDING';`
+
+	createBookingCancellationQuery = `
+	INSERT INTO booking_cancellations (
+		booking_id,
+		host_id,
+		cancelled_by,
+		initiated_by,
+		policy,
+		hours_before_pickup,
+		booking_amount,
+		refund_percent,
+		refund_amount,
+		cancellation_fee
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+
+	getBookingCancellationQuery = "SELECT * FROM booking_cancellations WHERE booking_id=$1;"
+
+	getNoShowBookingsQuery = `
+	SELECT *
+	FROM bookings
+	WHERE status = 'SCHEDULED' AND no_show_at IS NULL AND scheduled_pickup_time < $1
+	ORDER BY scheduled_pickup_time
+	LIMIT $2;`
+
+	getOverdueBookingsQuery = `
+	SELECT *
+	FROM bookings
+	WHERE status = 'CHECKED_OUT' AND overdue_at IS NULL AND scheduled_dropoff_time < $1
+	ORDER BY scheduled_dropoff_time
+	LIMIT $2;`
+
+	// Flags are only set once and only in the status they apply to, which makes the scheduled
+	// jobs safe to run again on bookings they have already handled.
+	flagBookingNoShowQuery = "UPDATE bookings SET no_show_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = 'SCHEDULED' AND no_show_at IS NULL;"
+
+	flagBookingOverdueQuery = "UPDATE bookings SET overdue_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = 'CHECKED_OUT' AND overdue_at IS NULL;"
+
+	// Soft-deleted vehicles are included, as their bookings still have to be followed up on.
+	getBookingVehicleNameQuery = "SELECT v.name FROM bookings b JOIN vehicles v ON v.id = b.vehicle_id WHERE b.id = $1;"
+
+	createBookingReminderQuery = "INSERT INTO booking_reminders (booking_id, kind, remind_at) VALUES ($1, $2, $3);"
+
+	cancelBookingRemindersQuery = `
+	UPDATE booking_reminders
+	SET status = 'CANCELLED', updated_at = CURRENT_TIMESTAMP
+	WHERE booking_id = $1 AND kind = ANY($2) AND status = 'PENDING';`
+
+	getDueBookingRemindersQuery = `
+	SELECT *
+	FROM booking_reminders
+	WHERE status = 'PENDING' AND remind_at <= $1
+	ORDER BY remind_at
+	LIMIT $2;`
+
+	// Only pending reminders change status, so a reminder is sent or cancelled at most once.
+	updateBookingReminderStatusQuery = `
+	UPDATE booking_reminders
+	SET status = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND status = 'PENDING';`
+
+	bookingCancellationColumns = `
+		c.id,
+		c.booking_id,
+		c.host_id,
+		c.cancelled_by,
+		c.initiated_by,
+		c.policy,
+		c.hours_before_pickup,
+		c.booking_amount,
+		c.refund_percent,
+		c.refund_amount,
+		c.cancellation_fee,
+		c.created_at`
 )
 
 func (br *bookingRepository) CreateBooking(ctx context.Context, tx *sql.Tx, bookingData CreateBookingRequestBody) (Booking, error) {
@@ -228,25 +383,13 @@ func (br *bookingRepository) CreateBooking(ctx context.Context, tx *sql.Tx, book
 		bookingData.CancellationAllowed,
 		bookingData.ScheduledPickupTime,
 		bookingData.ScheduledDropoffTime,
-	).Scan(
-		&booking.Id,
-		&booking.VehicleId,
-		&booking.HostId,
-		&booking.SeekerId,
-		&booking.Status,
-		&booking.PickupLocation,
-		&booking.DropoffLocation,
-		&booking.BookingAmount,
-		&booking.OverdueFeeRatePerHour,
-		&booking.CancellationAllowed,
-		&booking.ActualPickupTime,
-		&booking.ActualDropoffTime,
-		&booking.ScheduledPickupTime,
-		&booking.ScheduledDropoffTime,
-		&booking.CreatedAt,
-		&booking.UpdatedAt,
-	)
+		bookingData.CancellationPolicy,
+	).Scan(bookingScanFields(&booking)...)
 	if err != nil {
+		if isConstraintViolation(err, pqExclusionViolation, "bookings_no_overlap") {
+			slog.Error("booking overlaps with an existing booking for the vehicle", "error", err)
+			return Booking{}, apperrors.ErrBookingConflict
+		}
 		slog.Error("failed to create booking", "error", err)
 		return Booking{}, apperrors.ErrInternalServer
 	}
@@ -254,16 +397,48 @@ func (br *bookingRepository) CreateBooking(ctx context.Context, tx *sql.Tx, book
 	return booking, nil
 }
 
-func (br *bookingRepository) VehicleBookingConflictCheck(ctx context.Context, tx *sql.Tx, vehicleId int, scheduledPickupTimestamp, scheduledDropoffTimestamp time.Time) error {
+// VehicleBookingConflictCheck locks the vehicle and reports whether the range, padded by
+// bookingBuffer, overlaps another booking or a blackout. A paused or deleted vehicle only
+// rejects new bookings; moving an existing one, identified by excludeBookingId, is still allowed.
+func (br *bookingRepository) VehicleBookingConflictCheck(ctx context.Context, tx *sql.Tx, vehicleId int, scheduledPickupTimestamp, scheduledDropoffTimestamp time.Time, bookingBuffer time.Duration, excludeBookingId int) error {
 	executer := br.initiateQueryExecuter(tx)
 
+	lockQuery := lockBookableVehicleQuery
+	if bookingBuffer > 0 {
+		lockQuery = lockBookableVehicleExclusiveQuery
+	}
+
+	var available, deleted bool
+	err := executer.QueryRowContext(ctx, lockQuery, vehicleId).Scan(&available, &deleted)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("no vehicle found", "error", err)
+			return apperrors.ErrVehicleNotFound
+		}
+		slog.Error("failed to lock vehicle for booking", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if excludeBookingId == 0 {
+		if deleted {
+			slog.Error("no vehicle found", "vehicleId", vehicleId)
+			return apperrors.ErrVehicleNotFound
+		}
+		if !available {
+			slog.Error("vehicle is paused by its host", "vehicleId", vehicleId)
+			return apperrors.ErrVehicleUnavailable
+		}
+	}
+
 	var flag int
-	err := executer.QueryRowContext(
+	err = executer.QueryRowContext(
 		ctx,
 		vehicleBookingConflictCheckQuery,
 		vehicleId,
 		scheduledPickupTimestamp,
 		scheduledDropoffTimestamp,
+		bookingBuffer.Seconds(),
+		excludeBookingId,
 	).Scan(&flag)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -276,37 +451,53 @@ func (br *bookingRepository) VehicleBookingConflictCheck(ctx context.Context, tx
 	return apperrors.ErrBookingConflict
 }
 
-func (br *bookingRepository) CreateOtpToken(ctx context.Context, tx *sql.Tx, tokenData OtpToken) error {
+func (br *bookingRepository) CreateOtpToken(ctx context.Context, tx *sql.Tx, tokenData OtpToken, maxResends int) error {
 	executer := br.initiateQueryExecuter(tx)
 
-	_, err := executer.ExecContext(
+	result, err := executer.ExecContext(
 		ctx,
 		createOtpTokenQuery,
 		tokenData.BookingId,
-		tokenData.Otp,
+		tokenData.Purpose,
+		tokenData.OtpHash,
 		tokenData.ExpiresAt,
+		maxResends,
 	)
 	if err != nil {
 		slog.Error("failed to create otp token", "error", err)
 		return apperrors.ErrInternalServer
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.ErrOtpResendLimit
+	}
+
 	return nil
 }
 
-func (br *bookingRepository) GetOtpToken(ctx context.Context, tx *sql.Tx, otp string) (OtpToken, error) {
+func (br *bookingRepository) GetOtpToken(ctx context.Context, tx *sql.Tx, bookingId int, purpose string) (OtpToken, error) {
 	executer := br.initiateQueryExecuter(tx)
 
 	var optToken OtpToken
 	err := executer.QueryRowContext(
 		ctx,
 		getOtpTokenQuery,
-		otp,
+		bookingId,
+		purpose,
 	).Scan(
 		&optToken.Id,
 		&optToken.BookingId,
-		&optToken.Otp,
+		&optToken.Purpose,
+		&optToken.OtpHash,
+		&optToken.Attempts,
 		&optToken.ExpiresAt,
+		&optToken.CreatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -318,30 +509,132 @@ func (br *bookingRepository) GetOtpToken(ctx context.Context, tx *sql.Tx, otp st
 	return optToken, nil
 }
 
+// RecordOtpAttempt reports whether the OTP had an attempt left.
+func (br *bookingRepository) RecordOtpAttempt(ctx context.Context, tx *sql.Tx, otpTokenId, maxAttempts int) (bool, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, recordOtpAttemptQuery, otpTokenId, maxAttempts)
+	if err != nil {
+		slog.Error("failed to record otp attempt", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get recorded otp attempt count", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteOtpTokenById fails with ErrOptTokenNotFound if the token was already used, which
+// keeps an OTP from being used twice.
 func (br *bookingRepository) DeleteOtpTokenById(ctx context.Context, tx *sql.Tx, otpTokenId int) error {
 	executer := br.initiateQueryExecuter(tx)
 
-	_, err := executer.ExecContext(ctx, deleteOtpTokenByIdQuery, otpTokenId)
+	result, err := executer.ExecContext(ctx, deleteOtpTokenByIdQuery, otpTokenId)
 	if err != nil {
 		slog.Error("failed to delete opt token", "error", err)
 		return apperrors.ErrInternalServer
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get deleted otp token count", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.ErrOptTokenNotFound
+	}
+
 	return nil
 }
 
-func (br *bookingRepository) UpdateBookingStatus(ctx context.Context, tx *sql.Tx, bookingId int, status string) error {
+func (br *bookingRepository) UpdateBookingStatus(ctx context.Context, tx *sql.Tx, bookingId int, fromStatus, toStatus string) error {
 	executer := br.initiateQueryExecuter(tx)
 
-	_, err := executer.ExecContext(ctx, updateBookingStatusQuery, status, bookingId)
+	result, err := executer.ExecContext(ctx, updateBookingStatusQuery, toStatus, bookingId, fromStatus)
 	if err != nil {
 		slog.Error("failed to update booking status", "error", err)
 		return apperrors.ErrInternalServer
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get updated booking count", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		slog.Error("booking status changed concurrently", "bookingId", bookingId, "fromStatus", fromStatus, "toStatus", toStatus)
+		return apperrors.ErrInvalidBookingTransition
+	}
+
 	return nil
 }
 
+func (br *bookingRepository) CreateBookingStatusHistory(ctx context.Context, tx *sql.Tx, historyData BookingStatusHistory) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(
+		ctx,
+		createBookingStatusHistoryQuery,
+		historyData.BookingId,
+		historyData.FromStatus,
+		historyData.ToStatus,
+		historyData.ActorId,
+		historyData.ActorRole,
+		historyData.Reason,
+	)
+	if err != nil {
+		slog.Error("failed to create booking status history", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) GetBookingStatusHistory(ctx context.Context, tx *sql.Tx, bookingId int) ([]BookingStatusHistory, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	var history []BookingStatusHistory
+	rows, err := executer.QueryContext(ctx, getBookingStatusHistoryQuery, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking status history", "error", err)
+		return []BookingStatusHistory{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var historyData BookingStatusHistory
+		err = rows.Scan(
+			&historyData.Id,
+			&historyData.BookingId,
+			&historyData.FromStatus,
+			&historyData.ToStatus,
+			&historyData.ActorId,
+			&historyData.ActorRole,
+			&historyData.Reason,
+			&historyData.CreatedAt,
+		)
+		if err != nil {
+			slog.Error("failed to scan booking status history from rows", "error", err)
+			return []BookingStatusHistory{}, apperrors.ErrInternalServer
+		}
+		history = append(history, historyData)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed iterate over booking status history rows", "error", err)
+		return []BookingStatusHistory{}, apperrors.ErrInternalServer
+	}
+
+	return history, nil
+}
+
 func (br *bookingRepository) UpdateActualPickupTime(ctx context.Context, tx *sql.Tx, bookingId int) error {
 	executer := br.initiateQueryExecuter(tx)
 
@@ -374,24 +667,7 @@ func (br *bookingRepository) GetBookingById(ctx context.Context, tx *sql.Tx, boo
 		ctx,
 		getBookingById,
 		bookingId,
-	).Scan(
-		&booking.Id,
-		&booking.VehicleId,
-		&booking.HostId,
-		&booking.SeekerId,
-		&booking.Status,
-		&booking.PickupLocation,
-		&booking.DropoffLocation,
-		&booking.BookingAmount,
-		&booking.OverdueFeeRatePerHour,
-		&booking.CancellationAllowed,
-		&booking.ActualPickupTime,
-		&booking.ActualDropoffTime,
-		&booking.ScheduledPickupTime,
-		&booking.ScheduledDropoffTime,
-		&booking.CreatedAt,
-		&booking.UpdatedAt,
-	)
+	).Scan(bookingScanFields(&booking)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Booking{}, apperrors.ErrBookingNotFound
@@ -432,110 +708,136 @@ func (br *bookingRepository) CreateInvoice(ctx context.Context, tx *sql.Tx, invo
 	return invoice, nil
 }
 
-func (br *bookingRepository) GetSeekerBookings(ctx context.Context, tx *sql.Tx, params GetSeekerBookingsParams) ([]BookingData, int, error) {
+// Booking list sort orders
+const (
+	BookingSortNewest     = "newest"
+	BookingSortOldest     = "oldest"
+	BookingSortPickupAsc  = "pickup_asc"
+	BookingSortPickupDesc = "pickup_desc"
+)
+
+// Scheduled times a booking list can be filtered on
+const (
+	BookingTimePickup  = "pickup"
+	BookingTimeDropoff = "dropoff"
+)
+
+var hostCancellationsOrder = sortOrder{
+	{expression: "c.created_at", descending: true, kind: sortKeyTime},
+	{expression: "c.id", descending: true, kind: sortKeyInt},
+}
+
+var bookingSortOrders = map[string]sortOrder{
+	BookingSortNewest: {
+		{expression: "b.created_at", descending: true, kind: sortKeyTime},
+		{expression: "b.id", descending: true, kind: sortKeyInt},
+	},
+	BookingSortOldest: {
+		{expression: "b.created_at", kind: sortKeyTime},
+		{expression: "b.id", kind: sortKeyInt},
+	},
+	BookingSortPickupAsc: {
+		{expression: "b.scheduled_pickup_time", kind: sortKeyTime},
+		{expression: "b.id", kind: sortKeyInt},
+	},
+	BookingSortPickupDesc: {
+		{expression: "b.scheduled_pickup_time", descending: true, kind: sortKeyTime},
+		{expression: "b.id", descending: true, kind: sortKeyInt},
+	},
+}
+
+var bookingTimeColumns = map[string]string{
+	BookingTimePickup:  "b.scheduled_pickup_time",
+	BookingTimeDropoff: "b.scheduled_dropoff_time",
+}
+
+func (br *bookingRepository) GetSeekerBookings(ctx context.Context, tx *sql.Tx, params GetSeekerBookingsParams) ([]BookingData, pagination.Info, error) {
 	executer := br.initiateQueryExecuter(tx)
 
-	var bookings []BookingData
-	var totalCount int
-	rows, err := executer.QueryContext(
-		ctx,
-		getSeekerBookingsQuery,
-		params.SeekerId,
-		params.Offset,
-		params.Limit,
-	)
+	var qb queryBuilder
+	qb.where("b.seeker_id = " + qb.arg(params.SeekerId))
+	addBookingFilters(&qb, params.Filters)
+	lq := listQuery{columns: bookingDataColumns, from: bookingDataFrom, order: bookingListOrder(params.Sort), qb: qb}
+
+	bookings, pageInfo, err := fetchPage(ctx, executer, lq, params.Page, scanBookingData)
 	if err != nil {
 		slog.Error("failed to get bookings for seeker", "error", err)
-		return []BookingData{}, 0, apperrors.ErrInternalServer
+		return []BookingData{}, pagination.Info{}, err
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		var bookingData BookingData
-		err = rows.Scan(
-			&bookingData.Id,
-			&bookingData.Status,
-			&bookingData.PickupLocation,
-			&bookingData.DropoffLocation,
-			&bookingData.BookingAmount,
-			&bookingData.OverdueFeeRatePerHour,
-			&bookingData.CancellationAllowed,
-			&bookingData.ScheduledPickupTime,
-			&bookingData.ScheduledDropoffTime,
-			&bookingData.VehicleName,
-			&bookingData.VehicleSeatCount,
-			&bookingData.VehicleFuelType,
-			&bookingData.VehicleTransmissionType,
-			&bookingData.VehicleImage,
-			&totalCount,
-		)
-		if err != nil {
-			slog.Error("failed to scan booking data from rows for seeker", "error", err)
-			return []BookingData{}, 0, apperrors.ErrInternalServer
-		}
-		bookings = append(bookings, bookingData)
+	return bookings, pageInfo, nil
+}
+
+func (br *bookingRepository) GetHostBookings(ctx context.Context, tx *sql.Tx, params GetHostBookingsParams) ([]BookingData, pagination.Info, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	var qb queryBuilder
+	qb.where("b.host_id = " + qb.arg(params.HostId))
+	if params.VehicleId > 0 {
+		qb.where("b.vehicle_id = " + qb.arg(params.VehicleId))
 	}
+	addBookingFilters(&qb, params.Filters)
+	lq := listQuery{columns: bookingDataColumns, from: bookingDataFrom, order: bookingListOrder(params.Sort), qb: qb}
 
-	err = rows.Err()
+	bookings, pageInfo, err := fetchPage(ctx, executer, lq, params.Page, scanBookingData)
 	if err != nil {
-		slog.Error("failed iterate over booking data rows for seeker", "error", err)
-		return []BookingData{}, 0, apperrors.ErrInternalServer
+		slog.Error("failed to get bookings for host", "error", err)
+		return []BookingData{}, pagination.Info{}, err
 	}
 
-	return bookings, totalCount, nil
+	return bookings, pageInfo, nil
 }
 
-func (br *bookingRepository) GetHostBookings(ctx context.Context, tx *sql.Tx, params GetHostBookingsParams) ([]BookingData, int, error) {
-	executer := br.initiateQueryExecuter(tx)
-
-	var bookings []BookingData
-	var totalCount int
-	rows, err := executer.QueryContext(
-		ctx,
-		getHostBookingsQuery,
-		params.HostId,
-		params.Offset,
-		params.Limit,
-	)
-	if err != nil {
-		slog.Error("failed to get bookings for host", "error", err)
-		return []BookingData{}, 0, apperrors.ErrInternalServer
+func addBookingFilters(qb *queryBuilder, filters BookingFilters) {
+	if len(filters.Statuses) > 0 {
+		qb.where("b.status = ANY(" + qb.arg(pq.Array(filters.Statuses)) + ")")
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		var bookingData BookingData
-		err = rows.Scan(
-			&bookingData.Id,
-			&bookingData.Status,
-			&bookingData.PickupLocation,
-			&bookingData.DropoffLocation,
-			&bookingData.BookingAmount,
-			&bookingData.OverdueFeeRatePerHour,
-			&bookingData.CancellationAllowed,
-			&bookingData.ScheduledPickupTime,
-			&bookingData.ScheduledDropoffTime,
-			&bookingData.VehicleName,
-			&bookingData.VehicleSeatCount,
-			&bookingData.VehicleFuelType,
-			&bookingData.VehicleTransmissionType,
-			&bookingData.VehicleImage,
-			&totalCount,
-		)
-		if err != nil {
-			slog.Error("failed to scan booking data from rows for host", "error", err)
-			return []BookingData{}, 0, apperrors.ErrInternalServer
-		}
-		bookings = append(bookings, bookingData)
+	column, ok := bookingTimeColumns[filters.TimeField]
+	if !ok {
+		column = bookingTimeColumns[BookingTimePickup]
+	}
+	if filters.From != nil {
+		qb.where(column + " >= " + qb.arg(*filters.From))
 	}
+	if filters.To != nil {
+		qb.where(column + " <= " + qb.arg(*filters.To))
+	}
+}
 
-	err = rows.Err()
-	if err != nil {
-		slog.Error("failed iterate over booking data rows for host", "error", err)
-		return []BookingData{}, 0, apperrors.ErrInternalServer
+// bookingListOrder falls back to the newest bookings first; the service validates the sort.
+func bookingListOrder(sort string) sortOrder {
+	order, ok := bookingSortOrders[sort]
+	if !ok {
+		return bookingSortOrders[BookingSortNewest]
 	}
 
-	return bookings, totalCount, nil
+	return order
+}
+
+func scanBookingData(rows *sql.Rows, key *[]byte) (BookingData, error) {
+	var bookingData BookingData
+	err := rows.Scan(
+		&bookingData.Id,
+		&bookingData.Status,
+		&bookingData.PickupLocation,
+		&bookingData.DropoffLocation,
+		&bookingData.BookingAmount,
+		&bookingData.OverdueFeeRatePerHour,
+		&bookingData.CancellationAllowed,
+		&bookingData.ScheduledPickupTime,
+		&bookingData.ScheduledDropoffTime,
+		&bookingData.NoShowAt,
+		&bookingData.OverdueAt,
+		&bookingData.VehicleName,
+		&bookingData.VehicleSeatCount,
+		&bookingData.VehicleFuelType,
+		&bookingData.VehicleTransmissionType,
+		&bookingData.VehicleImage,
+		key,
+	)
+
+	return bookingData, err
 }
 
 func (br *bookingRepository) GetBookingDetailsById(ctx context.Context, tx *sql.Tx, bookingId int) (BookingDetails, error) {
@@ -558,10 +860,13 @@ func (br *bookingRepository) GetBookingDetailsById(ctx context.Context, tx *sql.
 		&booking.BookingAmount,
 		&booking.OverdueFeeRatePerHour,
 		&booking.CancellationAllowed,
+		&booking.CancellationPolicy,
 		&booking.ActualPickupTime,
 		&booking.ActualDropoffTime,
 		&booking.ScheduledPickupTime,
 		&booking.ScheduledDropoffTime,
+		&booking.NoShowAt,
+		&booking.OverdueAt,
 		&host.Id,
 		&host.Name,
 		&host.Email,
@@ -598,3 +903,451 @@ func (br *bookingRepository) GetBookingDetailsById(ctx context.Context, tx *sql.
 
 	return booking, nil
 }
+
+func (br *bookingRepository) UpdateBookingSchedule(ctx context.Context, tx *sql.Tx, scheduleData BookingScheduleUpdate) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(
+		ctx,
+		updateBookingScheduleQuery,
+		scheduleData.BookingId,
+		scheduleData.PickupTime,
+		scheduleData.DropoffTime,
+		scheduleData.BookingAmount,
+		scheduleData.PreviousPickupTime,
+		scheduleData.PreviousDropoffTime,
+	)
+	if err != nil {
+		if isConstraintViolation(err, pqExclusionViolation, "bookings_no_overlap") {
+			slog.Error("new booking schedule overlaps with an existing booking for the vehicle", "error", err)
+			return apperrors.ErrBookingConflict
+		}
+		slog.Error("failed to update booking schedule", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get updated booking count", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		slog.Error("booking changed concurrently", "bookingId", scheduleData.BookingId)
+		return apperrors.ErrBookingNotChangeable
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) UpdateOtpTokenExpiry(ctx context.Context, tx *sql.Tx, bookingId int, fromExpiry, toExpiry time.Time) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateOtpTokenExpiryQuery, bookingId, fromExpiry, toExpiry)
+	if err != nil {
+		slog.Error("failed to update otp token expiry", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) CreateBookingChangeRequest(ctx context.Context, tx *sql.Tx, requestData CreateBookingChangeRequestData) (BookingChangeRequest, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	changeRequest, err := scanBookingChangeRequest(executer.QueryRowContext(
+		ctx,
+		createBookingChangeRequestQuery,
+		requestData.BookingId,
+		requestData.Type,
+		requestData.Status,
+		requestData.PreviousPickupTime,
+		requestData.PreviousDropoffTime,
+		requestData.PreviousAmount,
+		requestData.NewPickupTime,
+		requestData.NewDropoffTime,
+		requestData.NewAmount,
+		requestData.RequestedBy,
+		requestData.DecidedBy,
+		requestData.Reason,
+	))
+	if err != nil {
+		if isConstraintViolation(err, pqUniqueViolation, "booking_change_requests_pending_idx") {
+			slog.Error("booking already has a pending change request", "bookingId", requestData.BookingId)
+			return BookingChangeRequest{}, apperrors.ErrBookingChangePending
+		}
+		slog.Error("failed to create booking change request", "error", err)
+		return BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	return changeRequest, nil
+}
+
+func (br *bookingRepository) GetBookingChangeRequestById(ctx context.Context, tx *sql.Tx, requestId int) (BookingChangeRequest, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	changeRequest, err := scanBookingChangeRequest(executer.QueryRowContext(ctx, getBookingChangeRequestByIdQuery, requestId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BookingChangeRequest{}, apperrors.ErrBookingChangeNotFound
+		}
+		slog.Error("failed to get booking change request", "error", err)
+		return BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	return changeRequest, nil
+}
+
+func (br *bookingRepository) GetBookingChangeRequests(ctx context.Context, tx *sql.Tx, bookingId int) ([]BookingChangeRequest, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getBookingChangeRequestsQuery, bookingId)
+	if err != nil {
+		slog.Error("failed to get booking change requests", "error", err)
+		return []BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	changeRequests := []BookingChangeRequest{}
+	for rows.Next() {
+		changeRequest, err := scanBookingChangeRequest(rows)
+		if err != nil {
+			slog.Error("failed to scan booking change request", "error", err)
+			return []BookingChangeRequest{}, apperrors.ErrInternalServer
+		}
+		changeRequests = append(changeRequests, changeRequest)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over booking change request rows", "error", err)
+		return []BookingChangeRequest{}, apperrors.ErrInternalServer
+	}
+
+	return changeRequests, nil
+}
+
+func (br *bookingRepository) DecideBookingChangeRequest(ctx context.Context, tx *sql.Tx, requestId int, status string, decidedBy int, reason string) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, decideBookingChangeRequestQuery, requestId, status, decidedBy, reason)
+	if err != nil {
+		slog.Error("failed to decide booking change request", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get decided booking change request count", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		slog.Error("booking change request is no longer pending", "requestId", requestId)
+		return apperrors.ErrBookingChangeDecided
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) DeclinePendingBookingChangeRequests(ctx context.Context, tx *sql.Tx, bookingId int, reason string) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, declinePendingBookingChangeRequestsQuery, bookingId, reason)
+	if err != nil {
+		slog.Error("failed to decline pending booking change requests", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func scanBookingChangeRequest(row interface{ Scan(dest ...any) error }) (BookingChangeRequest, error) {
+	var changeRequest BookingChangeRequest
+	err := row.Scan(
+		&changeRequest.Id,
+		&changeRequest.BookingId,
+		&changeRequest.Type,
+		&changeRequest.Status,
+		&changeRequest.PreviousPickupTime,
+		&changeRequest.PreviousDropoffTime,
+		&changeRequest.PreviousAmount,
+		&changeRequest.NewPickupTime,
+		&changeRequest.NewDropoffTime,
+		&changeRequest.NewAmount,
+		&changeRequest.RequestedBy,
+		&changeRequest.DecidedBy,
+		&changeRequest.Reason,
+		&changeRequest.CreatedAt,
+		&changeRequest.DecidedAt,
+	)
+
+	return changeRequest, err
+}
+
+func (br *bookingRepository) CreateBookingCancellation(ctx context.Context, tx *sql.Tx, cancellationData CreateBookingCancellationData) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(
+		ctx,
+		createBookingCancellationQuery,
+		cancellationData.BookingId,
+		cancellationData.HostId,
+		cancellationData.CancelledBy,
+		cancellationData.InitiatedBy,
+		cancellationData.Policy,
+		cancellationData.HoursBeforePickup,
+		cancellationData.BookingAmount,
+		cancellationData.RefundPercent,
+		cancellationData.RefundAmount,
+		cancellationData.CancellationFee,
+	)
+	if err != nil {
+		slog.Error("failed to create booking cancellation", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) GetBookingCancellation(ctx context.Context, tx *sql.Tx, bookingId int) (BookingCancellation, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	cancellation, err := scanBookingCancellation(executer.QueryRowContext(ctx, getBookingCancellationQuery, bookingId), nil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BookingCancellation{}, apperrors.ErrBookingCancellationNotFound
+		}
+		slog.Error("failed to get booking cancellation", "error", err)
+		return BookingCancellation{}, apperrors.ErrInternalServer
+	}
+
+	return cancellation, nil
+}
+
+// GetHostCancellations lists the cancellations a host initiated, newest first.
+func (br *bookingRepository) GetHostCancellations(ctx context.Context, tx *sql.Tx, params GetHostCancellationsParams) ([]BookingCancellation, pagination.Info, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	lq := listQuery{columns: bookingCancellationColumns, from: "booking_cancellations c", order: hostCancellationsOrder}
+	lq.qb.where("c.host_id = " + lq.qb.arg(params.HostId))
+	lq.qb.where("c.initiated_by = 'HOST'")
+
+	return fetchPage(ctx, executer, lq, params.Page, func(rows *sql.Rows, key *[]byte) (BookingCancellation, error) {
+		return scanBookingCancellation(rows, key)
+	})
+}
+
+// scanBookingCancellation reads a row in booking_cancellations column order, followed by the
+// row's sort key when key is not nil.
+func scanBookingCancellation(row interface{ Scan(dest ...any) error }, key *[]byte) (BookingCancellation, error) {
+	var cancellation BookingCancellation
+	fields := []any{
+		&cancellation.Id,
+		&cancellation.BookingId,
+		&cancellation.HostId,
+		&cancellation.CancelledBy,
+		&cancellation.InitiatedBy,
+		&cancellation.Policy,
+		&cancellation.HoursBeforePickup,
+		&cancellation.BookingAmount,
+		&cancellation.RefundPercent,
+		&cancellation.RefundAmount,
+		&cancellation.CancellationFee,
+		&cancellation.CreatedAt,
+	}
+	if key != nil {
+		fields = append(fields, key)
+	}
+
+	err := row.Scan(fields...)
+	return cancellation, err
+}
+
+// bookingScanFields lists scan destinations in bookings column order.
+func bookingScanFields(booking *Booking) []any {
+	return []any{
+		&booking.Id,
+		&booking.VehicleId,
+		&booking.HostId,
+		&booking.SeekerId,
+		&booking.Status,
+		&booking.PickupLocation,
+		&booking.DropoffLocation,
+		&booking.BookingAmount,
+		&booking.OverdueFeeRatePerHour,
+		&booking.CancellationAllowed,
+		&booking.ActualPickupTime,
+		&booking.ActualDropoffTime,
+		&booking.ScheduledPickupTime,
+		&booking.ScheduledDropoffTime,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+		&booking.CancellationPolicy,
+		&booking.NoShowAt,
+		&booking.OverdueAt,
+	}
+}
+
+func (br *bookingRepository) GetNoShowBookings(ctx context.Context, tx *sql.Tx, pickupBefore time.Time, limit int) ([]Booking, error) {
+	return br.getBookings(ctx, tx, getNoShowBookingsQuery, pickupBefore, limit)
+}
+
+func (br *bookingRepository) GetOverdueBookings(ctx context.Context, tx *sql.Tx, dropoffBefore time.Time, limit int) ([]Booking, error) {
+	return br.getBookings(ctx, tx, getOverdueBookingsQuery, dropoffBefore, limit)
+}
+
+func (br *bookingRepository) getBookings(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]Booking, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to get bookings", "error", err)
+		return []Booking{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	bookings := []Booking{}
+	for rows.Next() {
+		var booking Booking
+		err = rows.Scan(bookingScanFields(&booking)...)
+		if err != nil {
+			slog.Error("failed to scan booking", "error", err)
+			return []Booking{}, apperrors.ErrInternalServer
+		}
+		bookings = append(bookings, booking)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over booking rows", "error", err)
+		return []Booking{}, apperrors.ErrInternalServer
+	}
+
+	return bookings, nil
+}
+
+func (br *bookingRepository) FlagBookingNoShow(ctx context.Context, tx *sql.Tx, bookingId int) (bool, error) {
+	return br.flagBooking(ctx, tx, flagBookingNoShowQuery, bookingId)
+}
+
+func (br *bookingRepository) FlagBookingOverdue(ctx context.Context, tx *sql.Tx, bookingId int) (bool, error) {
+	return br.flagBooking(ctx, tx, flagBookingOverdueQuery, bookingId)
+}
+
+// flagBooking reports whether the flag was set by this call, as opposed to having been set
+// before or no longer applying.
+func (br *bookingRepository) flagBooking(ctx context.Context, tx *sql.Tx, query string, bookingId int) (bool, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, query, bookingId)
+	if err != nil {
+		slog.Error("failed to flag booking", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get flagged booking count", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (br *bookingRepository) GetBookingVehicleName(ctx context.Context, tx *sql.Tx, bookingId int) (string, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	var vehicleName string
+	err := executer.QueryRowContext(ctx, getBookingVehicleNameQuery, bookingId).Scan(&vehicleName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrBookingNotFound
+		}
+		slog.Error("failed to get booking vehicle name", "error", err)
+		return "", apperrors.ErrInternalServer
+	}
+
+	return vehicleName, nil
+}
+
+func (br *bookingRepository) CreateBookingReminder(ctx context.Context, tx *sql.Tx, reminderData CreateBookingReminderData) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createBookingReminderQuery, reminderData.BookingId, reminderData.Kind, reminderData.RemindAt)
+	if err != nil {
+		slog.Error("failed to create booking reminder", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) CancelBookingReminders(ctx context.Context, tx *sql.Tx, bookingId int, kinds []string) error {
+	executer := br.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, cancelBookingRemindersQuery, bookingId, pq.Array(kinds))
+	if err != nil {
+		slog.Error("failed to cancel booking reminders", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (br *bookingRepository) GetDueBookingReminders(ctx context.Context, tx *sql.Tx, dueBefore time.Time, limit int) ([]BookingReminder, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getDueBookingRemindersQuery, dueBefore, limit)
+	if err != nil {
+		slog.Error("failed to get due booking reminders", "error", err)
+		return []BookingReminder{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	reminders := []BookingReminder{}
+	for rows.Next() {
+		var reminder BookingReminder
+		err = rows.Scan(
+			&reminder.Id,
+			&reminder.BookingId,
+			&reminder.Kind,
+			&reminder.RemindAt,
+			&reminder.Status,
+			&reminder.CreatedAt,
+			&reminder.UpdatedAt,
+		)
+		if err != nil {
+			slog.Error("failed to scan booking reminder", "error", err)
+			return []BookingReminder{}, apperrors.ErrInternalServer
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over booking reminder rows", "error", err)
+		return []BookingReminder{}, apperrors.ErrInternalServer
+	}
+
+	return reminders, nil
+}
+
+// UpdateBookingReminderStatus reports whether the reminder was still pending.
+func (br *bookingRepository) UpdateBookingReminderStatus(ctx context.Context, tx *sql.Tx, reminderId int, status string) (bool, error) {
+	executer := br.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, updateBookingReminderStatusQuery, reminderId, status)
+	if err != nil {
+		slog.Error("failed to update booking reminder status", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get updated booking reminder count", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return rowsAffected > 0, nil
+}
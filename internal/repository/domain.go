@@ -3,26 +3,30 @@ package repository
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
 )
 
 type User struct {
-	Id          int
-	Name        string
-	Email       string
-	PhoneNumber string
-	Password    string
-	Role        string
-	IsVerified  bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Id                int
+	Name              string
+	Email             string
+	PhoneNumber       string
+	Password          string
+	Role              string
+	IsVerified        bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	PreferredLanguage string
 }
 
 type CreateUserRequestBody struct {
-	Name        string
-	Email       string
-	PhoneNumber string
-	Password    string
-	Role        string
+	Name              string
+	Email             string
+	PhoneNumber       string
+	Password          string
+	Role              string
+	PreferredLanguage string
 }
 
 type VerificationToken struct {
@@ -33,6 +37,18 @@ type VerificationToken struct {
 	ExpiresAt time.Time
 }
 
+type UserSession struct {
+	Id               string
+	UserId           int
+	RefreshTokenHash string
+	UserAgent        string
+	IpAddress        string
+	ExpiresAt        time.Time
+	RevokedAt        *time.Time
+	LastUsedAt       time.Time
+	CreatedAt        time.Time
+}
+
 type Vehicle struct {
 	Id                    int
 	Name                  string
@@ -47,19 +63,32 @@ type Vehicle struct {
 	City                  string
 	PinCode               int
 	CancellationAllowed   bool
+	CancellationPolicy    string
 	Available             bool
 	HostId                int
 	IsDeleted             bool
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
+	Latitude              *float64
+	Longitude             *float64
 }
 
 type VehicleImage struct {
-	Id        int
-	VehicleId int
-	Url       string
-	Featured  bool
-	CreatedAt time.Time
+	Id                      int
+	VehicleId               int
+	Url                     string
+	Featured                bool
+	CreatedAt               time.Time
+	ObjectPath              *string
+	UploadId                *int
+	ThumbnailPath           *string
+	MediumPath              *string
+	LargePath               *string
+	ProcessingStatus        string
+	ProcessingAttempts      int
+	ProcessingNextAttemptAt time.Time
+	ProcessingError         string
+	Position                int
 }
 
 type CreateVehicleRequestBody struct {
@@ -75,7 +104,10 @@ type CreateVehicleRequestBody struct {
 	City                  string
 	PinCode               int
 	CancellationAllowed   bool
+	CancellationPolicy    string
 	HostId                int
+	Latitude              *float64
+	Longitude             *float64
 }
 
 type EditVehicleRequestBody struct {
@@ -92,12 +124,50 @@ type EditVehicleRequestBody struct {
 	City                  string
 	PinCode               int
 	CancellationAllowed   bool
+	CancellationPolicy    string
+	HostId                int
+	Latitude              *float64
+	Longitude             *float64
 }
 
 type CreateVehicleImageData struct {
+	VehicleId        int
+	Url              string
+	Featured         bool
+	ObjectPath       *string
+	UploadId         *int
+	ThumbnailPath    *string
+	MediumPath       *string
+	LargePath        *string
+	ProcessingStatus string
+	Position         int
+}
+
+type VehicleImageVariants struct {
+	ThumbnailPath string
+	MediumPath    string
+	LargePath     string
+}
+
+type VehicleBlackout struct {
+	Id        int
 	VehicleId int
-	Url       string
-	Featured  bool
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+	CreatedAt time.Time
+}
+
+type CreateVehicleBlackoutData struct {
+	VehicleId int
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+}
+
+type BusyInterval struct {
+	StartsAt time.Time
+	EndsAt   time.Time
 }
 
 type VehicleOverview struct {
@@ -110,20 +180,45 @@ type VehicleOverview struct {
 	RatePerHour      float64
 	Address          string
 	PinCode          int
+	DistanceKm       *float64
+}
+
+type VehicleSuggestion struct {
+	Kind  string
+	Value string
+}
+
+type GeoRadius struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+type VehicleSearchFilters struct {
+	FuelTypes           []string
+	TransmissionTypes   []string
+	MinSeatCount        int
+	MinRatePerHour      *float64
+	MaxRatePerHour      *float64
+	CancellationAllowed *bool
+	Features            []string
 }
 
 type GetVehiclesParams struct {
 	City             string
+	SearchTerms      []string
+	Near             *GeoRadius
+	Filters          VehicleSearchFilters
+	Sort             string
 	PickupTimestamp  time.Time
 	DropoffTimestamp time.Time
-	Offset           int
-	Limit            int
+	Page             pagination.Params
+	BookingBuffer    time.Duration
 }
 
 type GetVehiclesForHostParams struct {
 	HostId int
-	Offset int
-	Limit  int
+	Page   pagination.Params
 }
 
 type Booking struct {
@@ -137,12 +232,15 @@ type Booking struct {
 	BookingAmount         float64
 	OverdueFeeRatePerHour float64
 	CancellationAllowed   bool
+	CancellationPolicy    string
 	ActualPickupTime      *time.Time
 	ActualDropoffTime     *time.Time
 	ScheduledPickupTime   time.Time
 	ScheduledDropoffTime  time.Time
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
+	NoShowAt              *time.Time
+	OverdueAt             *time.Time
 }
 
 type CreateBookingRequestBody struct {
@@ -155,15 +253,123 @@ type CreateBookingRequestBody struct {
 	BookingAmount         float64
 	OverdueFeeRatePerHour float64
 	CancellationAllowed   bool
+	CancellationPolicy    string
 	ScheduledPickupTime   time.Time
 	ScheduledDropoffTime  time.Time
 }
 
+type BookingStatusHistory struct {
+	Id         int
+	BookingId  int
+	FromStatus *string
+	ToStatus   string
+	ActorId    *int
+	ActorRole  string
+	Reason     string
+	CreatedAt  time.Time
+}
+
+type BookingChangeRequest struct {
+	Id                  int
+	BookingId           int
+	Type                string
+	Status              string
+	PreviousPickupTime  time.Time
+	PreviousDropoffTime time.Time
+	PreviousAmount      float64
+	NewPickupTime       time.Time
+	NewDropoffTime      time.Time
+	NewAmount           float64
+	RequestedBy         int
+	DecidedBy           *int
+	Reason              string
+	CreatedAt           time.Time
+	DecidedAt           *time.Time
+}
+
+type CreateBookingChangeRequestData struct {
+	BookingId           int
+	Type                string
+	Status              string
+	PreviousPickupTime  time.Time
+	PreviousDropoffTime time.Time
+	PreviousAmount      float64
+	NewPickupTime       time.Time
+	NewDropoffTime      time.Time
+	NewAmount           float64
+	RequestedBy         int
+	DecidedBy           *int
+	Reason              string
+}
+
+// BookingScheduleUpdate moves a booking from its previous schedule to a new one. The update
+// only applies while the booking still has the previous schedule and is active.
+type BookingScheduleUpdate struct {
+	BookingId           int
+	PreviousPickupTime  time.Time
+	PreviousDropoffTime time.Time
+	PickupTime          time.Time
+	DropoffTime         time.Time
+	BookingAmount       float64
+}
+
+type BookingCancellation struct {
+	Id                int
+	BookingId         int
+	HostId            int
+	CancelledBy       *int
+	InitiatedBy       string
+	Policy            string
+	HoursBeforePickup float64
+	BookingAmount     float64
+	RefundPercent     int
+	RefundAmount      float64
+	CancellationFee   float64
+	CreatedAt         time.Time
+}
+
+type CreateBookingCancellationData struct {
+	BookingId         int
+	HostId            int
+	CancelledBy       *int
+	InitiatedBy       string
+	Policy            string
+	HoursBeforePickup float64
+	BookingAmount     float64
+	RefundPercent     int
+	RefundAmount      float64
+	CancellationFee   float64
+}
+
+type BookingReminder struct {
+	Id        int
+	BookingId int
+	Kind      string
+	RemindAt  time.Time
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CreateBookingReminderData struct {
+	BookingId int
+	Kind      string
+	RemindAt  time.Time
+}
+
+type GetHostCancellationsParams struct {
+	HostId int
+	Page   pagination.Params
+}
+
 type OtpToken struct {
 	Id        int
 	BookingId int
-	Otp       string
+	Purpose   string
+	OtpHash   string
+	Attempts  int
 	ExpiresAt time.Time
+	CreatedAt time.Time
 }
 
 type Invoice struct {
@@ -186,6 +392,8 @@ type BookingData struct {
 	CancellationAllowed     bool
 	ScheduledPickupTime     time.Time
 	ScheduledDropoffTime    time.Time
+	NoShowAt                *time.Time
+	OverdueAt               *time.Time
 	VehicleName             string
 	VehicleSeatCount        int
 	VehicleFuelType         string
@@ -193,16 +401,26 @@ type BookingData struct {
 	VehicleImage            string
 }
 
+type BookingFilters struct {
+	Statuses  []string
+	TimeField string
+	From      *time.Time
+	To        *time.Time
+}
+
 type GetSeekerBookingsParams struct {
 	SeekerId int
-	Offset   int
-	Limit    int
+	Filters  BookingFilters
+	Sort     string
+	Page     pagination.Params
 }
 
 type GetHostBookingsParams struct {
-	HostId int
-	Offset int
-	Limit  int
+	HostId    int
+	VehicleId int
+	Filters   BookingFilters
+	Sort      string
+	Page      pagination.Params
 }
 
 type BookingDetails struct {
@@ -213,10 +431,13 @@ type BookingDetails struct {
 	BookingAmount         float64
 	OverdueFeeRatePerHour float64
 	CancellationAllowed   bool
+	CancellationPolicy    string
 	ActualPickupTime      *time.Time
 	ActualDropoffTime     *time.Time
 	ScheduledPickupTime   time.Time
 	ScheduledDropoffTime  time.Time
+	NoShowAt              *time.Time
+	OverdueAt             *time.Time
 	Host                  BookingDetailsUser
 	Seeker                BookingDetailsUser
 	Vehicle               BookingDetailsVehicle
@@ -246,3 +467,54 @@ type BookingDetailsInvoice struct {
 	TaxRate        float64
 	TotalAmount    float64
 }
+
+type OutboxEmail struct {
+	Id               int
+	ToName           string
+	ToEmail          string
+	Subject          string
+	PlainTextContent string
+	HTMLContent      string
+	Sensitive        bool
+	Status           string
+	Attempts         int
+	MaxAttempts      int
+	NextAttemptAt    time.Time
+	LastError        string
+	SentAt           *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type CreateOutboxEmailData struct {
+	ToName           string
+	ToEmail          string
+	Subject          string
+	PlainTextContent string
+	HTMLContent      string
+	MaxAttempts      int
+	Sensitive        bool
+}
+
+type GetOutboxEmailsParams struct {
+	Status string
+	Page   pagination.Params
+}
+
+type Upload struct {
+	Id          int
+	UserId      int
+	ObjectPath  string
+	ContentType string
+	Status      string
+	ExpiresAt   time.Time
+	LinkedAt    *time.Time
+	CreatedAt   time.Time
+}
+
+type CreateUploadData struct {
+	UserId      int
+	ObjectPath  string
+	ContentType string
+	ExpiresAt   time.Time
+}
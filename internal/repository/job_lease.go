@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+type jobLeaseRepository struct {
+	BaseRepository
+}
+
+type JobLeaseRepository interface {
+	RepositoryTransaction
+	AcquireJobLease(ctx context.Context, tx *sql.Tx, name, owner string, leaseDuration time.Duration) (bool, error)
+}
+
+func NewJobLeaseRepository(db *sql.DB) JobLeaseRepository {
+	return &jobLeaseRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	// The lease is taken when it is free or expired, and renewed when the owner already holds it.
+	// A lease held by someone else leaves the row untouched, so nothing is returned.
+	acquireJobLeaseQuery = `
+	INSERT INTO job_leases (name, owner, leased_until)
+	VALUES ($1, $2, CURRENT_TIMESTAMP + make_interval(secs => $3))
+	ON CONFLICT (name) DO UPDATE
+	SET owner = EXCLUDED.owner, leased_until = EXCLUDED.leased_until
+	WHERE job_leases.owner = EXCLUDED.owner OR job_leases.leased_until <= CURRENT_TIMESTAMP
+	RETURNING owner;`
+)
+
+func (jr *jobLeaseRepository) AcquireJobLease(ctx context.Context, tx *sql.Tx, name, owner string, leaseDuration time.Duration) (bool, error) {
+	executer := jr.initiateQueryExecuter(tx)
+
+	var leaseOwner string
+	err := executer.QueryRowContext(ctx, acquireJobLeaseQuery, name, owner, leaseDuration.Seconds()).Scan(&leaseOwner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		slog.Error("failed to acquire job lease", "job", name, "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return true, nil
+}
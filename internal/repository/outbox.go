@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+)
+
+type outboxRepository struct {
+	BaseRepository
+}
+
+type OutboxRepository interface {
+	RepositoryTransaction
+	CreateOutboxEmail(ctx context.Context, tx *sql.Tx, emailData CreateOutboxEmailData) error
+	ClaimDueOutboxEmails(ctx context.Context, tx *sql.Tx, limit int, leaseDuration time.Duration) ([]OutboxEmail, error)
+	MarkOutboxEmailSent(ctx context.Context, tx *sql.Tx, emailId int) error
+	MarkOutboxEmailFailed(ctx context.Context, tx *sql.Tx, emailId int, status string, nextAttemptAt time.Time, lastError string) error
+	GetOutboxEmails(ctx context.Context, tx *sql.Tx, params GetOutboxEmailsParams) ([]OutboxEmail, pagination.Info, error)
+	RedriveOutboxEmail(ctx context.Context, tx *sql.Tx, emailId int) error
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	createOutboxEmailQuery = `
+	INSERT INTO email_outbox (
+		to_name,
+		to_email,
+		subject,
+		plain_text_content,
+		html_content,
+		max_attempts,
+		sensitive
+	) VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	// Pushing next_attempt_at forward acts as a lease, so a worker that dies mid-send
+	// releases its claim once the lease expires instead of holding row locks during delivery.
+	claimDueOutboxEmailsQuery = `
+	UPDATE email_outbox
+	SET
+		attempts = attempts + 1,
+		next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+	WHERE id IN (
+		SELECT id
+		FROM email_outbox
+		WHERE status = 'PENDING' AND next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY next_attempt_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *;`
+
+	// The content of sensitive emails is only kept until it has been delivered.
+	markOutboxEmailSentQuery = `
+	UPDATE email_outbox
+	SET
+		status = 'SENT',
+		sent_at = CURRENT_TIMESTAMP,
+		last_error = '',
+		plain_text_content = CASE WHEN sensitive THEN '' ELSE plain_text_content END,
+		html_content = CASE WHEN sensitive THEN '' ELSE html_content END
+	WHERE id = $1;`
+
+	markOutboxEmailFailedQuery = `
+	UPDATE email_outbox
+	SET status = $2, next_attempt_at = $3, last_error = $4
+	WHERE id = $1;`
+
+	outboxEmailColumns = `
+		o.id,
+		o.to_name,
+		o.to_email,
+		o.subject,
+		o.plain_text_content,
+		o.status,
+		o.attempts,
+		o.max_attempts,
+		o.next_attempt_at,
+		o.last_error,
+		o.sent_at,
+		o.created_at,
+		o.updated_at,
+		o.html_content,
+		o.sensitive`
+
+	redriveOutboxEmailQuery = `
+	UPDATE email_outbox
+	SET status = 'PENDING', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, last_error = ''
+	WHERE id = $1 AND status = 'DEAD';`
+)
+
+const OutboxSortNewest = "newest"
+
+var outboxEmailsOrder = sortOrder{
+	{expression: "o.created_at", descending: true, kind: sortKeyTime},
+	{expression: "o.id", descending: true, kind: sortKeyInt},
+}
+
+func (or *outboxRepository) CreateOutboxEmail(ctx context.Context, tx *sql.Tx, emailData CreateOutboxEmailData) error {
+	executer := or.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(
+		ctx,
+		createOutboxEmailQuery,
+		emailData.ToName,
+		emailData.ToEmail,
+		emailData.Subject,
+		emailData.PlainTextContent,
+		emailData.HTMLContent,
+		emailData.MaxAttempts,
+		emailData.Sensitive,
+	)
+	if err != nil {
+		slog.Error("failed to create outbox email", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (or *outboxRepository) ClaimDueOutboxEmails(ctx context.Context, tx *sql.Tx, limit int, leaseDuration time.Duration) ([]OutboxEmail, error) {
+	executer := or.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, claimDueOutboxEmailsQuery, limit, leaseDuration.Seconds())
+	if err != nil {
+		slog.Error("failed to claim due outbox emails", "error", err)
+		return []OutboxEmail{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	var emails []OutboxEmail
+	for rows.Next() {
+		email, err := scanOutboxEmail(rows, nil)
+		if err != nil {
+			slog.Error("failed to scan outbox email", "error", err)
+			return []OutboxEmail{}, apperrors.ErrInternalServer
+		}
+		emails = append(emails, email)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over outbox email rows", "error", err)
+		return []OutboxEmail{}, apperrors.ErrInternalServer
+	}
+
+	return emails, nil
+}
+
+func (or *outboxRepository) MarkOutboxEmailSent(ctx context.Context, tx *sql.Tx, emailId int) error {
+	executer := or.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markOutboxEmailSentQuery, emailId)
+	if err != nil {
+		slog.Error("failed to mark outbox email as sent", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (or *outboxRepository) MarkOutboxEmailFailed(ctx context.Context, tx *sql.Tx, emailId int, status string, nextAttemptAt time.Time, lastError string) error {
+	executer := or.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markOutboxEmailFailedQuery, emailId, status, nextAttemptAt, lastError)
+	if err != nil {
+		slog.Error("failed to mark outbox email as failed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// GetOutboxEmails lists outbox emails, newest first, optionally only those with a status.
+func (or *outboxRepository) GetOutboxEmails(ctx context.Context, tx *sql.Tx, params GetOutboxEmailsParams) ([]OutboxEmail, pagination.Info, error) {
+	executer := or.initiateQueryExecuter(tx)
+
+	lq := listQuery{columns: outboxEmailColumns, from: "email_outbox o", order: outboxEmailsOrder}
+	if params.Status != "" {
+		lq.qb.where("o.status = " + lq.qb.arg(params.Status))
+	}
+
+	return fetchPage(ctx, executer, lq, params.Page, func(rows *sql.Rows, key *[]byte) (OutboxEmail, error) {
+		return scanOutboxEmail(rows, key)
+	})
+}
+
+func (or *outboxRepository) RedriveOutboxEmail(ctx context.Context, tx *sql.Tx, emailId int) error {
+	executer := or.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, redriveOutboxEmailQuery, emailId)
+	if err != nil {
+		slog.Error("failed to redrive outbox email", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows for outbox email redrive", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.ErrOutboxEmailNotFound
+	}
+
+	return nil
+}
+
+// scanOutboxEmail reads a row in email_outbox column order, followed by the row's sort key
+// when key is not nil.
+func scanOutboxEmail(row interface{ Scan(dest ...any) error }, key *[]byte) (OutboxEmail, error) {
+	var email OutboxEmail
+	fields := []any{
+		&email.Id,
+		&email.ToName,
+		&email.ToEmail,
+		&email.Subject,
+		&email.PlainTextContent,
+		&email.Status,
+		&email.Attempts,
+		&email.MaxAttempts,
+		&email.NextAttemptAt,
+		&email.LastError,
+		&email.SentAt,
+		&email.CreatedAt,
+		&email.UpdatedAt,
+		&email.HTMLContent,
+		&email.Sensitive,
+	}
+	if key != nil {
+		fields = append(fields, key)
+	}
+
+	err := row.Scan(fields...)
+	return email, err
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+)
+
+type sortKeyKind int
+
+const (
+	sortKeyTime sortKeyKind = iota
+	sortKeyInt
+	sortKeyNumeric
+	// Real keys are compared as REAL, so that a float4 such as ts_rank survives the round
+	// trip through the cursor exactly.
+	sortKeyReal
+)
+
+type sortKey struct {
+	expression string
+	descending bool
+	kind       sortKeyKind
+}
+
+// sortOrder is a list ordering whose last key must be unique, so that every row has a
+// distinct position to resume from. Keys must not be NULL.
+type sortOrder []sortKey
+
+const (
+	listPageQuery = `
+	SELECT %s,
+		json_build_array(%s) AS sort_key
+	FROM %s
+	%s
+	ORDER BY %s
+	%s;`
+
+	listCountQuery = "SELECT COUNT(*) FROM %s %s;"
+)
+
+// listQuery is a paginated list whose filters have all been added to qb.
+type listQuery struct {
+	columns string
+	from    string
+	order   sortOrder
+	qb      queryBuilder
+}
+
+func (lq listQuery) countQuery() (string, []any) {
+	return fmt.Sprintf(listCountQuery, lq.from, lq.qb.whereClause()), lq.qb.args
+}
+
+// pageQuery fetches one row past the limit to learn whether another page follows. Backward
+// pages are read in reverse order and flipped back by fetchPage.
+func (lq listQuery) pageQuery(page pagination.Params) (string, []any, error) {
+	qb := queryBuilder{conditions: slices.Clone(lq.qb.conditions), args: slices.Clone(lq.qb.args)}
+
+	backward := false
+	if page.Cursor != nil {
+		backward = page.Cursor.Backward
+		condition, err := lq.order.after(&qb, page.Cursor.Key, backward)
+		if err != nil {
+			return "", nil, err
+		}
+		qb.where(condition)
+	}
+
+	limit := ""
+	if page.Cursor == nil && page.Offset > 0 {
+		limit = "OFFSET " + qb.arg(page.Offset) + "\n\t"
+	}
+	limit += "LIMIT " + qb.arg(page.Limit+1)
+
+	expressions := make([]string, len(lq.order))
+	for i, key := range lq.order {
+		expressions[i] = key.expression
+	}
+
+	query := fmt.Sprintf(listPageQuery, lq.columns, strings.Join(expressions, ", "), lq.from, qb.whereClause(), lq.order.orderBy(backward), limit)
+	return query, qb.args, nil
+}
+
+func (o sortOrder) orderBy(reverse bool) string {
+	terms := make([]string, len(o))
+	for i, key := range o {
+		terms[i] = key.expression
+		if key.descending != reverse {
+			terms[i] += " DESC"
+		}
+	}
+
+	return strings.Join(terms, ", ")
+}
+
+// after builds the condition for rows that sort after the cursor key, or before it when
+// going backward. Orders running in a single direction use a row comparison, which indexes
+// on the sort columns can serve; mixed directions expand into the equivalent OR chain.
+func (o sortOrder) after(qb *queryBuilder, key json.RawMessage, backward bool) (string, error) {
+	values, err := o.decodeKey(key)
+	if err != nil {
+		slog.Error("invalid pagination cursor key", "error", err)
+		return "", apperrors.ErrInvalidCursor
+	}
+
+	placeholders := make([]string, len(o))
+	for i, value := range values {
+		placeholders[i] = qb.arg(value)
+		if o[i].kind == sortKeyReal {
+			placeholders[i] += "::real"
+		}
+	}
+
+	operator := func(key sortKey) string {
+		if key.descending != backward {
+			return "<"
+		}
+		return ">"
+	}
+
+	singleDirection := true
+	for _, key := range o {
+		singleDirection = singleDirection && key.descending == o[0].descending
+	}
+	if singleDirection {
+		expressions := make([]string, len(o))
+		for i, key := range o {
+			expressions[i] = key.expression
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(expressions, ", "), operator(o[0]), strings.Join(placeholders, ", ")), nil
+	}
+
+	alternatives := make([]string, len(o))
+	for i, key := range o {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, o[j].expression+" = "+placeholders[j])
+		}
+		terms = append(terms, key.expression+" "+operator(key)+" "+placeholders[i])
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", nil
+}
+
+// decodeKey checks every cursor value against the kind of its sort key, so that a tampered
+// cursor is rejected up front instead of failing inside the query.
+func (o sortOrder) decodeKey(key json.RawMessage) ([]any, error) {
+	var rawValues []json.RawMessage
+	err := json.Unmarshal(key, &rawValues)
+	if err != nil {
+		return nil, err
+	}
+	if len(rawValues) != len(o) {
+		return nil, fmt.Errorf("cursor key has %d values, expected %d", len(rawValues), len(o))
+	}
+
+	values := make([]any, len(o))
+	for i, rawValue := range rawValues {
+		switch o[i].kind {
+		case sortKeyTime:
+			var value time.Time
+			err = json.Unmarshal(rawValue, &value)
+			values[i] = value
+		case sortKeyInt:
+			var value int64
+			err = json.Unmarshal(rawValue, &value)
+			values[i] = value
+		default:
+			var value json.Number
+			err = json.Unmarshal(rawValue, &value)
+			if err == nil {
+				_, err = strconv.ParseFloat(value.String(), 64)
+			}
+			values[i] = value.String()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cursor key value %d: %w", i, err)
+		}
+	}
+
+	return values, nil
+}
+
+// fetchPage runs a list query and, unless the caller skipped it, its count. scan reads one
+// row, whose last column is the row's sort key.
+func fetchPage[T any](ctx context.Context, executer QueryExecuter, lq listQuery, page pagination.Params, scan func(rows *sql.Rows, key *[]byte) (T, error)) ([]T, pagination.Info, error) {
+	query, args, err := lq.pageQuery(page)
+	if err != nil {
+		return []T{}, pagination.Info{}, err
+	}
+
+	rows, err := executer.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to get list page", "error", err)
+		return []T{}, pagination.Info{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	items := []T{}
+	var keys []json.RawMessage
+	for rows.Next() {
+		var key []byte
+		item, err := scan(rows, &key)
+		if err != nil {
+			slog.Error("failed to scan list row", "error", err)
+			return []T{}, pagination.Info{}, apperrors.ErrInternalServer
+		}
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over list rows", "error", err)
+		return []T{}, pagination.Info{}, apperrors.ErrInternalServer
+	}
+
+	var info pagination.Info
+	hasMore := len(items) > page.Limit
+	if hasMore {
+		items = items[:page.Limit]
+		keys = keys[:page.Limit]
+	}
+
+	switch {
+	case page.Cursor == nil:
+		info.HasNext = hasMore
+		info.HasPrev = page.Offset > 0
+	case page.Cursor.Backward:
+		slices.Reverse(items)
+		slices.Reverse(keys)
+		info.HasNext = true
+		info.HasPrev = hasMore
+	default:
+		info.HasNext = hasMore
+		info.HasPrev = true
+	}
+
+	if len(keys) > 0 {
+		info.FirstKey = keys[0]
+		info.LastKey = keys[len(keys)-1]
+	}
+
+	if !page.SkipTotal {
+		countQuery, countArgs := lq.countQuery()
+		var totalCount int
+		err = executer.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount)
+		if err != nil {
+			slog.Error("failed to count list rows", "error", err)
+			return []T{}, pagination.Info{}, apperrors.ErrInternalServer
+		}
+		info.TotalCount = &totalCount
+	}
+
+	return items, info, nil
+}
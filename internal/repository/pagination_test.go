@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+)
+
+func TestSortOrderDecodeKey(t *testing.T) {
+	order := sortOrder{
+		{expression: "v.created_at", descending: true, kind: sortKeyTime},
+		{expression: "v.rate_per_hour", kind: sortKeyNumeric},
+		{expression: "v.id", kind: sortKeyInt},
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    []any
+		wantErr bool
+	}{
+		{
+			name: "valid key",
+			key:  `["2024-05-01T10:00:00Z", 12.50, 7]`,
+			want: []any{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), "12.50", int64(7)},
+		},
+		{
+			name: "numeric keeps its exact text",
+			key:  `["2024-05-01T10:00:00Z", 0.1000000000000000055511151231257827, 7]`,
+			want: []any{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), "0.1000000000000000055511151231257827", int64(7)},
+		},
+		{name: "not an array", key: `{"id": 7}`, wantErr: true},
+		{name: "too few values", key: `["2024-05-01T10:00:00Z", 12.5]`, wantErr: true},
+		{name: "too many values", key: `["2024-05-01T10:00:00Z", 12.5, 7, 8]`, wantErr: true},
+		{name: "invalid time", key: `["yesterday", 12.5, 7]`, wantErr: true},
+		{name: "fractional int", key: `["2024-05-01T10:00:00Z", 12.5, 7.5]`, wantErr: true},
+		{name: "string int", key: `["2024-05-01T10:00:00Z", 12.5, "7"]`, wantErr: true},
+		{name: "string numeric", key: `["2024-05-01T10:00:00Z", "12.5; DROP TABLE vehicles", 7]`, wantErr: true},
+		{name: "null value", key: `["2024-05-01T10:00:00Z", null, 7]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := order.decodeKey(json.RawMessage(tt.key))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeKey(%s) = %v, want an error", tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeKey(%s) returned error: %v", tt.key, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeKey(%s) = %#v, want %#v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortOrderAfter(t *testing.T) {
+	ascending := sortOrder{
+		{expression: "v.rate_per_hour", kind: sortKeyNumeric},
+		{expression: "v.id", kind: sortKeyInt},
+	}
+	descending := sortOrder{
+		{expression: "v.created_at", descending: true, kind: sortKeyTime},
+		{expression: "v.id", descending: true, kind: sortKeyInt},
+	}
+	mixed := sortOrder{
+		{expression: "v.rate_per_hour", descending: true, kind: sortKeyNumeric},
+		{expression: "v.id", kind: sortKeyInt},
+	}
+	relevance := sortOrder{
+		{expression: "ts_rank(v.search_vector, search_query)", descending: true, kind: sortKeyReal},
+		{expression: "v.id", kind: sortKeyInt},
+	}
+
+	tests := []struct {
+		name      string
+		order     sortOrder
+		key       string
+		backward  bool
+		priorArgs int
+		want      string
+		wantArgs  []any
+	}{
+		{
+			name:     "ascending forward",
+			order:    ascending,
+			key:      `[10, 3]`,
+			want:     "(v.rate_per_hour, v.id) > ($1, $2)",
+			wantArgs: []any{"10", int64(3)},
+		},
+		{
+			name:     "ascending backward",
+			order:    ascending,
+			key:      `[10, 3]`,
+			backward: true,
+			want:     "(v.rate_per_hour, v.id) < ($1, $2)",
+			wantArgs: []any{"10", int64(3)},
+		},
+		{
+			name:     "descending forward",
+			order:    descending,
+			key:      `["2024-05-01T10:00:00Z", 3]`,
+			want:     "(v.created_at, v.id) < ($1, $2)",
+			wantArgs: []any{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), int64(3)},
+		},
+		{
+			name:     "mixed forward",
+			order:    mixed,
+			key:      `[10, 3]`,
+			want:     "((v.rate_per_hour < $1) OR (v.rate_per_hour = $1 AND v.id > $2))",
+			wantArgs: []any{"10", int64(3)},
+		},
+		{
+			name:     "mixed backward",
+			order:    mixed,
+			key:      `[10, 3]`,
+			backward: true,
+			want:     "((v.rate_per_hour > $1) OR (v.rate_per_hour = $1 AND v.id < $2))",
+			wantArgs: []any{"10", int64(3)},
+		},
+		{
+			name:     "real keys are cast",
+			order:    relevance,
+			key:      `[0.0607927, 3]`,
+			want:     "((ts_rank(v.search_vector, search_query) < $1::real) OR (ts_rank(v.search_vector, search_query) = $1::real AND v.id > $2))",
+			wantArgs: []any{"0.0607927", int64(3)},
+		},
+		{
+			name:      "placeholders follow existing arguments",
+			order:     ascending,
+			key:       `[10, 3]`,
+			priorArgs: 2,
+			want:      "(v.rate_per_hour, v.id) > ($3, $4)",
+			wantArgs:  []any{"filter", "filter", "10", int64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var qb queryBuilder
+			for i := 0; i < tt.priorArgs; i++ {
+				qb.arg("filter")
+			}
+
+			got, err := tt.order.after(&qb, json.RawMessage(tt.key), tt.backward)
+			if err != nil {
+				t.Fatalf("after returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("after = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(qb.args, tt.wantArgs) {
+				t.Errorf("after args = %#v, want %#v", qb.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSortOrderAfterRejectsInvalidKey(t *testing.T) {
+	order := sortOrder{{expression: "v.id", kind: sortKeyInt}}
+
+	var qb queryBuilder
+	_, err := order.after(&qb, json.RawMessage(`["1 OR 1=1"]`), false)
+	if !errors.Is(err, apperrors.ErrInvalidCursor) {
+		t.Fatalf("after error = %v, want %v", err, apperrors.ErrInvalidCursor)
+	}
+	if len(qb.args) != 0 {
+		t.Errorf("after registered args %v for an invalid key", qb.args)
+	}
+}
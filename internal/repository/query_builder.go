@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+)
+
+// queryBuilder collects the conditions and positional arguments of queries whose filters
+// depend on the request, so placeholders are numbered in the order arguments are added.
+type queryBuilder struct {
+	conditions []string
+	args       []any
+}
+
+// arg registers a query argument and returns its placeholder.
+func (qb *queryBuilder) arg(value any) string {
+	qb.args = append(qb.args, value)
+	return "$" + strconv.Itoa(len(qb.args))
+}
+
+func (qb *queryBuilder) where(condition string) {
+	qb.conditions = append(qb.conditions, condition)
+}
+
+func (qb *queryBuilder) whereClause() string {
+	if len(qb.conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE\n\t\t" + strings.Join(qb.conditions, " AND\n\t\t")
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input only ever matches literally.
+func escapeLikePattern(value string) string {
+	return likePatternEscaper.Replace(value)
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
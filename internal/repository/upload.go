@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type uploadRepository struct {
+	BaseRepository
+}
+
+type UploadRepository interface {
+	RepositoryTransaction
+	CreateUpload(ctx context.Context, tx *sql.Tx, uploadData CreateUploadData) (Upload, error)
+	GetUploadsForUpdate(ctx context.Context, tx *sql.Tx, uploadIds []int, userId int) ([]Upload, error)
+	MarkUploadsLinked(ctx context.Context, tx *sql.Tx, uploadIds []int) error
+	GetOrphanedUploads(ctx context.Context, tx *sql.Tx, limit int) ([]Upload, error)
+	DeleteUpload(ctx context.Context, tx *sql.Tx, uploadId int) error
+}
+
+func NewUploadRepository(db *sql.DB) UploadRepository {
+	return &uploadRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	createUploadQuery = `
+	INSERT INTO uploads (
+		user_id,
+		object_path,
+		content_type,
+		expires_at
+	) VALUES ($1, $2, $3, $4)
+	RETURNING *;`
+
+	getUploadsForUpdateQuery = `
+	SELECT * FROM uploads
+	WHERE id = ANY($1) AND user_id = $2
+	FOR UPDATE;`
+
+	markUploadsLinkedQuery = `
+	UPDATE uploads
+	SET status = 'LINKED', linked_at = CURRENT_TIMESTAMP
+	WHERE id = ANY($1);`
+
+	// An upload is orphaned when it was never linked before expiring, or when the image that
+	// linked it has since been removed.
+	getOrphanedUploadsQuery = `
+	SELECT * FROM uploads u
+	WHERE (u.status = 'PENDING' AND u.expires_at <= CURRENT_TIMESTAMP)
+	OR (u.status = 'LINKED' AND NOT EXISTS (SELECT 1 FROM vehicle_images vi WHERE vi.upload_id = u.id))
+	ORDER BY u.id
+	LIMIT $1;`
+
+	deleteUploadQuery = "DELETE FROM uploads WHERE id=$1"
+)
+
+func (ur *uploadRepository) CreateUpload(ctx context.Context, tx *sql.Tx, uploadData CreateUploadData) (Upload, error) {
+	executer := ur.initiateQueryExecuter(tx)
+
+	var upload Upload
+	err := executer.QueryRowContext(
+		ctx,
+		createUploadQuery,
+		uploadData.UserId,
+		uploadData.ObjectPath,
+		uploadData.ContentType,
+		uploadData.ExpiresAt,
+	).Scan(
+		&upload.Id,
+		&upload.UserId,
+		&upload.ObjectPath,
+		&upload.ContentType,
+		&upload.Status,
+		&upload.ExpiresAt,
+		&upload.LinkedAt,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		slog.Error("failed to create upload", "error", err)
+		return Upload{}, apperrors.ErrInternalServer
+	}
+
+	return upload, nil
+}
+
+func (ur *uploadRepository) GetUploadsForUpdate(ctx context.Context, tx *sql.Tx, uploadIds []int, userId int) ([]Upload, error) {
+	return ur.getUploads(ctx, tx, getUploadsForUpdateQuery, pq.Array(uploadIds), userId)
+}
+
+func (ur *uploadRepository) MarkUploadsLinked(ctx context.Context, tx *sql.Tx, uploadIds []int) error {
+	executer := ur.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markUploadsLinkedQuery, pq.Array(uploadIds))
+	if err != nil {
+		slog.Error("failed to mark uploads as linked", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (ur *uploadRepository) GetOrphanedUploads(ctx context.Context, tx *sql.Tx, limit int) ([]Upload, error) {
+	return ur.getUploads(ctx, tx, getOrphanedUploadsQuery, limit)
+}
+
+func (ur *uploadRepository) DeleteUpload(ctx context.Context, tx *sql.Tx, uploadId int) error {
+	executer := ur.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteUploadQuery, uploadId)
+	if err != nil {
+		slog.Error("failed to delete upload", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (ur *uploadRepository) getUploads(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]Upload, error) {
+	executer := ur.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to get uploads", "error", err)
+		return []Upload{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	var uploads []Upload
+	for rows.Next() {
+		var upload Upload
+		err = rows.Scan(
+			&upload.Id,
+			&upload.UserId,
+			&upload.ObjectPath,
+			&upload.ContentType,
+			&upload.Status,
+			&upload.ExpiresAt,
+			&upload.LinkedAt,
+			&upload.CreatedAt,
+		)
+		if err != nil {
+			slog.Error("failed to scan upload", "error", err)
+			return []Upload{}, apperrors.ErrInternalServer
+		}
+		uploads = append(uploads, upload)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over upload rows", "error", err)
+		return []Upload{}, apperrors.ErrInternalServer
+	}
+
+	return uploads, nil
+}
@@ -26,6 +26,14 @@ type UserRepository interface {
 	DeleteVerificationTokenById(ctx context.Context, tx *sql.Tx, tokenId int) error
 	UpdateUserPassword(ctx context.Context, tx *sql.Tx, userId int, password string) error
 	UpdateUserRole(ctx context.Context, tx *sql.Tx, userId int, role string) error
+	UpdateUserPreferredLanguage(ctx context.Context, tx *sql.Tx, userId int, preferredLanguage string) error
+	CreateUserSession(ctx context.Context, tx *sql.Tx, sessionData UserSession) (UserSession, error)
+	GetUserSessionById(ctx context.Context, tx *sql.Tx, sessionId string) (UserSession, error)
+	GetActiveUserSessions(ctx context.Context, tx *sql.Tx, userId int) ([]UserSession, error)
+	RotateUserSessionRefreshToken(ctx context.Context, tx *sql.Tx, sessionId, oldRefreshTokenHash, newRefreshTokenHash string, expiresAt time.Time) error
+	RevokeUserSession(ctx context.Context, tx *sql.Tx, sessionId string) error
+	RevokeAllUserSessions(ctx context.Context, tx *sql.Tx, userId int) error
+	DeleteStaleUserSessions(ctx context.Context, tx *sql.Tx, endedBefore time.Time, limit int) (int64, error)
 }
 
 func NewUserRepository(db *sql.DB) UserRepository {
@@ -36,8 +44,8 @@ func NewUserRepository(db *sql.DB) UserRepository {
 
 const (
 	createUserQuery = `
-	INSERT INTO users (name, email, phone_number, password, role)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO users (name, email, phone_number, password, role, preferred_language)
+	VALUES ($1, $2, $3, $4, $5, $6)
 	RETURNING *`
 
 	getUserByIdQuery = "SELECT * FROM users WHERE id=$1"
@@ -50,6 +58,8 @@ const (
 
 	updateUserRoleQuery = "UPDATE users SET role=$1 WHERE id=$2"
 
+	updateUserPreferredLanguageQuery = "UPDATE users SET preferred_language=$1 WHERE id=$2"
+
 	createVerificationTokenQuery = `
 	INSERT INTO verification_tokens (user_id, token, type, expires_at)
 	VALUES ($1, $2, $3, $4)
@@ -58,6 +68,37 @@ const (
 	getVerificationTokenByTokenQuery = "SELECT * FROM verification_tokens WHERE token=$1"
 
 	deleteVerificationTokenByIdQuery = "DELETE FROM verification_tokens WHERE id=$1"
+
+	createUserSessionQuery = `
+	INSERT INTO user_sessions (id, user_id, refresh_token_hash, user_agent, ip_address, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING *`
+
+	getUserSessionByIdQuery = "SELECT * FROM user_sessions WHERE id=$1"
+
+	getActiveUserSessionsQuery = `
+	SELECT * FROM user_sessions
+	WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	ORDER BY last_used_at DESC`
+
+	rotateUserSessionRefreshTokenQuery = `
+	UPDATE user_sessions
+	SET refresh_token_hash=$1, expires_at=$2, last_used_at=CURRENT_TIMESTAMP
+	WHERE id=$3 AND refresh_token_hash=$4 AND revoked_at IS NULL`
+
+	revokeUserSessionQuery = "UPDATE user_sessions SET revoked_at=CURRENT_TIMESTAMP WHERE id=$1 AND revoked_at IS NULL"
+
+	revokeAllUserSessionsQuery = "UPDATE user_sessions SET revoked_at=CURRENT_TIMESTAMP WHERE user_id=$1 AND revoked_at IS NULL"
+
+	// Sessions that expired or were revoked before $1 can no longer be used or listed.
+	deleteStaleUserSessionsQuery = `
+	DELETE FROM user_sessions
+	WHERE id IN (
+		SELECT id
+		FROM user_sessions
+		WHERE expires_at < $1 OR revoked_at < $1
+		LIMIT $2
+	);`
 )
 
 func (ur *userRepository) CreateUser(ctx context.Context, tx *sql.Tx, userData CreateUserRequestBody) (User, error) {
@@ -72,6 +113,7 @@ func (ur *userRepository) CreateUser(ctx context.Context, tx *sql.Tx, userData C
 		userData.PhoneNumber,
 		userData.Password,
 		userData.Role,
+		userData.PreferredLanguage,
 	).Scan(
 		&user.Id,
 		&user.Name,
@@ -82,6 +124,7 @@ func (ur *userRepository) CreateUser(ctx context.Context, tx *sql.Tx, userData C
 		&user.IsVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.PreferredLanguage,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
@@ -113,6 +156,7 @@ func (ur *userRepository) GetUserById(ctx context.Context, tx *sql.Tx, userId in
 		&user.IsVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.PreferredLanguage,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -144,6 +188,7 @@ func (ur *userRepository) GetUserByEmail(ctx context.Context, tx *sql.Tx, email
 		&user.IsVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.PreferredLanguage,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -193,6 +238,18 @@ func (ur *userRepository) UpdateUserRole(ctx context.Context, tx *sql.Tx, userId
 	return nil
 }
 
+func (ur *userRepository) UpdateUserPreferredLanguage(ctx context.Context, tx *sql.Tx, userId int, preferredLanguage string) error {
+	executer := ur.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateUserPreferredLanguageQuery, preferredLanguage, userId)
+	if err != nil {
+		slog.Error("failed to update user preferred language", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
 func (ur *userRepository) CreateVerificationToken(ctx context.Context, tx *sql.Tx, userId int, token, tokenType string, expiresAt time.Time) (VerificationToken, error) {
 	executer := ur.initiateQueryExecuter(tx)
 
@@ -257,3 +314,171 @@ func (ur *userRepository) DeleteVerificationTokenById(ctx context.Context, tx *s
 
 	return nil
 }
+
+func (ur *userRepository) CreateUserSession(ctx context.Context, tx *sql.Tx, sessionData UserSession) (UserSession, error) {
+	executer := ur.initiateQueryExecuter(tx)
+
+	var session UserSession
+	err := executer.QueryRowContext(
+		ctx,
+		createUserSessionQuery,
+		sessionData.Id,
+		sessionData.UserId,
+		sessionData.RefreshTokenHash,
+		sessionData.UserAgent,
+		sessionData.IpAddress,
+		sessionData.ExpiresAt,
+	).Scan(
+		&session.Id,
+		&session.UserId,
+		&session.RefreshTokenHash,
+		&session.UserAgent,
+		&session.IpAddress,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.LastUsedAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		slog.Error("failed to create user session", "error", err)
+		return UserSession{}, apperrors.ErrInternalServer
+	}
+
+	return session, nil
+}
+
+func (ur *userRepository) GetUserSessionById(ctx context.Context, tx *sql.Tx, sessionId string) (UserSession, error) {
+	executer := ur.initiateQueryExecuter(tx)
+
+	var session UserSession
+	err := executer.QueryRowContext(
+		ctx,
+		getUserSessionByIdQuery,
+		sessionId,
+	).Scan(
+		&session.Id,
+		&session.UserId,
+		&session.RefreshTokenHash,
+		&session.UserAgent,
+		&session.IpAddress,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.LastUsedAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("no user session found", "error", err)
+			return UserSession{}, apperrors.ErrUnauthorizedAccess
+		}
+		slog.Error("failed to fetch user session", "error", err)
+		return UserSession{}, apperrors.ErrInternalServer
+	}
+
+	return session, nil
+}
+
+func (ur *userRepository) GetActiveUserSessions(ctx context.Context, tx *sql.Tx, userId int) ([]UserSession, error) {
+	executer := ur.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getActiveUserSessionsQuery, userId)
+	if err != nil {
+		slog.Error("failed to get active user sessions", "error", err)
+		return []UserSession{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	sessions := []UserSession{}
+	for rows.Next() {
+		var session UserSession
+		err = rows.Scan(
+			&session.Id,
+			&session.UserId,
+			&session.RefreshTokenHash,
+			&session.UserAgent,
+			&session.IpAddress,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+			&session.LastUsedAt,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			slog.Error("failed to scan user session from rows", "error", err)
+			return []UserSession{}, apperrors.ErrInternalServer
+		}
+		sessions = append(sessions, session)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed iterate over user session rows", "error", err)
+		return []UserSession{}, apperrors.ErrInternalServer
+	}
+
+	return sessions, nil
+}
+
+func (ur *userRepository) RotateUserSessionRefreshToken(ctx context.Context, tx *sql.Tx, sessionId, oldRefreshTokenHash, newRefreshTokenHash string, expiresAt time.Time) error {
+	executer := ur.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, rotateUserSessionRefreshTokenQuery, newRefreshTokenHash, expiresAt, sessionId, oldRefreshTokenHash)
+	if err != nil {
+		slog.Error("failed to rotate user session refresh token", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get rotated user session count", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		slog.Error("refresh token already rotated or session revoked", "sessionId", sessionId)
+		return apperrors.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+func (ur *userRepository) RevokeUserSession(ctx context.Context, tx *sql.Tx, sessionId string) error {
+	executer := ur.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, revokeUserSessionQuery, sessionId)
+	if err != nil {
+		slog.Error("failed to revoke user session", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (ur *userRepository) RevokeAllUserSessions(ctx context.Context, tx *sql.Tx, userId int) error {
+	executer := ur.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, revokeAllUserSessionsQuery, userId)
+	if err != nil {
+		slog.Error("failed to revoke all user sessions", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (ur *userRepository) DeleteStaleUserSessions(ctx context.Context, tx *sql.Tx, endedBefore time.Time, limit int) (int64, error) {
+	executer := ur.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, deleteStaleUserSessionsQuery, endedBefore, limit)
+	if err != nil {
+		slog.Error("failed to delete stale user sessions", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows for stale user sessions", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return rowsAffected, nil
+}
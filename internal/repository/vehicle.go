@@ -3,10 +3,17 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/apperrors"
+	"github.com/adityapadekar-josh/Wheelio-Backend.git/internal/pkg/pagination"
+	"github.com/lib/pq"
 )
 
 type vehicleRepository struct {
@@ -17,13 +24,33 @@ type VehicleRepository interface {
 	RepositoryTransaction
 	CreateVehicle(ctx context.Context, tx *sql.Tx, vehicleData CreateVehicleRequestBody) (Vehicle, error)
 	UpdateVehicle(ctx context.Context, tx *sql.Tx, vehicleData EditVehicleRequestBody) (Vehicle, error)
-	SoftDeleteVehicle(ctx context.Context, tx *sql.Tx, vehicleId int) error
+	SoftDeleteVehicle(ctx context.Context, tx *sql.Tx, vehicleId, hostId int) error
+	VehicleOwnershipCheck(ctx context.Context, tx *sql.Tx, vehicleId, hostId int) error
+	UpdateVehicleAvailability(ctx context.Context, tx *sql.Tx, vehicleId, hostId int, available bool) error
+	CreateVehicleBlackout(ctx context.Context, tx *sql.Tx, blackoutData CreateVehicleBlackoutData) (VehicleBlackout, error)
+	GetUpcomingVehicleBlackouts(ctx context.Context, tx *sql.Tx, vehicleId int) ([]VehicleBlackout, error)
+	DeleteVehicleBlackout(ctx context.Context, tx *sql.Tx, vehicleId, blackoutId int) error
+	BlackoutBookingConflictCheck(ctx context.Context, tx *sql.Tx, vehicleId int, startsAt, endsAt time.Time) error
+	GetVehicleBusyIntervals(ctx context.Context, tx *sql.Tx, vehicleId int, from, to time.Time, bookingBuffer time.Duration) ([]BusyInterval, error)
 	CreateVehicleImage(ctx context.Context, tx *sql.Tx, vehicleImageData CreateVehicleImageData) (VehicleImage, error)
-	DeleteAllImagesForVehicle(ctx context.Context, tx *sql.Tx, vehicleId int) error
+	LockVehicle(ctx context.Context, tx *sql.Tx, vehicleId int) error
+	GetVehicleImageById(ctx context.Context, tx *sql.Tx, vehicleId, imageId int) (VehicleImage, error)
+	DeleteVehicleImage(ctx context.Context, tx *sql.Tx, vehicleId, imageId int) error
+	SetFeaturedVehicleImage(ctx context.Context, tx *sql.Tx, vehicleId, imageId int) error
+	UpdateVehicleImagePositions(ctx context.Context, tx *sql.Tx, vehicleId int, orderedImageIds []int) error
 	GetVehicleById(ctx context.Context, tx *sql.Tx, vehicleId int) (Vehicle, error)
+	GetVehicleRatePerHour(ctx context.Context, tx *sql.Tx, vehicleId int) (float64, error)
+	GetVehiclesWithoutCoordinates(ctx context.Context, tx *sql.Tx, afterId, limit int) ([]Vehicle, error)
+	UpdateVehicleCoordinates(ctx context.Context, tx *sql.Tx, vehicleId int, latitude, longitude float64) error
 	GetVehicleImagesByVehicleId(ctx context.Context, tx *sql.Tx, vehicleId int) ([]VehicleImage, error)
-	GetVehicles(ctx context.Context, tx *sql.Tx, params GetVehiclesParams) ([]VehicleOverview, int, error)
-	GetVehiclesForHost(ctx context.Context, tx *sql.Tx, params GetVehiclesForHostParams) ([]VehicleOverview, int, error)
+	GetAllVehicleImages(ctx context.Context, tx *sql.Tx) ([]VehicleImage, error)
+	UpdateVehicleImageLocation(ctx context.Context, tx *sql.Tx, imageId int, url string, objectPath string) error
+	ClaimPendingVehicleImages(ctx context.Context, tx *sql.Tx, limit int, leaseDuration time.Duration) ([]VehicleImage, error)
+	MarkVehicleImageProcessed(ctx context.Context, tx *sql.Tx, imageId int, variants VehicleImageVariants) error
+	MarkVehicleImageProcessingFailed(ctx context.Context, tx *sql.Tx, imageId int, status string, nextAttemptAt time.Time, processingError string) error
+	GetVehicles(ctx context.Context, tx *sql.Tx, params GetVehiclesParams) ([]VehicleOverview, pagination.Info, error)
+	GetVehicleSuggestions(ctx context.Context, tx *sql.Tx, prefix string, limit int) ([]VehicleSuggestion, error)
+	GetVehiclesForHost(ctx context.Context, tx *sql.Tx, params GetVehiclesForHostParams) ([]VehicleOverview, pagination.Info, error)
 }
 
 func NewVehicleRepository(db *sql.DB) VehicleRepository {
@@ -32,7 +59,46 @@ func NewVehicleRepository(db *sql.DB) VehicleRepository {
 	}
 }
 
+const kmPerLatitudeDegree = 111.045
+
+// Vehicle search sort orders
+const (
+	VehicleSortNewest    = "newest"
+	VehicleSortPriceAsc  = "price_asc"
+	VehicleSortPriceDesc = "price_desc"
+	VehicleSortDistance  = "distance"
+	VehicleSortRelevance = "relevance"
+)
+
+// vehicleSortOrders maps sort orders to their keys. Only these fixed expressions ever reach
+// the query; the id tie-breaker keeps pages stable. Distance depends on the search point, so
+// its order is built with the query.
+var vehicleSortOrders = map[string]sortOrder{
+	VehicleSortNewest: {
+		{expression: "v.created_at", descending: true, kind: sortKeyTime},
+		{expression: "v.id", descending: true, kind: sortKeyInt},
+	},
+	VehicleSortPriceAsc: {
+		{expression: "v.rate_per_hour", kind: sortKeyNumeric},
+		{expression: "v.id", kind: sortKeyInt},
+	},
+	VehicleSortPriceDesc: {
+		{expression: "v.rate_per_hour", descending: true, kind: sortKeyNumeric},
+		{expression: "v.id", kind: sortKeyInt},
+	},
+	VehicleSortRelevance: {
+		{expression: "ts_rank(v.search_vector, search_query)", descending: true, kind: sortKeyReal},
+		{expression: "v.id", kind: sortKeyInt},
+	},
+}
+
 const (
+	// Vehicles are selected by column list rather than *, so that columns only used inside
+	// queries, such as the search vector, are not scanned. The order matches vehicleScanFields.
+	vehicleColumns = `id, name, fuel_type, seat_count, transmission_type, features, rate_per_hour,
+		overdue_fee_rate_per_hour, address, state, city, pin_code, cancellation_allowed, available,
+		host_id, is_deleted, created_at, updated_at, latitude, longitude, cancellation_policy`
+
 	createVehicleQuery = `
 	INSERT INTO vehicles (
 		name, 
@@ -47,10 +113,13 @@ const (
 		city, 
 		pin_code, 
 		cancellation_allowed, 
-		host_id
+		host_id,
+		latitude,
+		longitude,
+		cancellation_policy
 	) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) 
-	RETURNING *;`
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) 
+	RETURNING ` + vehicleColumns + `;`
 
 	updateVehicleQuery = `
 	UPDATE vehicles 
@@ -66,36 +135,150 @@ const (
 		state = $9, 
 		city = $10, 
 		pin_code = $11, 
-		cancellation_allowed = $12 
-	WHERE id = $13 AND is_deleted=false
-	RETURNING *;`
+		cancellation_allowed = $12,
+		latitude = $15,
+		longitude = $16,
+		cancellation_policy = $17
+	WHERE id = $13 AND host_id = $14 AND is_deleted=false
+	RETURNING ` + vehicleColumns + `;`
+
+	softDeleteVehicleQuery = "UPDATE vehicles SET is_deleted=true WHERE id=$1 AND host_id=$2 AND is_deleted=false"
+
+	updateVehicleAvailabilityQuery = "UPDATE vehicles SET available=$3 WHERE id=$1 AND host_id=$2 AND is_deleted=false"
+
+	getVehicleHostIdQuery = "SELECT host_id FROM vehicles WHERE id=$1 AND is_deleted=false"
 
-	softDeleteVehicleQuery = "UPDATE vehicles SET is_deleted=true WHERE id=$1"
+	// Soft-deleted vehicles are included, as their existing bookings can still be changed.
+	getVehicleRatePerHourQuery = "SELECT rate_per_hour FROM vehicles WHERE id=$1"
 
 	createVehicleImageQuery = `
 	INSERT INTO vehicle_images (
 		vehicle_id,
 		url,
-		featured
+		featured,
+		object_path,
+		upload_id,
+		thumbnail_path,
+		medium_path,
+		large_path,
+		processing_status,
+		position
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+	RETURNING *;`
+
+	lockVehicleQuery = "SELECT id FROM vehicles WHERE id=$1 FOR UPDATE"
+
+	getVehicleImageByIdQuery = "SELECT * FROM vehicle_images WHERE id=$1 AND vehicle_id=$2"
+
+	deleteVehicleImageQuery = "DELETE FROM vehicle_images WHERE id=$1 AND vehicle_id=$2"
+
+	// The single featured image is enforced by a partial unique index that is checked per row,
+	// so the old image is cleared in its own statement before the new one is set.
+	clearFeaturedVehicleImageQuery = "UPDATE vehicle_images SET featured=false WHERE vehicle_id=$1 AND featured"
+
+	setFeaturedVehicleImageQuery = "UPDATE vehicle_images SET featured=true WHERE id=$1 AND vehicle_id=$2"
+
+	updateVehicleImagePositionsQuery = `
+	UPDATE vehicle_images vi
+	SET position = ordered.position - 1
+	FROM unnest($2::int[]) WITH ORDINALITY AS ordered (id, position)
+	WHERE vi.id = ordered.id AND vi.vehicle_id = $1;`
+
+	createVehicleBlackoutQuery = `
+	INSERT INTO vehicle_blackouts (
+		vehicle_id,
+		starts_at,
+		ends_at,
+		reason
 	)
-	VALUES ($1, $2, $3) 
+	VALUES ($1, $2, $3, $4)
 	RETURNING *;`
 
-	deleteAllImagesForVehicleQuery = "DELETE FROM vehicle_images WHERE vehicle_id=$1"
+	getUpcomingVehicleBlackoutsQuery = "SELECT * FROM vehicle_blackouts WHERE vehicle_id=$1 AND ends_at >= CURRENT_TIMESTAMP ORDER BY starts_at"
+
+	deleteVehicleBlackoutQuery = "DELETE FROM vehicle_blackouts WHERE id=$1 AND vehicle_id=$2"
 
-	getVehicleByIdQuery = "SELECT * FROM vehicles WHERE id=$1 AND is_deleted=false"
+	// Bounds are inclusive, matching the booking conflict check.
+	blackoutBookingConflictCheckQuery = `
+	SELECT 1
+	FROM bookings
+	WHERE
+		vehicle_id = $1 AND
+		status NOT IN ('RETURNED', 'CANCELLED') AND
+		scheduled_pickup_time <= $3 AND $2 <= scheduled_dropoff_time
+	LIMIT 1;`
 
-	getVehicleImagesByVehicleIdQuery = "SELECT * FROM vehicle_images WHERE vehicle_id=$1"
+	// Uses the same overlap rules and booking buffer as the booking conflict check.
+	getVehicleBusyIntervalsQuery = `
+	SELECT starts_at, ends_at
+	FROM (
+		SELECT
+			scheduled_pickup_time - make_interval(secs => $4) AS starts_at,
+			scheduled_dropoff_time + make_interval(secs => $4) AS ends_at
+		FROM bookings
+		WHERE vehicle_id = $1 AND status NOT IN ('RETURNED', 'CANCELLED')
+		UNION ALL
+		SELECT starts_at, ends_at
+		FROM vehicle_blackouts
+		WHERE vehicle_id = $1
+	) AS busy
+	WHERE starts_at <= $3 AND $2 <= ends_at
+	ORDER BY starts_at;`
 
-	getVehiclesQuery = `
-	SELECT 
+	getVehicleByIdQuery = "SELECT " + vehicleColumns + " FROM vehicles WHERE id=$1 AND is_deleted=false"
+
+	getVehiclesWithoutCoordinatesQuery = "SELECT " + vehicleColumns + " FROM vehicles WHERE id > $1 AND latitude IS NULL AND is_deleted=false ORDER BY id LIMIT $2"
+
+	updateVehicleCoordinatesQuery = "UPDATE vehicles SET latitude=$2, longitude=$3 WHERE id=$1"
+
+	getVehicleImagesByVehicleIdQuery = "SELECT * FROM vehicle_images WHERE vehicle_id=$1 ORDER BY position"
+
+	getAllVehicleImagesQuery = "SELECT * FROM vehicle_images ORDER BY id"
+
+	updateVehicleImageLocationQuery = "UPDATE vehicle_images SET url=$2, object_path=$3 WHERE id=$1"
+
+	// Like the email outbox, pushing the next attempt forward leases the claimed images to this worker.
+	claimPendingVehicleImagesQuery = `
+	UPDATE vehicle_images
+	SET
+		processing_attempts = processing_attempts + 1,
+		processing_next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+	WHERE id IN (
+		SELECT id
+		FROM vehicle_images
+		WHERE processing_status = 'PENDING'
+		AND object_path IS NOT NULL
+		AND processing_next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY processing_next_attempt_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *;`
+
+	markVehicleImageProcessedQuery = `
+	UPDATE vehicle_images
+	SET
+		thumbnail_path = $2,
+		medium_path = $3,
+		large_path = $4,
+		processing_status = 'PROCESSED',
+		processing_error = ''
+	WHERE id = $1;`
+
+	markVehicleImageProcessingFailedQuery = `
+	UPDATE vehicle_images
+	SET processing_status = $2, processing_next_attempt_at = $3, processing_error = $4
+	WHERE id = $1;`
+
+	vehicleOverviewColumns = `
 		v.id,
 		v.name,
 		v.fuel_type,
 		v.seat_count,
 		v.transmission_type,
 		COALESCE((
-			SELECT vi.url
+			SELECT COALESCE(vi.thumbnail_path, vi.object_path, vi.url)
 			FROM vehicle_images vi
 			WHERE vi.vehicle_id = v.id
 			AND vi.featured = true
@@ -103,53 +286,51 @@ const (
 		), '') AS image,
 		v.rate_per_hour,
 		v.address,
-		v.pin_code,
-		COUNT(*) OVER() AS total_count
-	FROM vehicles v
-	WHERE 
-		v.city ILIKE $1 AND
-		v.is_deleted = false AND 
-		v.available = true AND
-			NOT EXISTS (
+		v.pin_code`
+
+	// Active bookings are widened by the booking buffer, matching the booking conflict check.
+	vehicleBookingFreeCondition = `NOT EXISTS (
 			SELECT 1
 			FROM bookings AS b
 			WHERE
 				v.id = b.vehicle_id AND
 				b.status NOT IN ('RETURNED', 'CANCELLED') AND
-				(
-					(b.scheduled_pickup_time <= $2 AND $2 <= b.scheduled_dropoff_time) OR
-					(b.scheduled_pickup_time <= $3 AND $3 <= b.scheduled_dropoff_time) OR
-					($2 <= b.scheduled_pickup_time AND b.scheduled_dropoff_time <= $3) 
-				)
-			)
-	ORDER BY v.created_at DESC
-	OFFSET $4
-	LIMIT $5;`
-
-	getVehiclesForHostQuery = `
-	SELECT 
-		v.id,
-		v.name,
-		v.fuel_type,
-		v.seat_count,
-		v.transmission_type,
-		COALESCE((
-			SELECT vi.url
-			FROM vehicle_images vi
-			WHERE vi.vehicle_id = v.id
-			AND vi.featured = true
-			LIMIT 1
-		), '') AS image,
-		v.rate_per_hour,
-		v.address,
-		v.pin_code,
-		COUNT(*) OVER() AS total_count
-	FROM vehicles v
-	WHERE 
-		host_id=$1 AND
-		v.is_deleted = false
-	ORDER BY v.created_at DESC
-	OFFSET $2
+				b.scheduled_pickup_time - make_interval(secs => %[3]s) <= %[2]s AND
+				%[1]s <= b.scheduled_dropoff_time + make_interval(secs => %[3]s)
+		)`
+
+	vehicleBlackoutFreeCondition = `NOT EXISTS (
+			SELECT 1
+			FROM vehicle_blackouts AS vb
+			WHERE
+				v.id = vb.vehicle_id AND
+				vb.starts_at <= %[2]s AND %[1]s <= vb.ends_at
+		)`
+
+	// Great-circle distance in kilometres from the search point (%[1]s, %[2]s) to the vehicle.
+	vehicleDistanceExpression = `(2 * 6371 * ASIN(LEAST(1, SQRT(
+			POWER(SIN(RADIANS(v.latitude - %[1]s) / 2), 2) +
+			COS(RADIANS(%[1]s)) * COS(RADIANS(v.latitude)) * POWER(SIN(RADIANS(v.longitude - %[2]s) / 2), 2)
+		))))`
+
+	// Cities match on their prefix and names on the prefix of any word. Only bookable
+	// vehicles contribute, so every suggestion leads to at least one search result.
+	getVehicleSuggestionsQuery = `
+	SELECT kind, value
+	FROM (
+		SELECT DISTINCT ON (kind, lower(value)) kind, value
+		FROM (
+			SELECT 'city' AS kind, city AS value
+			FROM vehicles
+			WHERE is_deleted = false AND available = true AND lower(city) LIKE $1
+			UNION ALL
+			SELECT 'vehicle' AS kind, name AS value
+			FROM vehicles
+			WHERE is_deleted = false AND available = true AND (lower(name) LIKE $1 OR lower(name) LIKE $2)
+		) AS matches
+		ORDER BY kind, lower(value), value
+	) AS suggestions
+	ORDER BY kind, length(value), value
 	LIMIT $3;`
 )
 
@@ -173,26 +354,10 @@ func (vr *vehicleRepository) CreateVehicle(ctx context.Context, tx *sql.Tx, vehi
 		vehicleData.PinCode,
 		vehicleData.CancellationAllowed,
 		vehicleData.HostId,
-	).Scan(
-		&vehicle.Id,
-		&vehicle.Name,
-		&vehicle.FuelType,
-		&vehicle.SeatCount,
-		&vehicle.TransmissionType,
-		&vehicle.Features,
-		&vehicle.RatePerHour,
-		&vehicle.OverdueFeeRatePerHour,
-		&vehicle.Address,
-		&vehicle.State,
-		&vehicle.City,
-		&vehicle.PinCode,
-		&vehicle.CancellationAllowed,
-		&vehicle.Available,
-		&vehicle.HostId,
-		&vehicle.IsDeleted,
-		&vehicle.CreatedAt,
-		&vehicle.UpdatedAt,
-	)
+		vehicleData.Latitude,
+		vehicleData.Longitude,
+		vehicleData.CancellationPolicy,
+	).Scan(vehicleScanFields(&vehicle)...)
 	if err != nil {
 		slog.Error("failed to create vehicle", "error", err)
 		return Vehicle{}, apperrors.ErrInternalServer
@@ -221,26 +386,11 @@ func (vr *vehicleRepository) UpdateVehicle(ctx context.Context, tx *sql.Tx, vehi
 		vehicleData.PinCode,
 		vehicleData.CancellationAllowed,
 		vehicleData.Id,
-	).Scan(
-		&vehicle.Id,
-		&vehicle.Name,
-		&vehicle.FuelType,
-		&vehicle.SeatCount,
-		&vehicle.TransmissionType,
-		&vehicle.Features,
-		&vehicle.RatePerHour,
-		&vehicle.OverdueFeeRatePerHour,
-		&vehicle.Address,
-		&vehicle.State,
-		&vehicle.City,
-		&vehicle.PinCode,
-		&vehicle.CancellationAllowed,
-		&vehicle.Available,
-		&vehicle.HostId,
-		&vehicle.IsDeleted,
-		&vehicle.CreatedAt,
-		&vehicle.UpdatedAt,
-	)
+		vehicleData.HostId,
+		vehicleData.Latitude,
+		vehicleData.Longitude,
+		vehicleData.CancellationPolicy,
+	).Scan(vehicleScanFields(&vehicle)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Error("no vehicle found", "error", err)
@@ -253,18 +403,175 @@ func (vr *vehicleRepository) UpdateVehicle(ctx context.Context, tx *sql.Tx, vehi
 	return vehicle, nil
 }
 
-func (vr *vehicleRepository) SoftDeleteVehicle(ctx context.Context, tx *sql.Tx, vehicleId int) error {
+func (vr *vehicleRepository) SoftDeleteVehicle(ctx context.Context, tx *sql.Tx, vehicleId, hostId int) error {
 	executer := vr.initiateQueryExecuter(tx)
 
-	_, err := executer.ExecContext(ctx, softDeleteVehicleQuery, vehicleId)
+	result, err := executer.ExecContext(ctx, softDeleteVehicleQuery, vehicleId, hostId)
 	if err != nil {
 		slog.Error("failed to soft delete vehicle", "error", err)
 		return apperrors.ErrInternalServer
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get soft deleted vehicle count", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		slog.Error("no vehicle found to soft delete for host", "vehicleId", vehicleId, "hostId", hostId)
+		return apperrors.ErrVehicleNotFound
+	}
+
 	return nil
 }
 
+func (vr *vehicleRepository) VehicleOwnershipCheck(ctx context.Context, tx *sql.Tx, vehicleId, hostId int) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var vehicleHostId int
+	err := executer.QueryRowContext(ctx, getVehicleHostIdQuery, vehicleId).Scan(&vehicleHostId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("no vehicle found", "error", err)
+			return apperrors.ErrVehicleNotFound
+		}
+		slog.Error("failed to check vehicle ownership", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if vehicleHostId != hostId {
+		slog.Error("vehicle does not belong to host", "vehicleId", vehicleId, "hostId", hostId)
+		return apperrors.ErrActionForbidden
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) UpdateVehicleAvailability(ctx context.Context, tx *sql.Tx, vehicleId, hostId int, available bool) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, updateVehicleAvailabilityQuery, vehicleId, hostId, available)
+	if err != nil {
+		slog.Error("failed to update vehicle availability", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows for vehicle availability", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		slog.Error("no vehicle found to update availability for host", "vehicleId", vehicleId, "hostId", hostId)
+		return apperrors.ErrVehicleNotFound
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) CreateVehicleBlackout(ctx context.Context, tx *sql.Tx, blackoutData CreateVehicleBlackoutData) (VehicleBlackout, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var blackout VehicleBlackout
+	err := executer.QueryRowContext(
+		ctx,
+		createVehicleBlackoutQuery,
+		blackoutData.VehicleId,
+		blackoutData.StartsAt,
+		blackoutData.EndsAt,
+		blackoutData.Reason,
+	).Scan(
+		&blackout.Id,
+		&blackout.VehicleId,
+		&blackout.StartsAt,
+		&blackout.EndsAt,
+		&blackout.Reason,
+		&blackout.CreatedAt,
+	)
+	if err != nil {
+		slog.Error("failed to create vehicle blackout", "error", err)
+		return VehicleBlackout{}, apperrors.ErrInternalServer
+	}
+
+	return blackout, nil
+}
+
+func (vr *vehicleRepository) GetUpcomingVehicleBlackouts(ctx context.Context, tx *sql.Tx, vehicleId int) ([]VehicleBlackout, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var blackouts []VehicleBlackout
+	rows, err := executer.QueryContext(ctx, getUpcomingVehicleBlackoutsQuery, vehicleId)
+	if err != nil {
+		slog.Error("failed to get vehicle blackouts", "error", err)
+		return []VehicleBlackout{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var blackout VehicleBlackout
+		err = rows.Scan(
+			&blackout.Id,
+			&blackout.VehicleId,
+			&blackout.StartsAt,
+			&blackout.EndsAt,
+			&blackout.Reason,
+			&blackout.CreatedAt,
+		)
+		if err != nil {
+			slog.Error("failed to scan vehicle blackout from rows", "error", err)
+			return []VehicleBlackout{}, apperrors.ErrInternalServer
+		}
+		blackouts = append(blackouts, blackout)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed iterate over vehicle blackout rows", "error", err)
+		return []VehicleBlackout{}, apperrors.ErrInternalServer
+	}
+	return blackouts, nil
+}
+
+func (vr *vehicleRepository) DeleteVehicleBlackout(ctx context.Context, tx *sql.Tx, vehicleId, blackoutId int) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, deleteVehicleBlackoutQuery, blackoutId, vehicleId)
+	if err != nil {
+		slog.Error("failed to delete vehicle blackout", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows for vehicle blackout deletion", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.ErrVehicleBlackoutNotFound
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) BlackoutBookingConflictCheck(ctx context.Context, tx *sql.Tx, vehicleId int, startsAt, endsAt time.Time) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var flag int
+	err := executer.QueryRowContext(ctx, blackoutBookingConflictCheckQuery, vehicleId, startsAt, endsAt).Scan(&flag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		slog.Error("failed to check blackout booking conflict", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return apperrors.ErrBlackoutBookingConflict
+}
+
 func (vr *vehicleRepository) CreateVehicleImage(ctx context.Context, tx *sql.Tx, vehicleImageData CreateVehicleImageData) (VehicleImage, error) {
 	executer := vr.initiateQueryExecuter(tx)
 
@@ -275,13 +582,14 @@ func (vr *vehicleRepository) CreateVehicleImage(ctx context.Context, tx *sql.Tx,
 		vehicleImageData.VehicleId,
 		vehicleImageData.Url,
 		vehicleImageData.Featured,
-	).Scan(
-		&vehicleImage.Id,
-		&vehicleImage.VehicleId,
-		&vehicleImage.Url,
-		&vehicleImage.Featured,
-		&vehicleImage.CreatedAt,
-	)
+		vehicleImageData.ObjectPath,
+		vehicleImageData.UploadId,
+		vehicleImageData.ThumbnailPath,
+		vehicleImageData.MediumPath,
+		vehicleImageData.LargePath,
+		vehicleImageData.ProcessingStatus,
+		vehicleImageData.Position,
+	).Scan(vehicleImageScanFields(&vehicleImage)...)
 	if err != nil {
 		slog.Error("failed to create vehicle image", "error", err)
 		return VehicleImage{}, apperrors.ErrInternalServer
@@ -290,18 +598,121 @@ func (vr *vehicleRepository) CreateVehicleImage(ctx context.Context, tx *sql.Tx,
 	return vehicleImage, nil
 }
 
-func (vr *vehicleRepository) DeleteAllImagesForVehicle(ctx context.Context, tx *sql.Tx, vehicleId int) error {
+func (vr *vehicleRepository) LockVehicle(ctx context.Context, tx *sql.Tx, vehicleId int) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var id int
+	err := executer.QueryRowContext(ctx, lockVehicleQuery, vehicleId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("no vehicle found", "error", err)
+			return apperrors.ErrVehicleNotFound
+		}
+		slog.Error("failed to lock vehicle", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) GetVehicleImageById(ctx context.Context, tx *sql.Tx, vehicleId, imageId int) (VehicleImage, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var vehicleImage VehicleImage
+	err := executer.QueryRowContext(ctx, getVehicleImageByIdQuery, imageId, vehicleId).Scan(vehicleImageScanFields(&vehicleImage)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("no vehicle image found", "error", err)
+			return VehicleImage{}, apperrors.ErrVehicleImageNotFound
+		}
+		slog.Error("failed to get vehicle image", "error", err)
+		return VehicleImage{}, apperrors.ErrInternalServer
+	}
+
+	return vehicleImage, nil
+}
+
+func (vr *vehicleRepository) DeleteVehicleImage(ctx context.Context, tx *sql.Tx, vehicleId, imageId int) error {
 	executer := vr.initiateQueryExecuter(tx)
 
-	_, err := executer.ExecContext(ctx, deleteAllImagesForVehicleQuery, vehicleId)
+	_, err := executer.ExecContext(ctx, deleteVehicleImageQuery, imageId, vehicleId)
 	if err != nil {
-		slog.Error("failed to delete images for vehicle", "error", err)
+		slog.Error("failed to delete vehicle image", "error", err)
 		return apperrors.ErrInternalServer
 	}
 
 	return nil
 }
 
+func (vr *vehicleRepository) SetFeaturedVehicleImage(ctx context.Context, tx *sql.Tx, vehicleId, imageId int) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, clearFeaturedVehicleImageQuery, vehicleId)
+	if err != nil {
+		slog.Error("failed to clear featured vehicle image", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	result, err := executer.ExecContext(ctx, setFeaturedVehicleImageQuery, imageId, vehicleId)
+	if err != nil {
+		slog.Error("failed to set featured vehicle image", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows for featured vehicle image", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.ErrVehicleImageNotFound
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) UpdateVehicleImagePositions(ctx context.Context, tx *sql.Tx, vehicleId int, orderedImageIds []int) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateVehicleImagePositionsQuery, vehicleId, pq.Array(orderedImageIds))
+	if err != nil {
+		slog.Error("failed to update vehicle image positions", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) GetVehicleBusyIntervals(ctx context.Context, tx *sql.Tx, vehicleId int, from, to time.Time, bookingBuffer time.Duration) ([]BusyInterval, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var intervals []BusyInterval
+	rows, err := executer.QueryContext(ctx, getVehicleBusyIntervalsQuery, vehicleId, from, to, bookingBuffer.Seconds())
+	if err != nil {
+		slog.Error("failed to get vehicle busy intervals", "error", err)
+		return []BusyInterval{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var interval BusyInterval
+		err = rows.Scan(&interval.StartsAt, &interval.EndsAt)
+		if err != nil {
+			slog.Error("failed to scan vehicle busy interval from rows", "error", err)
+			return []BusyInterval{}, apperrors.ErrInternalServer
+		}
+		intervals = append(intervals, interval)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed iterate over vehicle busy interval rows", "error", err)
+		return []BusyInterval{}, apperrors.ErrInternalServer
+	}
+	return intervals, nil
+}
+
 func (vr *vehicleRepository) GetVehicleById(ctx context.Context, tx *sql.Tx, vehicleId int) (Vehicle, error) {
 	executer := vr.initiateQueryExecuter(tx)
 
@@ -310,26 +721,7 @@ func (vr *vehicleRepository) GetVehicleById(ctx context.Context, tx *sql.Tx, veh
 		ctx,
 		getVehicleByIdQuery,
 		vehicleId,
-	).Scan(
-		&vehicle.Id,
-		&vehicle.Name,
-		&vehicle.FuelType,
-		&vehicle.SeatCount,
-		&vehicle.TransmissionType,
-		&vehicle.Features,
-		&vehicle.RatePerHour,
-		&vehicle.OverdueFeeRatePerHour,
-		&vehicle.Address,
-		&vehicle.State,
-		&vehicle.City,
-		&vehicle.PinCode,
-		&vehicle.CancellationAllowed,
-		&vehicle.Available,
-		&vehicle.HostId,
-		&vehicle.IsDeleted,
-		&vehicle.CreatedAt,
-		&vehicle.UpdatedAt,
-	)
+	).Scan(vehicleScanFields(&vehicle)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Error("no vehicle found", "error", err)
@@ -342,6 +734,64 @@ func (vr *vehicleRepository) GetVehicleById(ctx context.Context, tx *sql.Tx, veh
 	return vehicle, nil
 }
 
+func (vr *vehicleRepository) GetVehicleRatePerHour(ctx context.Context, tx *sql.Tx, vehicleId int) (float64, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var ratePerHour float64
+	err := executer.QueryRowContext(ctx, getVehicleRatePerHourQuery, vehicleId).Scan(&ratePerHour)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("no vehicle found", "error", err)
+			return 0, apperrors.ErrVehicleNotFound
+		}
+		slog.Error("failed to get vehicle rate per hour", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return ratePerHour, nil
+}
+
+func (vr *vehicleRepository) GetVehiclesWithoutCoordinates(ctx context.Context, tx *sql.Tx, afterId, limit int) ([]Vehicle, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var vehicles []Vehicle
+	rows, err := executer.QueryContext(ctx, getVehiclesWithoutCoordinatesQuery, afterId, limit)
+	if err != nil {
+		slog.Error("failed to get vehicles without coordinates", "error", err)
+		return []Vehicle{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var vehicle Vehicle
+		err = rows.Scan(vehicleScanFields(&vehicle)...)
+		if err != nil {
+			slog.Error("failed to scan vehicle from rows", "error", err)
+			return []Vehicle{}, apperrors.ErrInternalServer
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed iterate over vehicle rows", "error", err)
+		return []Vehicle{}, apperrors.ErrInternalServer
+	}
+	return vehicles, nil
+}
+
+func (vr *vehicleRepository) UpdateVehicleCoordinates(ctx context.Context, tx *sql.Tx, vehicleId int, latitude, longitude float64) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateVehicleCoordinatesQuery, vehicleId, latitude, longitude)
+	if err != nil {
+		slog.Error("failed to update vehicle coordinates", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
 func (vr *vehicleRepository) GetVehicleImagesByVehicleId(ctx context.Context, tx *sql.Tx, vehicleId int) ([]VehicleImage, error) {
 	executer := vr.initiateQueryExecuter(tx)
 
@@ -355,7 +805,7 @@ func (vr *vehicleRepository) GetVehicleImagesByVehicleId(ctx context.Context, tx
 	defer rows.Close()
 	for rows.Next() {
 		var vehicleImage VehicleImage
-		err = rows.Scan(&vehicleImage.Id, &vehicleImage.VehicleId, &vehicleImage.Url, &vehicleImage.Featured, &vehicleImage.CreatedAt)
+		err = rows.Scan(vehicleImageScanFields(&vehicleImage)...)
 		if err != nil {
 			slog.Error("failed to scan vehicle image from rows", "error", err)
 			return []VehicleImage{}, apperrors.ErrInternalServer
@@ -371,98 +821,347 @@ func (vr *vehicleRepository) GetVehicleImagesByVehicleId(ctx context.Context, tx
 	return vehicleImages, nil
 }
 
-func (vr *vehicleRepository) GetVehicles(ctx context.Context, tx *sql.Tx, params GetVehiclesParams) ([]VehicleOverview, int, error) {
+func (vr *vehicleRepository) GetAllVehicleImages(ctx context.Context, tx *sql.Tx) ([]VehicleImage, error) {
 	executer := vr.initiateQueryExecuter(tx)
 
-	var vehicles []VehicleOverview
-	var totalCount int
-	rows, err := executer.QueryContext(
-		ctx,
-		getVehiclesQuery,
-		params.City,
-		params.PickupTimestamp,
-		params.DropoffTimestamp,
-		params.Offset,
-		params.Limit,
-	)
+	var vehicleImages []VehicleImage
+	rows, err := executer.QueryContext(ctx, getAllVehicleImagesQuery)
 	if err != nil {
-		slog.Error("failed to get vehicles", "error", err)
-		return []VehicleOverview{}, 0, apperrors.ErrInternalServer
+		slog.Error("failed to get all vehicle images", "error", err)
+		return []VehicleImage{}, apperrors.ErrInternalServer
 	}
 
 	defer rows.Close()
 	for rows.Next() {
-		var vehicleData VehicleOverview
-		err = rows.Scan(
-			&vehicleData.Id,
-			&vehicleData.Name,
-			&vehicleData.FuelType,
-			&vehicleData.SeatCount,
-			&vehicleData.TransmissionType,
-			&vehicleData.Image,
-			&vehicleData.RatePerHour,
-			&vehicleData.Address,
-			&vehicleData.PinCode,
-			&totalCount,
-		)
+		var vehicleImage VehicleImage
+		err = rows.Scan(vehicleImageScanFields(&vehicleImage)...)
 		if err != nil {
-			slog.Error("failed to scan vehicle overview from rows", "error", err)
-			return []VehicleOverview{}, 0, apperrors.ErrInternalServer
+			slog.Error("failed to scan vehicle image from rows", "error", err)
+			return []VehicleImage{}, apperrors.ErrInternalServer
 		}
-		vehicles = append(vehicles, vehicleData)
+		vehicleImages = append(vehicleImages, vehicleImage)
 	}
 
 	err = rows.Err()
 	if err != nil {
-		slog.Error("failed iterate over vehicle overview rows", "error", err)
-		return []VehicleOverview{}, 0, apperrors.ErrInternalServer
+		slog.Error("failed iterate over vehicle image rows", "error", err)
+		return []VehicleImage{}, apperrors.ErrInternalServer
 	}
-	return vehicles, totalCount, nil
+	return vehicleImages, nil
 }
 
-func (vr *vehicleRepository) GetVehiclesForHost(ctx context.Context, tx *sql.Tx, params GetVehiclesForHostParams) ([]VehicleOverview, int, error) {
+func (vr *vehicleRepository) UpdateVehicleImageLocation(ctx context.Context, tx *sql.Tx, imageId int, url string, objectPath string) error {
 	executer := vr.initiateQueryExecuter(tx)
 
-	var vehicles []VehicleOverview
-	var totalCount int
-	rows, err := executer.QueryContext(
-		ctx,
-		getVehiclesForHostQuery,
-		params.HostId,
-		params.Offset,
-		params.Limit,
+	_, err := executer.ExecContext(ctx, updateVehicleImageLocationQuery, imageId, url, objectPath)
+	if err != nil {
+		slog.Error("failed to update vehicle image location", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) GetVehicles(ctx context.Context, tx *sql.Tx, params GetVehiclesParams) ([]VehicleOverview, pagination.Info, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	vehicles, pageInfo, err := fetchPage(ctx, executer, buildGetVehiclesQuery(params), params.Page, scanVehicleOverview)
+	if err != nil {
+		slog.Error("failed to get vehicles", "error", err)
+		return []VehicleOverview{}, pagination.Info{}, err
+	}
+
+	return vehicles, pageInfo, nil
+}
+
+func scanVehicleOverview(rows *sql.Rows, key *[]byte) (VehicleOverview, error) {
+	var vehicleData VehicleOverview
+	err := rows.Scan(
+		&vehicleData.Id,
+		&vehicleData.Name,
+		&vehicleData.FuelType,
+		&vehicleData.SeatCount,
+		&vehicleData.TransmissionType,
+		&vehicleData.Image,
+		&vehicleData.RatePerHour,
+		&vehicleData.Address,
+		&vehicleData.PinCode,
+		&vehicleData.DistanceKm,
+		key,
 	)
+
+	return vehicleData, err
+}
+
+func addVehicleSearchFilters(qb *queryBuilder, filters VehicleSearchFilters) {
+	if len(filters.FuelTypes) > 0 {
+		qb.where("v.fuel_type = ANY(" + qb.arg(pq.Array(filters.FuelTypes)) + ")")
+	}
+
+	if len(filters.TransmissionTypes) > 0 {
+		qb.where("v.transmission_type = ANY(" + qb.arg(pq.Array(filters.TransmissionTypes)) + ")")
+	}
+
+	if filters.MinSeatCount > 0 {
+		qb.where("v.seat_count >= " + qb.arg(filters.MinSeatCount))
+	}
+
+	if filters.MinRatePerHour != nil {
+		qb.where("v.rate_per_hour >= " + qb.arg(*filters.MinRatePerHour))
+	}
+
+	if filters.MaxRatePerHour != nil {
+		qb.where("v.rate_per_hour <= " + qb.arg(*filters.MaxRatePerHour))
+	}
+
+	if filters.CancellationAllowed != nil {
+		qb.where("v.cancellation_allowed = " + qb.arg(*filters.CancellationAllowed))
+	}
+
+	// Features are stored either as a list of names or as an object of flags, so a vehicle
+	// matches when the list holds every name or every flag is set to true.
+	if len(filters.Features) > 0 {
+		flags := make(map[string]bool, len(filters.Features))
+		for _, feature := range filters.Features {
+			flags[feature] = true
+		}
+		flagsJSON, _ := json.Marshal(flags)
+		qb.where(fmt.Sprintf(
+			"(CASE jsonb_typeof(v.features) WHEN 'array' THEN v.features ?& %s::text[] ELSE v.features @> %s::jsonb END)",
+			qb.arg(pq.Array(filters.Features)),
+			qb.arg(string(flagsJSON)),
+		))
+	}
+}
+
+// buildGetVehiclesQuery lists bookable vehicles that are free for the whole pickup to dropoff
+// range, filtered by city and/or a search radius. Radius searches are sorted by distance.
+func buildGetVehiclesQuery(params GetVehiclesParams) listQuery {
+	var qb queryBuilder
+	pickup := qb.arg(params.PickupTimestamp)
+	dropoff := qb.arg(params.DropoffTimestamp)
+	bookingBuffer := qb.arg(params.BookingBuffer.Seconds())
+
+	qb.where("v.is_deleted = false")
+	qb.where("v.available = true")
+	qb.where(fmt.Sprintf(vehicleBookingFreeCondition, pickup, dropoff, bookingBuffer))
+	qb.where(fmt.Sprintf(vehicleBlackoutFreeCondition, pickup, dropoff))
+
+	// The search query is joined once so the match and the relevance sort share it.
+	searchQuery := ""
+	if len(params.SearchTerms) > 0 {
+		searchQuery = ", to_tsquery('simple', " + qb.arg(vehicleSearchTsQuery(params.SearchTerms)) + ") AS search_query"
+		qb.where("v.search_vector @@ search_query")
+	}
+
+	if params.City != "" {
+		qb.where("v.city ILIKE " + qb.arg(params.City))
+	}
+
+	addVehicleSearchFilters(&qb, params.Filters)
+
+	distance := "NULL::DOUBLE PRECISION"
+	if params.Near != nil {
+		latitude := qb.arg(params.Near.Latitude)
+		longitude := qb.arg(params.Near.Longitude)
+		distance = fmt.Sprintf(vehicleDistanceExpression, latitude, longitude)
+
+		// The bounding box only narrows candidates through the coordinates index, the distance
+		// check below is exact. Longitude bounds are skipped where they would wrap around.
+		latitudeDelta := params.Near.RadiusKm / kmPerLatitudeDegree
+		qb.where(fmt.Sprintf("v.latitude BETWEEN %s AND %s", qb.arg(params.Near.Latitude-latitudeDelta), qb.arg(params.Near.Latitude+latitudeDelta)))
+		longitudeScale := math.Cos(params.Near.Latitude * math.Pi / 180)
+		if longitudeScale > 0 {
+			longitudeDelta := latitudeDelta / longitudeScale
+			if params.Near.Longitude-longitudeDelta >= -180 && params.Near.Longitude+longitudeDelta <= 180 {
+				qb.where(fmt.Sprintf("v.longitude BETWEEN %s AND %s", qb.arg(params.Near.Longitude-longitudeDelta), qb.arg(params.Near.Longitude+longitudeDelta)))
+			}
+		}
+		qb.where(distance + " <= " + qb.arg(params.Near.RadiusKm))
+	}
+
+	// The service validates the sort order, this only guards against building a broken query.
+	order, ok := vehicleSortOrders[params.Sort]
+	switch {
+	case params.Sort == VehicleSortDistance && params.Near != nil:
+		order = sortOrder{
+			{expression: distance, kind: sortKeyNumeric},
+			{expression: "v.id", kind: sortKeyInt},
+		}
+	case !ok || (params.Sort == VehicleSortRelevance && searchQuery == ""):
+		order = vehicleSortOrders[VehicleSortNewest]
+	}
+
+	return listQuery{
+		columns: vehicleOverviewColumns + ",\n\t\t" + distance + " AS distance_km",
+		from:    "vehicles v" + searchQuery,
+		order:   order,
+		qb:      qb,
+	}
+}
+
+// vehicleSearchTsQuery requires every term and matches each as a prefix, so partially typed
+// words still find vehicles. Terms must only hold letters and digits, which tsquery treats
+// as plain lexemes.
+func vehicleSearchTsQuery(terms []string) string {
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = term + ":*"
+	}
+
+	return strings.Join(lexemes, " & ")
+}
+
+func (vr *vehicleRepository) GetVehicleSuggestions(ctx context.Context, tx *sql.Tx, prefix string, limit int) ([]VehicleSuggestion, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	pattern := escapeLikePattern(strings.ToLower(prefix))
+	rows, err := executer.QueryContext(ctx, getVehicleSuggestionsQuery, pattern+"%", "% "+pattern+"%", limit)
+	if err != nil {
+		slog.Error("failed to get vehicle suggestions", "error", err)
+		return []VehicleSuggestion{}, apperrors.ErrInternalServer
+	}
+
+	defer rows.Close()
+	suggestions := []VehicleSuggestion{}
+	for rows.Next() {
+		var suggestion VehicleSuggestion
+		err = rows.Scan(&suggestion.Kind, &suggestion.Value)
+		if err != nil {
+			slog.Error("failed to scan vehicle suggestion", "error", err)
+			return []VehicleSuggestion{}, apperrors.ErrInternalServer
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		slog.Error("failed to iterate over vehicle suggestion rows", "error", err)
+		return []VehicleSuggestion{}, apperrors.ErrInternalServer
+	}
+
+	return suggestions, nil
+}
+
+func (vr *vehicleRepository) GetVehiclesForHost(ctx context.Context, tx *sql.Tx, params GetVehiclesForHostParams) ([]VehicleOverview, pagination.Info, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var qb queryBuilder
+	qb.where("v.host_id = " + qb.arg(params.HostId))
+	qb.where("v.is_deleted = false")
+	lq := listQuery{
+		columns: vehicleOverviewColumns + ",\n\t\tNULL::DOUBLE PRECISION AS distance_km",
+		from:    "vehicles v",
+		order:   vehicleSortOrders[VehicleSortNewest],
+		qb:      qb,
+	}
+
+	vehicles, pageInfo, err := fetchPage(ctx, executer, lq, params.Page, scanVehicleOverview)
 	if err != nil {
 		slog.Error("failed to get vehicles for host", "error", err)
-		return []VehicleOverview{}, 0, apperrors.ErrInternalServer
+		return []VehicleOverview{}, pagination.Info{}, err
+	}
+
+	return vehicles, pageInfo, nil
+}
+
+func (vr *vehicleRepository) ClaimPendingVehicleImages(ctx context.Context, tx *sql.Tx, limit int, leaseDuration time.Duration) ([]VehicleImage, error) {
+	executer := vr.initiateQueryExecuter(tx)
+
+	var vehicleImages []VehicleImage
+	rows, err := executer.QueryContext(ctx, claimPendingVehicleImagesQuery, limit, leaseDuration.Seconds())
+	if err != nil {
+		slog.Error("failed to claim pending vehicle images", "error", err)
+		return []VehicleImage{}, apperrors.ErrInternalServer
 	}
 
 	defer rows.Close()
 	for rows.Next() {
-		var vehicleData VehicleOverview
-		err = rows.Scan(
-			&vehicleData.Id,
-			&vehicleData.Name,
-			&vehicleData.FuelType,
-			&vehicleData.SeatCount,
-			&vehicleData.TransmissionType,
-			&vehicleData.Image,
-			&vehicleData.RatePerHour,
-			&vehicleData.Address,
-			&vehicleData.PinCode,
-			&totalCount,
-		)
+		var vehicleImage VehicleImage
+		err = rows.Scan(vehicleImageScanFields(&vehicleImage)...)
 		if err != nil {
-			slog.Error("failed to scan vehicle overview from rows for host", "error", err)
-			return []VehicleOverview{}, 0, apperrors.ErrInternalServer
+			slog.Error("failed to scan vehicle image from rows", "error", err)
+			return []VehicleImage{}, apperrors.ErrInternalServer
 		}
-		vehicles = append(vehicles, vehicleData)
+		vehicleImages = append(vehicleImages, vehicleImage)
 	}
 
 	err = rows.Err()
 	if err != nil {
-		slog.Error("failed iterate over vehicle overview rows for host", "error", err)
-		return []VehicleOverview{}, 0, apperrors.ErrInternalServer
+		slog.Error("failed iterate over vehicle image rows", "error", err)
+		return []VehicleImage{}, apperrors.ErrInternalServer
+	}
+	return vehicleImages, nil
+}
+
+func (vr *vehicleRepository) MarkVehicleImageProcessed(ctx context.Context, tx *sql.Tx, imageId int, variants VehicleImageVariants) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markVehicleImageProcessedQuery, imageId, variants.ThumbnailPath, variants.MediumPath, variants.LargePath)
+	if err != nil {
+		slog.Error("failed to mark vehicle image as processed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (vr *vehicleRepository) MarkVehicleImageProcessingFailed(ctx context.Context, tx *sql.Tx, imageId int, status string, nextAttemptAt time.Time, processingError string) error {
+	executer := vr.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markVehicleImageProcessingFailedQuery, imageId, status, nextAttemptAt, processingError)
+	if err != nil {
+		slog.Error("failed to mark vehicle image processing as failed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// vehicleScanFields lists scan destinations in vehicleColumns order.
+func vehicleScanFields(vehicle *Vehicle) []any {
+	return []any{
+		&vehicle.Id,
+		&vehicle.Name,
+		&vehicle.FuelType,
+		&vehicle.SeatCount,
+		&vehicle.TransmissionType,
+		&vehicle.Features,
+		&vehicle.RatePerHour,
+		&vehicle.OverdueFeeRatePerHour,
+		&vehicle.Address,
+		&vehicle.State,
+		&vehicle.City,
+		&vehicle.PinCode,
+		&vehicle.CancellationAllowed,
+		&vehicle.Available,
+		&vehicle.HostId,
+		&vehicle.IsDeleted,
+		&vehicle.CreatedAt,
+		&vehicle.UpdatedAt,
+		&vehicle.Latitude,
+		&vehicle.Longitude,
+		&vehicle.CancellationPolicy,
+	}
+}
+
+// vehicleImageScanFields lists scan destinations in vehicle_images column order.
+func vehicleImageScanFields(vehicleImage *VehicleImage) []any {
+	return []any{
+		&vehicleImage.Id,
+		&vehicleImage.VehicleId,
+		&vehicleImage.Url,
+		&vehicleImage.Featured,
+		&vehicleImage.CreatedAt,
+		&vehicleImage.ObjectPath,
+		&vehicleImage.UploadId,
+		&vehicleImage.ThumbnailPath,
+		&vehicleImage.MediumPath,
+		&vehicleImage.LargePath,
+		&vehicleImage.ProcessingStatus,
+		&vehicleImage.ProcessingAttempts,
+		&vehicleImage.ProcessingNextAttemptAt,
+		&vehicleImage.ProcessingError,
+		&vehicleImage.Position,
 	}
-	return vehicles, totalCount, nil
 }